@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/extract-scope-go/go-scope/internal/extract"
+	extractformat "github.com/extract-scope-go/go-scope/internal/extract/format"
+	"github.com/extract-scope-go/go-scope/internal/rules"
+	"github.com/extract-scope-go/go-scope/internal/types"
+)
+
+// runCheck checks the module against its architecture rules, failing on violations
+func runCheck(args []string) error {
+	flags := flag.NewFlagSet("check", flag.ExitOnError)
+	var (
+		root   = flags.String("root", "", "Module root to check (default: working directory)")
+		config = flags.String("config", "", "Rules file (default: <root>/"+rules.DefaultFile+")")
+		format = flags.String("format", "text", "Output format: text, json")
+	)
+
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s check [options]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Check layer dependencies against %s and exit non-zero on violations.\n\n", rules.DefaultFile)
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if *root == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get working directory: %w", err)
+		}
+		*root = wd
+	}
+	if *config == "" {
+		*config = filepath.Join(*root, rules.DefaultFile)
+	}
+
+	cfg, err := rules.Load(*config)
+	if err != nil {
+		return err
+	}
+	if len(cfg.Layers) == 0 {
+		return fmt.Errorf("%s: no layers declared", *config)
+	}
+
+	ws, err := extract.LoadWorkspace(*root)
+	if err != nil {
+		return err
+	}
+
+	violations := ws.CheckArchitecture(cfg)
+
+	switch *format {
+	case "text":
+		fmt.Print(extractformat.ViolationsText(violations, *root))
+	case "json":
+		if violations == nil {
+			violations = []types.Violation{}
+		}
+		data, err := json.MarshalIndent(violations, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode violations: %w", err)
+		}
+		fmt.Println(string(data))
+	default:
+		return fmt.Errorf("unknown format: %s", *format)
+	}
+
+	if len(violations) > 0 {
+		return fmt.Errorf("%d architecture violation(s)", len(violations))
+	}
+	return nil
+}
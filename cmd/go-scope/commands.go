@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+)
+
+// subcommand is a go-scope command other than the default extraction
+type subcommand struct {
+	run     func(args []string) error
+	summary string
+}
+
+// subcommands are dispatched on the first command-line argument
+var subcommands = map[string]subcommand{
+	"check":           {runCheck, "Check architecture layering rules (exit 1 on violations)"},
+	"di":              {runDI, "Check and graph dependency injection wiring (di check, di graph)"},
+	"flow":            {runFlow, "Slice the data flow of a variable forward or backward"},
+	"implementations": {runImplementations, "List implementations of an interface, or interfaces of a type"},
+	"lsp":             {runLSP, "Serve the Language Server Protocol over stdio"},
+	"mcp":             {runMCP, "Serve the Model Context Protocol over stdio"},
+}
+
+// printCommands lists the subcommands for usage output
+func printCommands() {
+	names := make([]string, 0, len(subcommands))
+	for name := range subcommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-16s %s\n", name, subcommands[name].summary)
+	}
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/extract-scope-go/go-scope/internal/extract"
+	extractformat "github.com/extract-scope-go/go-scope/internal/extract/format"
+	"github.com/extract-scope-go/go-scope/internal/types"
+)
+
+// runDI dispatches the di subcommands
+func runDI(args []string) error {
+	usage := func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s di <command> [options]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Commands:\n")
+		fmt.Fprintf(os.Stderr, "  %-16s %s\n", "check", "Check DI container wiring (exit 1 on missing, duplicate or cyclic providers)")
+		fmt.Fprintf(os.Stderr, "  %-16s %s\n", "graph", "Show the object graph a container builds from main, an invoke or an injector")
+	}
+	if len(args) == 0 {
+		usage()
+		return fmt.Errorf("missing di command")
+	}
+
+	switch args[0] {
+	case "check":
+		return runDICheck(args[1:])
+	case "graph":
+		return runDIGraph(args[1:])
+	default:
+		usage()
+		return fmt.Errorf("unknown di command: %s", args[0])
+	}
+}
+
+// runDICheck validates the module's DI containers, failing on wiring errors
+func runDICheck(args []string) error {
+	flags := flag.NewFlagSet("di check", flag.ExitOnError)
+	var (
+		root   = flags.String("root", "", "Module root to check (default: working directory)")
+		format = flags.String("format", "text", "Output format: text, json")
+	)
+
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s di check [options]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Report values required but never provided, provided twice or never required,\n")
+		fmt.Fprintf(os.Stderr, "and dependency cycles in Wire, Fx, dig and samber/do containers. Exit non-zero\n")
+		fmt.Fprintf(os.Stderr, "on anything but unused providers.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if *root == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get working directory: %w", err)
+		}
+		*root = wd
+	}
+
+	ws, err := extract.LoadWorkspace(*root)
+	if err != nil {
+		return err
+	}
+
+	printWarnings(ws)
+
+	issues, err := ws.CheckDI()
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "text":
+		fmt.Print(extractformat.DIIssuesText(issues, *root))
+	case "json":
+		if issues == nil {
+			issues = []types.DIIssue{}
+		}
+		data, err := json.MarshalIndent(issues, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode DI issues: %w", err)
+		}
+		fmt.Println(string(data))
+	default:
+		return fmt.Errorf("unknown format: %s", *format)
+	}
+
+	errors := 0
+	for _, issue := range issues {
+		if issue.Kind != "unused" {
+			errors++
+		}
+	}
+	if errors > 0 {
+		return fmt.Errorf("%d DI wiring error(s)", errors)
+	}
+	return nil
+}
+
+// runDIGraph renders the object graphs built from an application root
+func runDIGraph(args []string) error {
+	flags := flag.NewFlagSet("di graph", flag.ExitOnError)
+	var (
+		root   = flags.String("root", "", "Module root (default: working directory)")
+		symbol = flags.String("symbol", "main", "main, or a function calling fx.New, an invoke or a Wire injector")
+		format = flags.String("format", "mermaid", "Output format: mermaid, dot, json")
+	)
+
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s di graph [options]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Show every object the DI container constructs for the invokes and Wire injectors\n")
+		fmt.Fprintf(os.Stderr, "a function is, declares or calls: providers, products, scopes and parameters,\n")
+		fmt.Fprintf(os.Stderr, "with the implementation behind interface parameters.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if *root == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get working directory: %w", err)
+		}
+		*root = wd
+	}
+
+	ws, err := extract.LoadWorkspace(*root)
+	if err != nil {
+		return err
+	}
+
+	printWarnings(ws)
+
+	graphs, err := ws.DIObjectGraphs(*symbol)
+	if err != nil {
+		return err
+	}
+	if len(graphs) == 0 {
+		return fmt.Errorf("%s builds no DI container graph", *symbol)
+	}
+
+	switch *format {
+	case "mermaid":
+		fmt.Print(extractformat.DIObjectGraphsMermaid(graphs))
+	case "dot":
+		fmt.Print(extractformat.DIObjectGraphsDOT(graphs))
+	case "json":
+		data, err := json.MarshalIndent(graphs, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode DI graphs: %w", err)
+		}
+		fmt.Println(string(data))
+	default:
+		return fmt.Errorf("unknown format: %s", *format)
+	}
+	return nil
+}
+
+// printWarnings reports problems that left the workspace's DI analysis incomplete
+func printWarnings(ws *extract.Workspace) {
+	for _, warning := range ws.Warnings() {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
+}
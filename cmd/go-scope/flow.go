@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/extract-scope-go/go-scope/internal/extract"
+	extractformat "github.com/extract-scope-go/go-scope/internal/extract/format"
+	"github.com/extract-scope-go/go-scope/internal/types"
+)
+
+// runFlow slices the data flow of a variable or parameter
+func runFlow(args []string) error {
+	flags := flag.NewFlagSet("flow", flag.ExitOnError)
+	var (
+		root      = flags.String("root", "", "Module root (default: working directory)")
+		file      = flags.String("file", "", "Source file (required)")
+		line      = flags.Int("line", 0, "Line of the variable (required)")
+		column    = flags.Int("column", 1, "Column of the variable (default: first variable on the line)")
+		direction = flags.String("direction", "forward", "Direction: forward, backward, both")
+		depth     = flags.Int("depth", 2, "Function boundaries to follow the value across")
+		format    = flags.String("format", "markdown", "Output format: markdown, json")
+	)
+
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s flow -file=<file> -line=<line> [options]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Slice the statements and functions a variable's value flows to or comes from.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if *file == "" || *line == 0 {
+		flags.Usage()
+		return fmt.Errorf("-file and -line are required")
+	}
+	if *root == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get working directory: %w", err)
+		}
+		*root = wd
+	}
+	if !filepath.IsAbs(*file) {
+		if _, err := os.Stat(*file); err != nil {
+			*file = filepath.Join(*root, *file)
+		}
+	}
+
+	ws, err := extract.LoadWorkspace(*root)
+	if err != nil {
+		return err
+	}
+
+	target := types.Target{Root: *root, File: *file, Line: *line, Column: *column}
+	flow, err := ws.DataFlow(target, *direction, *depth)
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "markdown":
+		fmt.Print(extractformat.DataFlowMarkdown(flow, *root))
+	case "json":
+		data, err := json.MarshalIndent(flow, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode data flow: %w", err)
+		}
+		fmt.Println(string(data))
+	default:
+		return fmt.Errorf("unknown format: %s", *format)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/extract-scope-go/go-scope/internal/extract"
+	extractformat "github.com/extract-scope-go/go-scope/internal/extract/format"
+	"github.com/extract-scope-go/go-scope/internal/types"
+)
+
+// runImplementations queries the module-wide implementation index
+func runImplementations(args []string) error {
+	flags := flag.NewFlagSet("implementations", flag.ExitOnError)
+	var (
+		root   = flags.String("root", "", "Module root (default: working directory)")
+		symbol = flags.String("symbol", "", "Interface or concrete type: Name, pkg.Name or import/path.Name (required)")
+		format = flags.String("format", "text", "Output format: text, json")
+	)
+
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s implementations -symbol=<type> [options]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "List the module's types implementing an interface, or the interfaces a type implements.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if *symbol == "" {
+		flags.Usage()
+		return fmt.Errorf("-symbol is required")
+	}
+	if *root == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get working directory: %w", err)
+		}
+		*root = wd
+	}
+
+	ws, err := extract.LoadWorkspace(*root)
+	if err != nil {
+		return err
+	}
+
+	sym, impls, err := ws.Implementations(*symbol)
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "text":
+		fmt.Print(extractformat.ImplementationsText(sym, impls, *root))
+	case "json":
+		if impls == nil {
+			impls = []types.Implementation{}
+		}
+		data, err := json.MarshalIndent(impls, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode implementations: %w", err)
+		}
+		fmt.Println(string(data))
+	default:
+		return fmt.Errorf("unknown format: %s", *format)
+	}
+	return nil
+}
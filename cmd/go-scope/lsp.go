@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/extract-scope-go/go-scope/internal/jsonrpc"
+	"github.com/extract-scope-go/go-scope/internal/lsp"
+	"github.com/extract-scope-go/go-scope/internal/types"
+)
+
+// runLSP serves the Language Server Protocol over stdin/stdout
+func runLSP(args []string) error {
+	flags := flag.NewFlagSet("lsp", flag.ExitOnError)
+	var (
+		depth     = flags.Int("depth", 1, "Dependency depth for \"Extract scope here\"")
+		maxTokens = flags.Int("max-tokens", 0, "Trim extracts to about this many LLM tokens (0=unlimited)")
+		fullDepth = flags.Int("full-depth", 0, "Show full code up to this depth, signatures beyond (0=all depths)")
+		stubDepth = flags.Int("stub-depth", 0, "Show signatures up to this depth, names only beyond (0=all depths)")
+	)
+
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s lsp [options]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Run as a language server alongside gopls. Offers an \"Extract scope here\"\n")
+		fmt.Fprintf(os.Stderr, "code action (command %q) and hover cards with dependencies.\n\n", lsp.ExtractCommand)
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if *fullDepth > 0 && *stubDepth > 0 && *stubDepth < *fullDepth {
+		return fmt.Errorf("-stub-depth (%d) must not be lower than -full-depth (%d)", *stubDepth, *fullDepth)
+	}
+
+	opts := types.Options{
+		Depth:     *depth,
+		Annotate:  true,
+		MaxTokens: *maxTokens,
+		FullDepth: *fullDepth,
+		StubDepth: *stubDepth,
+	}
+
+	server := lsp.NewServer(jsonrpc.NewHeaderStream(os.Stdin, os.Stdout), opts)
+	return server.Run(context.Background())
+}
@@ -8,24 +8,47 @@ import (
 	"path/filepath"
 
 	"github.com/extract-scope-go/go-scope/internal/extract"
+	extractformat "github.com/extract-scope-go/go-scope/internal/extract/format"
 	"github.com/extract-scope-go/go-scope/internal/types"
 )
 
 func main() {
+	// Subcommands take over the command line; plain flags run an extraction
+	if len(os.Args) > 1 {
+		if cmd, ok := subcommands[os.Args[1]]; ok {
+			if err := cmd.run(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
 	// Define flags
 	var (
 		file    = flag.String("file", "", "Source file to extract from (required)")
 		line    = flag.Int("line", 0, "Line number of target symbol (required)")
 		col     = flag.Int("col", 1, "Column number (default: 1)")
 		depth   = flag.Int("depth", 1, "Dependency depth (0=target only, 1=direct deps, etc)")
-		format  = flag.String("format", "markdown", "Output format: markdown, json, html")
+		format  = flag.String("format", "markdown", "Output format: markdown, json, result, html")
 		output  = flag.String("output", "", "Output file (default: stdout)")
 		verbose = flag.Bool("verbose", false, "Show verbose output")
+		schema  = flag.Bool("schema", false, "Print the JSON Schema for -format=result and exit")
+
+		maxTokens = flag.Int("max-tokens", 0, "Trim output to about this many LLM tokens (0=unlimited)")
+		maxBytes  = flag.Int("max-bytes", 0, "Trim output to at most this many bytes (0=unlimited)")
+		fullDepth = flag.Int("full-depth", 0, "Show full code up to this depth, signatures beyond (0=all depths)")
+		stubDepth = flag.Int("stub-depth", 0, "Show signatures up to this depth, names only beyond (0=all depths)")
+		taint     = flag.Bool("taint", false, "Report untrusted input reaching SQL, commands, templates or file paths through the target")
 	)
 
 	flag.Usage = func() {
-		fmt.Fprintf(os.Stderr, "Usage: %s [options]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Usage: %s [options]\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "       %s <command> [options]\n\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "Extract Go code with dependencies for review and understanding.\n\n")
+		fmt.Fprintf(os.Stderr, "Commands:\n")
+		printCommands()
+		fmt.Fprintf(os.Stderr, "\n")
 		fmt.Fprintf(os.Stderr, "Options:\n")
 		flag.PrintDefaults()
 		fmt.Fprintf(os.Stderr, "\nExamples:\n")
@@ -35,15 +58,32 @@ func main() {
 		fmt.Fprintf(os.Stderr, "  %s -file=pkg/math/add.go -line=42 -depth=2\n\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  # Save output to file\n")
 		fmt.Fprintf(os.Stderr, "  %s -file=pkg/math/add.go -line=42 -output=extract.md\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  # Full code for direct deps, contracts to depth 3, names beyond\n")
+		fmt.Fprintf(os.Stderr, "  %s -file=pkg/math/add.go -line=42 -depth=4 -full-depth=1 -stub-depth=3\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  # Fit a depth-2 extract into an LLM prompt\n")
+		fmt.Fprintf(os.Stderr, "  %s -file=pkg/math/add.go -line=42 -depth=2 -max-tokens=8000\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  # Review where request, environment and file input reaches sinks through a handler\n")
+		fmt.Fprintf(os.Stderr, "  %s -file=internal/server/http.go -line=26 -taint\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  # Save the full, versioned result for other tools\n")
+		fmt.Fprintf(os.Stderr, "  %s -file=pkg/math/add.go -line=42 -format=result -output=extract.json\n\n", os.Args[0])
 	}
 
 	flag.Parse()
 
+	if *schema {
+		fmt.Println(extractformat.JSONSchema())
+		return
+	}
+
 	// Validate required flags
 	if *file == "" || *line == 0 {
 		flag.Usage()
 		os.Exit(1)
 	}
+	if *fullDepth > 0 && *stubDepth > 0 && *stubDepth < *fullDepth {
+		fmt.Fprintf(os.Stderr, "Error: -stub-depth (%d) must not be lower than -full-depth (%d)\n", *stubDepth, *fullDepth)
+		os.Exit(1)
+	}
 
 	// Get current working directory as root
 	root, err := os.Getwd()
@@ -75,8 +115,13 @@ func main() {
 	}
 
 	opts := types.Options{
-		Depth:  *depth,
-		Format: *format,
+		Depth:     *depth,
+		Format:    *format,
+		MaxTokens: *maxTokens,
+		MaxBytes:  *maxBytes,
+		FullDepth: *fullDepth,
+		StubDepth: *stubDepth,
+		Taint:     *taint,
 	}
 
 	// Extract and format
@@ -105,5 +150,7 @@ func main() {
 	if *verbose && *output != "" {
 		fmt.Fprintf(os.Stderr, "Output written to: %s\n", *output)
 		fmt.Fprintf(os.Stderr, "Total symbols: %d\n", result.Metadata.TotalSymbols)
+		fmt.Fprintf(os.Stderr, "Total lines: %d\n", result.Metadata.TotalLines)
+		fmt.Fprintf(os.Stderr, "Extraction time: %s\n", result.Metadata.Timings.Total)
 	}
 }
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/extract-scope-go/go-scope/internal/extract"
+	"github.com/extract-scope-go/go-scope/internal/jsonrpc"
+	"github.com/extract-scope-go/go-scope/internal/mcp"
+	"github.com/extract-scope-go/go-scope/internal/types"
+)
+
+// runMCP serves the Model Context Protocol over stdin/stdout
+func runMCP(args []string) error {
+	flags := flag.NewFlagSet("mcp", flag.ExitOnError)
+	var (
+		root      = flags.String("root", "", "Module root to serve (default: working directory)")
+		depth     = flags.Int("depth", 1, "Default dependency depth for extract_symbol")
+		maxTokens = flags.Int("max-tokens", 0, "Default token budget for extract_symbol (0=unlimited)")
+	)
+
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s mcp [options]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Run as an MCP server for AI assistants. The module is loaded once and kept\n")
+		fmt.Fprintf(os.Stderr, "warm; tools: extract_symbol, find_callers, list_implementations,\n")
+		fmt.Fprintf(os.Stderr, "search_symbols, di_graph.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if *root == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get working directory: %w", err)
+		}
+		*root = wd
+	}
+
+	ws, err := extract.LoadWorkspace(*root)
+	if err != nil {
+		return err
+	}
+
+	opts := types.Options{
+		Depth:     *depth,
+		Annotate:  true,
+		MaxTokens: *maxTokens,
+	}
+
+	server := mcp.NewServer(jsonrpc.NewLineStream(os.Stdin, os.Stdout), ws, opts)
+	return server.Run(context.Background())
+}
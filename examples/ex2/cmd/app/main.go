@@ -0,0 +1,31 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"os"
+
+	"example.com/ex2/internal/adapters/email"
+	"example.com/ex2/internal/adapters/memory"
+	"example.com/ex2/internal/adapters/rest"
+	"example.com/ex2/internal/app"
+	"example.com/ex2/internal/domain"
+)
+
+func main() {
+	repo := memory.NewRepository()
+	notifier := email.NewNotifier("noreply@example.com")
+	service := app.NewUserService(repo, notifier)
+
+	if err := repo.Save(&domain.User{ID: "1", Name: "Ada"}); err != nil {
+		log.Fatal(err)
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		log.Fatal(http.ListenAndServe(":8080", rest.WithRequestLog(rest.NewHandler(service))))
+	}
+
+	if err := service.Rename("1", "Grace"); err != nil {
+		log.Fatal(err)
+	}
+}
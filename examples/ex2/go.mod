@@ -0,0 +1,3 @@
+module example.com/ex2
+
+go 1.22
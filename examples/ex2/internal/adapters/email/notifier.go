@@ -0,0 +1,23 @@
+package email
+
+import (
+	"fmt"
+
+	"example.com/ex2/internal/domain"
+)
+
+// Notifier sends notifications by email
+type Notifier struct {
+	from string
+}
+
+// NewNotifier creates a Notifier sending from the given address
+func NewNotifier(from string) *Notifier {
+	return &Notifier{from: from}
+}
+
+// Notify emails a message to the user
+func (n *Notifier) Notify(u *domain.User, msg string) error {
+	fmt.Printf("From: %s\nTo: %s\n\n%s\n", n.from, u.Name, msg)
+	return nil
+}
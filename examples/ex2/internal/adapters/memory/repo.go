@@ -0,0 +1,28 @@
+package memory
+
+import "example.com/ex2/internal/domain"
+
+// Repository keeps users in memory
+type Repository struct {
+	users map[string]*domain.User
+}
+
+// NewRepository creates an empty Repository
+func NewRepository() *Repository {
+	return &Repository{users: make(map[string]*domain.User)}
+}
+
+// Get returns the user with the given ID
+func (r *Repository) Get(id string) (*domain.User, error) {
+	u, ok := r.users[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return u, nil
+}
+
+// Save stores a user
+func (r *Repository) Save(u *domain.User) error {
+	r.users[u.ID] = u
+	return nil
+}
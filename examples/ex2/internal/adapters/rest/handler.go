@@ -0,0 +1,27 @@
+package rest
+
+import (
+	"net/http"
+
+	"example.com/ex2/internal/app"
+)
+
+// Handler serves the user API
+type Handler struct {
+	users app.Renamer
+}
+
+// NewHandler creates a Handler for the rename use case
+func NewHandler(users app.Renamer) *Handler {
+	return &Handler{users: users}
+}
+
+// ServeHTTP renames the user given by the id and name query parameters
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if err := h.users.Rename(query.Get("id"), query.Get("name")); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
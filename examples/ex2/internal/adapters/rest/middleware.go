@@ -0,0 +1,35 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// RequestLog times a single request
+type RequestLog struct {
+	path  string
+	start time.Time
+}
+
+// NewRequestLog starts the log of a request
+func NewRequestLog(r *http.Request) *RequestLog {
+	return &RequestLog{path: r.URL.Path, start: time.Now()}
+}
+
+// Close ends the log once the request is served
+func (l *RequestLog) Close() error {
+	return nil
+}
+
+// logKey is the context key of a request's log
+type logKey struct{}
+
+// WithRequestLog gives every request its own log
+func WithRequestLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		log := NewRequestLog(r)
+		defer log.Close()
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), logKey{}, log)))
+	})
+}
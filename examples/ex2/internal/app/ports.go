@@ -0,0 +1,6 @@
+package app
+
+// Renamer is the rename use case, as driven by primary adapters
+type Renamer interface {
+	Rename(id, name string) error
+}
@@ -0,0 +1,29 @@
+package app
+
+import "example.com/ex2/internal/domain"
+
+// UserService implements the user use cases
+type UserService struct {
+	repo     domain.UserRepository
+	notifier domain.Notifier
+}
+
+// NewUserService creates a UserService
+func NewUserService(repo domain.UserRepository, notifier domain.Notifier) *UserService {
+	return &UserService{repo: repo, notifier: notifier}
+}
+
+// Rename changes a user's name and notifies them
+func (s *UserService) Rename(id, name string) error {
+	u, err := s.repo.Get(id)
+	if err != nil {
+		return err
+	}
+
+	u.Name = name
+	if err := s.repo.Save(u); err != nil {
+		return err
+	}
+
+	return s.notifier.Notify(u, "your name was changed")
+}
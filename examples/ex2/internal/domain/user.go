@@ -0,0 +1,23 @@
+package domain
+
+import "errors"
+
+// ErrNotFound is returned when a user does not exist
+var ErrNotFound = errors.New("user not found")
+
+// User is a registered user
+type User struct {
+	ID   string
+	Name string
+}
+
+// UserRepository stores users
+type UserRepository interface {
+	Get(id string) (*User, error)
+	Save(u *User) error
+}
+
+// Notifier tells users about changes to their account
+type Notifier interface {
+	Notify(u *User, msg string) error
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+
+	"example.com/ex3/pkg/memory"
+	"example.com/ex3/pkg/store"
+)
+
+func main() {
+	m := memory.NewMap()
+	m.Put("greeting", "hello")
+
+	var r store.Reader = memory.ReadOnly{Reader: m}
+	fmt.Println(store.Lookup(r, "greeting", "?"))
+	fmt.Println(store.Lookup(memory.Static("fixed"), "any", "?"))
+
+	var loader store.Loader[int] = memory.NewCache[int]()
+	_, ok := loader.Load("missing")
+	fmt.Println(ok)
+
+	preloaded, err := openStore()
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(store.Lookup(preloaded, "greeting", "?"))
+}
+
+// openStore opens a Map holding a greeting
+func openStore() (store.ReadWriter, error) {
+	return memory.Open(memory.WithValue("greeting", "hello"))
+}
@@ -0,0 +1,3 @@
+module example.com/ex3
+
+go 1.22
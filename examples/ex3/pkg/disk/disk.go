@@ -0,0 +1,24 @@
+package disk
+
+import "os"
+
+// Disk stores values as files in a directory. It was meant to be a
+// store.ReadWriter but has drifted from the interface.
+type Disk struct {
+	dir string
+}
+
+// NewDisk creates a Disk rooted at dir
+func NewDisk(dir string) *Disk {
+	return &Disk{dir: dir}
+}
+
+// Get reads the file for key
+func (d *Disk) Get(key string) ([]byte, error) {
+	return os.ReadFile(d.dir + "/" + key)
+}
+
+// Put writes the file for key
+func (d *Disk) Put(key, value string) {
+	_ = os.WriteFile(d.dir+"/"+key, []byte(value), 0o644)
+}
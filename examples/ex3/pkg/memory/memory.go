@@ -0,0 +1,53 @@
+package memory
+
+import "example.com/ex3/pkg/store"
+
+// Map is a ReadWriter backed by a map
+type Map struct {
+	values map[string]string
+}
+
+// NewMap creates an empty Map
+func NewMap() *Map {
+	return &Map{values: make(map[string]string)}
+}
+
+// Get returns the value for key
+func (m *Map) Get(key string) (string, bool) {
+	value, ok := m.values[key]
+	return value, ok
+}
+
+// Put sets the value for key
+func (m *Map) Put(key, value string) {
+	m.values[key] = value
+}
+
+// Static answers every key with the same value
+type Static string
+
+// Get returns the static value
+func (s Static) Get(key string) (string, bool) {
+	return string(s), true
+}
+
+// ReadOnly exposes only the Reader side of a store
+type ReadOnly struct {
+	store.Reader
+}
+
+// Cache holds typed values
+type Cache[V any] struct {
+	items map[string]V
+}
+
+// NewCache creates an empty Cache
+func NewCache[V any]() *Cache[V] {
+	return &Cache[V]{items: make(map[string]V)}
+}
+
+// Load returns the cached value for key
+func (c *Cache[V]) Load(key string) (V, bool) {
+	value, ok := c.items[key]
+	return value, ok
+}
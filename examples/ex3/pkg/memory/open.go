@@ -0,0 +1,42 @@
+package memory
+
+import "example.com/ex3/pkg/store"
+
+// Option configures a Map opened with Open
+type Option func(*Map)
+
+// WithValues preloads several keys
+func WithValues(values map[string]string) Option {
+	return func(m *Map) {
+		for key, value := range values {
+			m.values[key] = value
+		}
+	}
+}
+
+// WithValue preloads a key
+func WithValue(key, value string) Option {
+	return func(m *Map) {
+		m.values[key] = value
+	}
+}
+
+// Open creates a Map configured by options
+func Open(opts ...Option) (store.ReadWriter, error) {
+	m := &Map{values: make(map[string]string)}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m, nil
+}
+
+// Factory builds read-only stores
+type Factory struct {
+	Fallback string
+}
+
+// Reader returns a Reader answering every key with the fallback
+func (f Factory) Reader() store.Reader {
+	var r store.Reader = Static(f.Fallback)
+	return r
+}
@@ -0,0 +1,36 @@
+package store
+
+// Reader looks up values by key
+type Reader interface {
+	Get(key string) (string, bool)
+}
+
+// Writer stores values by key
+type Writer interface {
+	Put(key, value string)
+}
+
+// ReadWriter combines Reader and Writer
+type ReadWriter interface {
+	Reader
+	Writer
+}
+
+// Store is a ReadWriter that can also forget keys
+type Store interface {
+	ReadWriter
+	Delete(key string)
+}
+
+// Loader is a typed lookup, implemented by generic caches
+type Loader[V any] interface {
+	Load(key string) (V, bool)
+}
+
+// Lookup reads a key or returns a fallback
+func Lookup(r Reader, key, fallback string) string {
+	if value, ok := r.Get(key); ok {
+		return value
+	}
+	return fallback
+}
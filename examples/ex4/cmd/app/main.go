@@ -0,0 +1,23 @@
+package main
+
+import (
+	"example.com/ex4/internal/config"
+	"example.com/ex4/internal/db"
+	"example.com/ex4/internal/server"
+	"example.com/ex4/internal/users"
+	"go.uber.org/fx"
+)
+
+func main() {
+	fx.New(
+		fx.Supply(config.Config{Addr: ":8080", DSN: "postgres://localhost/app"}),
+		db.Module,
+		users.Module,
+		fx.Provide(
+			fx.Annotate(server.NewServer, fx.ParamTags(``, `group:"routes"`)),
+		),
+		fx.Invoke(func(lc fx.Lifecycle, s *server.Server) {
+			lc.Append(fx.StartHook(s.Start))
+		}),
+	).Run()
+}
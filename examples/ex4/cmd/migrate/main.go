@@ -0,0 +1,22 @@
+package main
+
+import (
+	"log"
+
+	"example.com/ex4/internal/config"
+	"example.com/ex4/internal/db"
+	"go.uber.org/fx"
+)
+
+func main() {
+	fx.New(
+		fx.Supply(config.Config{DSN: "postgres://localhost/app"}),
+		db.Module,
+		fx.Invoke(migrate),
+	).Run()
+}
+
+// migrate brings the database schema up to date
+func migrate(conn *db.Conn) {
+	log.Printf("migrating %p", conn)
+}
@@ -0,0 +1,8 @@
+module example.com/ex4
+
+go 1.22
+
+require go.uber.org/fx v1.22.0
+
+// A minimal stand-in with fx's API, so the example builds offline
+replace go.uber.org/fx => ./third_party/fx
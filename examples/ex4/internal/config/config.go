@@ -0,0 +1,7 @@
+package config
+
+// Config holds the application settings
+type Config struct {
+	Addr string
+	DSN  string
+}
@@ -0,0 +1,38 @@
+package db
+
+import (
+	"context"
+
+	"example.com/ex4/internal/config"
+	"go.uber.org/fx"
+)
+
+// Module provides the database connection
+var Module = fx.Module("db",
+	fx.Provide(NewConn),
+)
+
+// Conn is a database connection
+type Conn struct {
+	dsn  string
+	open bool
+}
+
+// NewConn opens a connection when the app starts and closes it on stop
+func NewConn(cfg config.Config, lc fx.Lifecycle) (*Conn, error) {
+	if cfg.DSN == "" {
+		cfg.DSN = "postgres://localhost/dev"
+	}
+	conn := &Conn{dsn: cfg.DSN}
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			conn.open = true
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			conn.open = false
+			return nil
+		},
+	})
+	return conn, nil
+}
@@ -0,0 +1,22 @@
+package server
+
+import (
+	"example.com/ex4/internal/config"
+	"example.com/ex4/internal/users"
+)
+
+// Server serves every registered route
+type Server struct {
+	addr   string
+	routes []users.Handler
+}
+
+// NewServer creates a Server for the "routes" group
+func NewServer(cfg config.Config, routes []users.Handler) *Server {
+	return &Server{addr: cfg.Addr, routes: routes}
+}
+
+// Start starts listening
+func (s *Server) Start() error {
+	return nil
+}
@@ -0,0 +1,106 @@
+package users
+
+import (
+	"example.com/ex4/internal/db"
+	"go.uber.org/fx"
+)
+
+// Module provides the user store, service and routes
+var Module = fx.Module("users",
+	fx.Provide(
+		fx.Annotate(NewStore, fx.As(new(Repository))),
+		fx.Annotate(NewCache, fx.ResultTags(`name:"cache"`), fx.As(new(Repository))),
+		fx.Private,
+		NewService,
+		NewRoutes,
+	),
+	fx.Decorate(WithAudit),
+)
+
+// Repository finds users
+type Repository interface {
+	Find(id string) (string, bool)
+}
+
+// Handler serves one route
+type Handler interface {
+	Pattern() string
+}
+
+// Store is the database-backed Repository
+type Store struct {
+	conn *db.Conn
+}
+
+// NewStore creates a Store
+func NewStore(conn *db.Conn) *Store {
+	return &Store{conn: conn}
+}
+
+// Find looks up a user
+func (s *Store) Find(id string) (string, bool) {
+	return "", false
+}
+
+// Cache is an in-memory Repository
+type Cache struct {
+	users map[string]string
+}
+
+// NewCache creates an empty Cache
+func NewCache() *Cache {
+	return &Cache{users: make(map[string]string)}
+}
+
+// Find looks up a cached user
+func (c *Cache) Find(id string) (string, bool) {
+	name, ok := c.users[id]
+	return name, ok
+}
+
+// Service answers user queries
+type Service struct {
+	repo    Repository
+	cache   Repository
+	audited bool
+}
+
+// ServiceParams are the Service's dependencies
+type ServiceParams struct {
+	fx.In
+
+	Repo  Repository
+	Cache Repository `name:"cache" optional:"true"`
+}
+
+// NewService creates a Service
+func NewService(p ServiceParams) *Service {
+	return &Service{repo: p.Repo, cache: p.Cache}
+}
+
+// WithAudit decorates the Service with auditing
+func WithAudit(s *Service) *Service {
+	s.audited = true
+	return s
+}
+
+// Routes are the user routes, contributed to the "routes" group
+type Routes struct {
+	fx.Out
+
+	Get  Handler `group:"routes"`
+	List Handler `group:"routes"`
+}
+
+// route is a Handler for one pattern
+type route string
+
+// Pattern returns the route's pattern
+func (r route) Pattern() string {
+	return string(r)
+}
+
+// NewRoutes creates the user routes
+func NewRoutes(s *Service) Routes {
+	return Routes{Get: route("/users/{id}"), List: route("/users")}
+}
@@ -0,0 +1,88 @@
+// A stand-in for the parts of go.uber.org/fx's API used by the example. It
+// type-checks like the real library but does not run anything.
+
+package fx
+
+import "context"
+
+// Option configures an App
+type Option interface{ apply() }
+
+type option struct{}
+
+func (option) apply() {}
+
+// Annotation annotates a constructor passed to Annotate
+type Annotation interface{ annotation() }
+
+type annotation struct{}
+
+func (annotation) annotation() {}
+
+// In marks a parameter struct whose fields are dependencies
+type In struct{}
+
+// Out marks a result struct whose fields are provided values
+type Out struct{}
+
+// Hook is a pair of lifecycle callbacks
+type Hook struct {
+	OnStart func(context.Context) error
+	OnStop  func(context.Context) error
+}
+
+// Lifecycle registers hooks run when the App starts and stops
+type Lifecycle interface {
+	Append(Hook)
+}
+
+// App is a dependency injection container
+type App struct{}
+
+// Private makes a Provide only visible within its Module
+var Private = option{}
+
+// New creates an App from options
+func New(opts ...Option) *App { return &App{} }
+
+// Run starts the App and blocks until it is stopped
+func (app *App) Run() {}
+
+// Provide registers constructors
+func Provide(constructors ...interface{}) Option { return option{} }
+
+// Invoke registers functions run on start
+func Invoke(funcs ...interface{}) Option { return option{} }
+
+// Decorate registers functions that modify provided values
+func Decorate(decorators ...interface{}) Option { return option{} }
+
+// Supply provides instantiated values
+func Supply(values ...interface{}) Option { return option{} }
+
+// Module groups options under a name
+func Module(name string, opts ...Option) Option { return option{} }
+
+// Options groups options
+func Options(opts ...Option) Option { return option{} }
+
+// Annotate annotates a constructor
+func Annotate(t interface{}, anns ...Annotation) interface{} { return t }
+
+// As provides a result as the given interfaces
+func As(interfaces ...interface{}) Annotation { return annotation{} }
+
+// Self provides a result as its own type alongside As
+func Self() any { return nil }
+
+// ParamTags tags constructor parameters
+func ParamTags(tags ...string) Annotation { return annotation{} }
+
+// ResultTags tags constructor results
+func ResultTags(tags ...string) Annotation { return annotation{} }
+
+// StartHook builds a Hook from a start function
+func StartHook[T any](start T) Hook { return Hook{} }
+
+// StopHook builds a Hook from a stop function
+func StopHook[T any](stop T) Hook { return Hook{} }
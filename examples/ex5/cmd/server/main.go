@@ -0,0 +1,16 @@
+package main
+
+import (
+	"log"
+
+	"example.com/ex5/internal/config"
+)
+
+func main() {
+	server, cleanup, err := InitializeServer(config.Env{App: config.Config{Addr: ":8080", DSN: "postgres://localhost/app"}})
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer cleanup()
+	log.Fatal(server.ListenAndServe())
+}
@@ -0,0 +1,21 @@
+//go:build wireinject
+
+package main
+
+import (
+	"example.com/ex5/internal/app"
+	"example.com/ex5/internal/config"
+	"example.com/ex5/internal/store"
+	"github.com/google/wire"
+)
+
+// InitializeServer builds the production server
+func InitializeServer(env config.Env) (*app.Server, func(), error) {
+	wire.Build(wire.FieldsOf(new(config.Env), "App"), store.SQLSet, app.ProviderSet)
+	return nil, nil, nil
+}
+
+// InitializeTestServer builds a server over an in-memory store
+func InitializeTestServer(cfg config.Config) *app.Server {
+	panic(wire.Build(store.MemorySet, app.ProviderSet))
+}
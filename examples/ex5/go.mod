@@ -0,0 +1,8 @@
+module example.com/ex5
+
+go 1.22
+
+require github.com/google/wire v0.6.0
+
+// A minimal stand-in with wire's API, so the example builds offline
+replace github.com/google/wire => ./third_party/wire
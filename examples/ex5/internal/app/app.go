@@ -0,0 +1,39 @@
+package app
+
+import (
+	"net/http"
+	"time"
+
+	"example.com/ex5/internal/config"
+	"example.com/ex5/internal/service"
+	"github.com/google/wire"
+)
+
+// Timeout bounds request handling
+type Timeout time.Duration
+
+// Server serves lookups over HTTP
+type Server struct {
+	http *http.Server
+	svc  *service.Service
+}
+
+// NewServer creates the HTTP server
+func NewServer(cfg config.Config, svc *service.Service, timeout Timeout) *Server {
+	return &Server{
+		http: &http.Server{Addr: cfg.Addr, ReadTimeout: time.Duration(timeout)},
+		svc:  svc,
+	}
+}
+
+// ListenAndServe serves until the server fails
+func (s *Server) ListenAndServe() error {
+	return s.http.ListenAndServe()
+}
+
+// ProviderSet provides the server and everything it needs but the store
+var ProviderSet = wire.NewSet(
+	service.Set,
+	NewServer,
+	wire.Value(Timeout(30*time.Second)),
+)
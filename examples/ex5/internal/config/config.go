@@ -0,0 +1,13 @@
+package config
+
+// Config holds the server settings
+type Config struct {
+	Addr string
+	DSN  string
+}
+
+// Env is the process environment
+type Env struct {
+	App   Config
+	Debug bool
+}
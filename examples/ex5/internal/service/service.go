@@ -0,0 +1,36 @@
+package service
+
+import (
+	"time"
+
+	"example.com/ex5/internal/store"
+	"github.com/google/wire"
+)
+
+// Clock tells the time
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock reads the system clock
+type SystemClock struct{}
+
+// Now returns the current time
+func (SystemClock) Now() time.Time { return time.Now() }
+
+// Service looks records up
+type Service struct {
+	Repo  store.Repository
+	Clock Clock
+}
+
+// Lookup finds a record
+func (s *Service) Lookup(id string) (string, error) {
+	return s.Repo.Find(id)
+}
+
+// Set provides the service from its fields and the system clock
+var Set = wire.NewSet(
+	wire.Struct(new(Service), "*"),
+	wire.InterfaceValue(new(Clock), SystemClock{}),
+)
@@ -0,0 +1,52 @@
+package store
+
+import (
+	"errors"
+
+	"example.com/ex5/internal/config"
+	"github.com/google/wire"
+)
+
+// Repository finds records by ID
+type Repository interface {
+	Find(id string) (string, error)
+}
+
+// SQLStore is a database-backed repository
+type SQLStore struct {
+	dsn string
+}
+
+// NewSQLStore opens the database; the cleanup function closes it
+func NewSQLStore(cfg config.Config) (*SQLStore, func(), error) {
+	if cfg.DSN == "" {
+		return nil, nil, errors.New("store: empty DSN")
+	}
+	return &SQLStore{dsn: cfg.DSN}, func() {}, nil
+}
+
+// Find looks a record up in the database
+func (s *SQLStore) Find(id string) (string, error) {
+	return s.dsn + "/" + id, nil
+}
+
+// MemoryStore is an in-memory repository for tests
+type MemoryStore struct {
+	records map[string]string
+}
+
+// NewMemoryStore creates an empty store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[string]string)}
+}
+
+// Find looks a record up in memory
+func (m *MemoryStore) Find(id string) (string, error) {
+	return m.records[id], nil
+}
+
+// SQLSet provides a database-backed Repository
+var SQLSet = wire.NewSet(NewSQLStore, wire.Bind(new(Repository), new(*SQLStore)))
+
+// MemorySet provides an in-memory Repository
+var MemorySet = wire.NewSet(NewMemoryStore, wire.Bind(new(Repository), new(*MemoryStore)))
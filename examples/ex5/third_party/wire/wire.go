@@ -0,0 +1,40 @@
+// A stand-in for the parts of github.com/google/wire's API used by the
+// example. It type-checks like the real library but generates nothing.
+
+package wire
+
+// ProviderSet is a set of providers
+type ProviderSet struct{}
+
+// NewSet creates a new provider set from providers and other sets
+func NewSet(...interface{}) ProviderSet { return ProviderSet{} }
+
+// Build declares the providers of an injector function
+func Build(...interface{}) string { return "implementation not generated, run wire" }
+
+// Binding maps an interface to a concrete type
+type Binding struct{}
+
+// Bind declares that a concrete type should be used for an interface
+func Bind(iface, to interface{}) Binding { return Binding{} }
+
+// ProvidedValue is a value provided as-is
+type ProvidedValue struct{}
+
+// Value provides an expression's value
+func Value(interface{}) ProvidedValue { return ProvidedValue{} }
+
+// InterfaceValue provides a value as an interface type
+func InterfaceValue(typ interface{}, x interface{}) ProvidedValue { return ProvidedValue{} }
+
+// StructProvider provides a struct from its fields
+type StructProvider struct{}
+
+// Struct provides a struct whose named fields are injected
+func Struct(structType interface{}, fieldNames ...string) StructProvider { return StructProvider{} }
+
+// StructFields provides fields of a struct
+type StructFields struct{}
+
+// FieldsOf provides the named fields of a struct
+func FieldsOf(structType interface{}, fieldNames ...string) StructFields { return StructFields{} }
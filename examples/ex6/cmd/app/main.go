@@ -0,0 +1,21 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"example.com/ex6/internal/mail"
+	_ "example.com/ex6/internal/plugins"
+	"example.com/ex6/internal/registry"
+	"example.com/ex6/internal/web"
+)
+
+func main() {
+	if _, err := web.Container(); err != nil {
+		log.Fatal(err)
+	}
+	if err := mail.Notify(mail.Injector(), "ops@example.com"); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(registry.Names())
+}
@@ -0,0 +1,14 @@
+module example.com/ex6
+
+go 1.22
+
+require (
+	github.com/samber/do v1.6.0
+	go.uber.org/dig v1.18.0
+)
+
+// Minimal stand-ins with each library's API, so the example builds offline
+replace (
+	github.com/samber/do => ./third_party/do
+	go.uber.org/dig => ./third_party/dig
+)
@@ -0,0 +1,53 @@
+package mail
+
+import (
+	"errors"
+
+	"github.com/samber/do"
+)
+
+// Config holds the mail server settings
+type Config struct {
+	Host string
+}
+
+// NewConfig reads the settings
+func NewConfig(i *do.Injector) (Config, error) {
+	return Config{Host: "localhost:25"}, nil
+}
+
+// Mailer sends mail
+type Mailer struct {
+	host   string
+	region string
+}
+
+// Send delivers a message
+func (m *Mailer) Send(to, body string) error {
+	if to == "" {
+		return errors.New("mail: no recipient")
+	}
+	return nil
+}
+
+// Injector registers the mail services
+func Injector() *do.Injector {
+	injector := do.New()
+	do.Provide(injector, NewConfig)
+	do.ProvideNamedValue(injector, "region", "eu-west-1")
+	do.Provide(injector, func(i *do.Injector) (*Mailer, error) {
+		cfg := do.MustInvoke[Config](i)
+		region := do.MustInvokeNamed[string](i, "region")
+		return &Mailer{host: cfg.Host, region: region}, nil
+	})
+	return injector
+}
+
+// Notify sends a message through the injector's mailer
+func Notify(injector *do.Injector, to string) error {
+	mailer, err := do.Invoke[*Mailer](injector)
+	if err != nil {
+		return err
+	}
+	return mailer.Send(to, "hello")
+}
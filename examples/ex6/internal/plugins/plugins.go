@@ -0,0 +1,30 @@
+package plugins
+
+import (
+	"strings"
+
+	"example.com/ex6/internal/registry"
+)
+
+// Upper upper-cases text
+type Upper struct{}
+
+// Apply upper-cases text
+func (Upper) Apply(text string) string { return strings.ToUpper(text) }
+
+// NewUpper creates the plugin
+func NewUpper() (registry.Plugin, error) { return Upper{}, nil }
+
+// echoName is the name the echo plugin registers under
+const echoName = "echo"
+
+func init() {
+	registry.Register("upper", NewUpper)
+	registry.Register(echoName, func() (registry.Plugin, error) {
+		return echo{}, nil
+	})
+}
+
+type echo struct{}
+
+func (echo) Apply(text string) string { return text }
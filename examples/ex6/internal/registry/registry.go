@@ -0,0 +1,28 @@
+package registry
+
+import "sort"
+
+// Plugin transforms text
+type Plugin interface {
+	Apply(text string) string
+}
+
+// Factory creates a plugin
+type Factory func() (Plugin, error)
+
+var factories = make(map[string]Factory)
+
+// Register makes a plugin available by name
+func Register(name string, factory Factory) {
+	factories[name] = factory
+}
+
+// Names lists the registered plugins
+func Names() []string {
+	var names []string
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
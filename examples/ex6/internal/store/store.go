@@ -0,0 +1,21 @@
+package store
+
+// Store reads values by key
+type Store interface {
+	Get(key string) string
+}
+
+// MemStore keeps values in memory
+type MemStore struct {
+	values map[string]string
+}
+
+// NewMemStore creates an empty store
+func NewMemStore() *MemStore {
+	return &MemStore{values: make(map[string]string)}
+}
+
+// Get returns the value of a key
+func (m *MemStore) Get(key string) string {
+	return m.values[key]
+}
@@ -0,0 +1,92 @@
+package web
+
+import (
+	"net/http"
+
+	"example.com/ex6/internal/store"
+	"go.uber.org/dig"
+)
+
+// Service answers lookups
+type Service struct {
+	store store.Store
+	cache store.Store
+}
+
+// ServiceParams are the service's dependencies
+type ServiceParams struct {
+	dig.In
+
+	Store store.Store
+	Cache store.Store `name:"cache" optional:"true"`
+}
+
+// NewService creates the service
+func NewService(p ServiceParams) *Service {
+	return &Service{store: p.Store, cache: p.Cache}
+}
+
+// Handlers are the service's HTTP handlers
+type Handlers struct {
+	dig.Out
+
+	Lookup http.Handler `group:"handlers"`
+}
+
+// NewHandlers creates the service's handlers
+func NewHandlers(s *Service) Handlers {
+	return Handlers{Lookup: http.NotFoundHandler()}
+}
+
+// Admin serves administration pages
+type Admin struct {
+	svc *Service
+}
+
+// NewAdmin creates the admin pages
+func NewAdmin(s *Service) *Admin {
+	return &Admin{svc: s}
+}
+
+// WithLogging logs admin requests
+func WithLogging(a *Admin) *Admin {
+	return a
+}
+
+// Mux serves every handler
+type Mux struct {
+	dig.In
+
+	Handlers []http.Handler `group:"handlers"`
+}
+
+// Container builds the web container
+func Container() (*dig.Container, error) {
+	c := dig.New()
+	if err := c.Provide(store.NewMemStore, dig.As(new(store.Store))); err != nil {
+		return nil, err
+	}
+	if err := c.Provide(store.NewMemStore, dig.Name("cache"), dig.As(new(store.Store))); err != nil {
+		return nil, err
+	}
+	if err := c.Provide(NewService); err != nil {
+		return nil, err
+	}
+	if err := c.Provide(NewHandlers); err != nil {
+		return nil, err
+	}
+
+	admin := c.Scope("admin")
+	if err := admin.Provide(NewAdmin); err != nil {
+		return nil, err
+	}
+	if err := admin.Decorate(WithLogging); err != nil {
+		return nil, err
+	}
+
+	return c, c.Invoke(func(m Mux) {
+		for _, h := range m.Handlers {
+			http.Handle("/", h)
+		}
+	})
+}
@@ -0,0 +1,70 @@
+// A stand-in for the parts of go.uber.org/dig's API used by the example. It
+// type-checks like the real library but does not run anything.
+
+package dig
+
+// In marks a parameter struct whose fields are dependencies
+type In struct{}
+
+// Out marks a result struct whose fields are provided
+type Out struct{}
+
+// ProvideOption modifies Provide
+type ProvideOption interface{ provideOption() }
+
+// InvokeOption modifies Invoke
+type InvokeOption interface{ invokeOption() }
+
+// DecorateOption modifies Decorate
+type DecorateOption interface{ decorateOption() }
+
+// ScopeOption modifies Scope
+type ScopeOption interface{ scopeOption() }
+
+type provideOption struct{}
+
+func (provideOption) provideOption() {}
+
+// Name names the values a constructor provides
+func Name(name string) ProvideOption { return provideOption{} }
+
+// Group adds the values a constructor provides to a value group
+func Group(group string) ProvideOption { return provideOption{} }
+
+// As provides the values as the given interfaces instead
+func As(i ...interface{}) ProvideOption { return provideOption{} }
+
+// Container is a dependency injection container
+type Container struct{ scope *Scope }
+
+// New creates a container
+func New() *Container { return &Container{scope: &Scope{}} }
+
+// Provide registers a constructor
+func (c *Container) Provide(constructor interface{}, opts ...ProvideOption) error { return nil }
+
+// Invoke calls a function with its dependencies
+func (c *Container) Invoke(function interface{}, opts ...InvokeOption) error { return nil }
+
+// Decorate registers a decorator of provided values
+func (c *Container) Decorate(decorator interface{}, opts ...DecorateOption) error { return nil }
+
+// Scope creates a child scope
+func (c *Container) Scope(name string, opts ...ScopeOption) *Scope {
+	return c.scope.Scope(name, opts...)
+}
+
+// Scope is a child container
+type Scope struct{}
+
+// Provide registers a constructor
+func (s *Scope) Provide(constructor interface{}, opts ...ProvideOption) error { return nil }
+
+// Invoke calls a function with its dependencies
+func (s *Scope) Invoke(function interface{}, opts ...InvokeOption) error { return nil }
+
+// Decorate registers a decorator of provided values
+func (s *Scope) Decorate(decorator interface{}, opts ...DecorateOption) error { return nil }
+
+// Scope creates a child scope
+func (s *Scope) Scope(name string, opts ...ScopeOption) *Scope { return &Scope{} }
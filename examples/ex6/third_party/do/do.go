@@ -0,0 +1,49 @@
+// A stand-in for the parts of github.com/samber/do's API used by the
+// example. It type-checks like the real library but does not run anything.
+
+package do
+
+// Injector is a dependency injection container
+type Injector struct{}
+
+// New creates an injector
+func New() *Injector { return &Injector{} }
+
+// Provider builds a service
+type Provider[T any] func(*Injector) (T, error)
+
+// Provide registers a service provider
+func Provide[T any](i *Injector, provider Provider[T]) {}
+
+// ProvideNamed registers a named service provider
+func ProvideNamed[T any](i *Injector, name string, provider Provider[T]) {}
+
+// ProvideValue registers a value
+func ProvideValue[T any](i *Injector, value T) {}
+
+// ProvideNamedValue registers a named value
+func ProvideNamedValue[T any](i *Injector, name string, value T) {}
+
+// Invoke returns a service
+func Invoke[T any](i *Injector) (T, error) {
+	var t T
+	return t, nil
+}
+
+// MustInvoke returns a service or panics
+func MustInvoke[T any](i *Injector) T {
+	var t T
+	return t
+}
+
+// InvokeNamed returns a named service
+func InvokeNamed[T any](i *Injector, name string) (T, error) {
+	var t T
+	return t, nil
+}
+
+// MustInvokeNamed returns a named service or panics
+func MustInvokeNamed[T any](i *Injector, name string) T {
+	var t T
+	return t
+}
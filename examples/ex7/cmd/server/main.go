@@ -0,0 +1,35 @@
+package main
+
+import (
+	"database/sql"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"example.com/ex7/internal/server"
+	"example.com/ex7/internal/store"
+)
+
+func main() {
+	db, err := sql.Open("sqlite", os.Getenv("DATABASE_URL"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	users := store.New(db)
+
+	script, err := os.ReadFile(filepath.Join(os.Getenv("DATA_DIR"), "seed.sql"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := users.Seed(string(script)); err != nil {
+		log.Fatal(err)
+	}
+
+	handler := server.NewHandler(users)
+	http.HandleFunc("/search", handler.Search)
+	http.HandleFunc("/user", handler.User)
+	http.HandleFunc("/profile", handler.Profile)
+	http.HandleFunc("/thumbnail", handler.Thumbnail)
+	log.Fatal(http.ListenAndServe(":8080", nil))
+}
@@ -0,0 +1,3 @@
+module example.com/ex7
+
+go 1.22
@@ -0,0 +1,53 @@
+package server
+
+import (
+	"net/http"
+	"os/exec"
+
+	"example.com/ex7/internal/store"
+)
+
+// Routes registers the admin endpoints, served by inline handlers
+func Routes(mux *http.ServeMux, users *store.Store) {
+	// The filter reaches the query through the closure returning it
+	mux.HandleFunc("/admin/report", func(w http.ResponseWriter, r *http.Request) {
+		filter := func() string { return r.FormValue("filter") }
+		if _, err := users.Find(filter()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+
+	// The file name reaches the command through the closure's parameter
+	mux.HandleFunc("/admin/convert", func(w http.ResponseWriter, r *http.Request) {
+		convert := func(file string) error {
+			return exec.Command("convert", file, "out.png").Run()
+		}
+		if err := convert(r.FormValue("file")); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	})
+
+	// The directory is stored through a slice sharing the arguments' array
+	mux.HandleFunc("/admin/archive", func(w http.ResponseWriter, r *http.Request) {
+		args := []string{"czf", "archive.tgz", ""}
+		last := args[2:]
+		last[0] = r.FormValue("dir")
+		if err := exec.Command("tar", args...).Run(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	})
+
+	// The name is filled in through a pointer to it
+	mux.HandleFunc("/admin/find", func(w http.ResponseWriter, r *http.Request) {
+		var name string
+		formValue(r, "name", &name)
+		if _, err := users.Find("name = '" + name + "'"); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// formValue stores a form value through a pointer
+func formValue(r *http.Request, key string, value *string) {
+	*value = r.FormValue(key)
+}
@@ -0,0 +1,27 @@
+package server
+
+import (
+	"context"
+
+	"example.com/ex7/internal/store"
+	"example.com/ex7/pkg/pb"
+)
+
+// SearchServer implements the Search RPC
+type SearchServer struct {
+	users *store.Store
+}
+
+// NewSearchServer creates a SearchServer
+func NewSearchServer(users *store.Store) *SearchServer {
+	return &SearchServer{users: users}
+}
+
+// Search finds the users matching the request's query
+func (s *SearchServer) Search(ctx context.Context, req *pb.SearchRequest) (*pb.SearchResponse, error) {
+	ids, err := s.users.Find(req.GetQuery())
+	if err != nil {
+		return nil, err
+	}
+	return &pb.SearchResponse{IDs: ids}, nil
+}
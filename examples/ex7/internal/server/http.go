@@ -0,0 +1,58 @@
+package server
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"os/exec"
+	"strings"
+
+	"example.com/ex7/internal/store"
+)
+
+var profile = template.Must(template.New("profile").Parse(`<p>{{.}}</p>`))
+
+// Handler serves the user API
+type Handler struct {
+	users *store.Store
+}
+
+// NewHandler creates a Handler
+func NewHandler(users *store.Store) *Handler {
+	return &Handler{users: users}
+}
+
+// Search lists the users with the name given by the name query parameter
+func (h *Handler) Search(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	ids, err := h.users.Find("name = '" + name + "'")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprint(w, strings.Join(ids, "\n"))
+}
+
+// User reports whether the user given by the id query parameter exists
+func (h *Handler) User(w http.ResponseWriter, r *http.Request) {
+	ok, err := h.users.Exists(r.URL.Query().Get("id"))
+	if err != nil || !ok {
+		http.NotFound(w, r)
+	}
+}
+
+// Profile renders a user's biography
+func (h *Handler) Profile(w http.ResponseWriter, r *http.Request) {
+	bio := template.HTML(r.FormValue("bio"))
+	profile.Execute(w, bio)
+}
+
+// Thumbnail converts the uploaded file named by the file form value
+func (h *Handler) Thumbnail(w http.ResponseWriter, r *http.Request) {
+	out, err := exec.Command("convert", r.FormValue("file"), "-resize", "64x64", "png:-").Output()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Write(out)
+}
@@ -0,0 +1,46 @@
+package store
+
+import "database/sql"
+
+// Store queries the user table
+type Store struct {
+	db *sql.DB
+}
+
+// New creates a Store on an open database
+func New(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// Find returns the IDs of the users matching a SQL condition
+func (s *Store) Find(filter string) ([]string, error) {
+	query := "SELECT id FROM users WHERE " + filter
+	rows, err := s.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// Exists reports whether a user exists, passing the ID as a query parameter
+func (s *Store) Exists(id string) (bool, error) {
+	var n int
+	err := s.db.QueryRow("SELECT count(*) FROM users WHERE id = ?", id).Scan(&n)
+	return n > 0, err
+}
+
+// Seed runs a SQL script
+func (s *Store) Seed(script string) error {
+	_, err := s.db.Exec(script)
+	return err
+}
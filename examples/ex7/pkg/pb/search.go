@@ -0,0 +1,20 @@
+package pb
+
+// SearchRequest is the request message of the Search RPC
+type SearchRequest struct {
+	Query string
+	Limit int32
+}
+
+// GetQuery returns the query, or "" for a nil request
+func (x *SearchRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+// SearchResponse is the response message of the Search RPC
+type SearchResponse struct {
+	IDs []string
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+
+	"example.com/ex8/worker"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	jobs := []worker.Job{
+		func(ctx context.Context) error { fmt.Println("first"); return nil },
+		func(ctx context.Context) error { fmt.Println("second"); return nil },
+	}
+	if err := worker.RunAll(ctx, jobs); err != nil {
+		log.Fatal(err)
+	}
+
+	pool := worker.NewPool(len(jobs))
+	pool.Start(ctx, 2)
+	pool.Submit(jobs...)
+	for range jobs {
+		if err := <-pool.Results(); err != nil {
+			log.Print(err)
+		}
+	}
+	stop()
+	pool.Stop()
+}
@@ -0,0 +1,8 @@
+module example.com/ex8
+
+go 1.22
+
+require golang.org/x/sync v0.17.0
+
+// A minimal stand-in with errgroup's API, so the example builds offline
+replace golang.org/x/sync => ./third_party/sync
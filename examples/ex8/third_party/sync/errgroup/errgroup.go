@@ -0,0 +1,48 @@
+// A stand-in for golang.org/x/sync/errgroup, with the same API and a
+// simplified implementation.
+
+package errgroup
+
+import (
+	"context"
+	"sync"
+)
+
+// Group runs goroutines and collects the first error
+type Group struct {
+	cancel func()
+	wg     sync.WaitGroup
+	once   sync.Once
+	err    error
+}
+
+// WithContext returns a Group whose context is canceled by the first error
+func WithContext(ctx context.Context) (*Group, context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Group{cancel: cancel}, ctx
+}
+
+// Go runs f in a new goroutine
+func (g *Group) Go(f func() error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if err := f(); err != nil {
+			g.once.Do(func() {
+				g.err = err
+				if g.cancel != nil {
+					g.cancel()
+				}
+			})
+		}
+	}()
+}
+
+// Wait blocks until every goroutine returns, and returns the first error
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel()
+	}
+	return g.err
+}
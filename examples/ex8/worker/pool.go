@@ -0,0 +1,78 @@
+package worker
+
+import (
+	"context"
+	"sync"
+)
+
+// Job is a unit of work
+type Job func(ctx context.Context) error
+
+// Pool runs queued jobs on a fixed number of goroutines
+type Pool struct {
+	jobs    chan Job
+	results chan error
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	done    int
+}
+
+// NewPool creates a Pool queueing up to size jobs
+func NewPool(size int) *Pool {
+	return &Pool{jobs: make(chan Job, size), results: make(chan error)}
+}
+
+// Start launches n workers that run jobs until ctx is done
+func (p *Pool) Start(ctx context.Context, n int) {
+	for i := 0; i < n; i++ {
+		p.wg.Add(1)
+		go p.work(ctx)
+	}
+}
+
+// Submit queues jobs
+func (p *Pool) Submit(jobs ...Job) {
+	for _, job := range jobs {
+		p.jobs <- job
+	}
+}
+
+// Results returns the error of each finished job
+func (p *Pool) Results() <-chan error {
+	return p.results
+}
+
+// Stop waits for the workers to return once their context is done
+func (p *Pool) Stop() {
+	p.wg.Wait()
+	close(p.results)
+}
+
+// Done returns how many jobs have finished
+func (p *Pool) Done() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.done
+}
+
+// work runs queued jobs, reporting each result
+func (p *Pool) work(ctx context.Context) {
+	defer p.wg.Done()
+	for {
+		select {
+		case job := <-p.jobs:
+			err := job(ctx)
+			p.finish()
+			p.results <- err
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// finish counts a finished job
+func (p *Pool) finish() {
+	p.mu.Lock()
+	p.done++
+	p.mu.Unlock()
+}
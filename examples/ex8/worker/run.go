@@ -0,0 +1,38 @@
+package worker
+
+import (
+	"context"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// RunAll runs jobs concurrently, canceling the rest at the first error
+func RunAll(ctx context.Context, jobs []Job) error {
+	g, gctx := errgroup.WithContext(ctx)
+	for _, job := range jobs {
+		g.Go(func() error {
+			return job(gctx)
+		})
+	}
+	return g.Wait()
+}
+
+// Retry runs a job until it succeeds or the attempts run out
+func Retry(ctx context.Context, job Job, attempts int) error {
+	var err error
+	for i := 0; i < attempts; i++ {
+		if err = job(context.TODO()); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// Warm runs every job once in the background, ignoring the results
+func Warm(jobs []Job) {
+	for _, job := range jobs {
+		go func() {
+			_ = job(context.Background())
+		}()
+	}
+}
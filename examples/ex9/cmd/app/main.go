@@ -0,0 +1,24 @@
+package main
+
+import (
+	"log"
+	"net/http"
+
+	"example.com/ex9/internal/adapters/email"
+	"example.com/ex9/internal/adapters/memory"
+	"example.com/ex9/internal/adapters/rest"
+	"example.com/ex9/internal/app"
+	"example.com/ex9/internal/domain"
+)
+
+func main() {
+	repo := memory.NewRepository()
+	notifier := email.NewNotifier("orders@example.com")
+	service := app.NewOrderService(repo, notifier)
+
+	if err := repo.Save(&domain.Order{ID: "1", Customer: "ada@example.com", Status: "placed"}); err != nil {
+		log.Fatal(err)
+	}
+
+	log.Fatal(http.ListenAndServe(":8080", rest.NewHandler(service)))
+}
@@ -0,0 +1,3 @@
+module example.com/ex9
+
+go 1.22
@@ -0,0 +1,26 @@
+package email
+
+import (
+	"fmt"
+
+	"example.com/ex9/internal/domain"
+)
+
+// Notifier sends notifications by email
+type Notifier struct {
+	from string
+}
+
+// NewNotifier creates a Notifier sending from the given address
+func NewNotifier(from string) *Notifier {
+	return &Notifier{from: from}
+}
+
+// Notify emails a message to the order's customer
+func (n *Notifier) Notify(o *domain.Order, msg string) error {
+	if o.Customer == "" {
+		return fmt.Errorf("notify %s: no customer to address", o.ID)
+	}
+	fmt.Printf("From: %s\nTo: %s\n\n%s\n", n.from, o.Customer, msg)
+	return nil
+}
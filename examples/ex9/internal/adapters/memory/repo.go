@@ -0,0 +1,32 @@
+package memory
+
+import (
+	"fmt"
+
+	"example.com/ex9/internal/domain"
+)
+
+// Repository keeps orders in memory
+type Repository struct {
+	orders map[string]*domain.Order
+}
+
+// NewRepository creates an empty Repository
+func NewRepository() *Repository {
+	return &Repository{orders: make(map[string]*domain.Order)}
+}
+
+// Get returns the order with the given id
+func (r *Repository) Get(id string) (*domain.Order, error) {
+	o, ok := r.orders[id]
+	if !ok {
+		return nil, fmt.Errorf("get order %s: %w", id, domain.ErrNotFound)
+	}
+	return o, nil
+}
+
+// Save stores an order
+func (r *Repository) Save(o *domain.Order) error {
+	r.orders[o.ID] = o
+	return nil
+}
@@ -0,0 +1,35 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+
+	"example.com/ex9/internal/app"
+	"example.com/ex9/internal/domain"
+)
+
+// Handler serves the order API
+type Handler struct {
+	orders app.Canceler
+}
+
+// NewHandler creates a Handler for the cancel use case
+func NewHandler(orders app.Canceler) *Handler {
+	return &Handler{orders: orders}
+}
+
+// ServeHTTP cancels the order given by the id query parameter
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	err := h.orders.Cancel(r.URL.Query().Get("id"))
+	var statusErr *domain.StatusError
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.As(err, &statusErr):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
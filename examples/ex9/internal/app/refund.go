@@ -0,0 +1,22 @@
+package app
+
+import (
+	"fmt"
+
+	"example.com/ex9/internal/domain"
+)
+
+// Refund refunds a canceled order, naming the order in any error
+func (s *OrderService) Refund(id string) (err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("refund %s: %w", id, err)
+		}
+	}()
+
+	o, err := s.repo.Get(id)
+	if err == nil && o.Status != "canceled" {
+		err = &domain.StatusError{Status: o.Status}
+	}
+	return
+}
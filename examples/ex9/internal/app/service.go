@@ -0,0 +1,37 @@
+package app
+
+import "example.com/ex9/internal/domain"
+
+// Canceler is the cancel use case, as driven by primary adapters
+type Canceler interface {
+	Cancel(id string) error
+}
+
+// OrderService implements the order use cases
+type OrderService struct {
+	repo     domain.OrderRepository
+	notifier domain.Notifier
+}
+
+// NewOrderService creates an OrderService
+func NewOrderService(repo domain.OrderRepository, notifier domain.Notifier) *OrderService {
+	return &OrderService{repo: repo, notifier: notifier}
+}
+
+// Cancel cancels an order that has not shipped and notifies its customer
+func (s *OrderService) Cancel(id string) error {
+	o, err := s.repo.Get(id)
+	if err != nil {
+		return err
+	}
+	if o.Status == "shipped" {
+		return &domain.StatusError{Status: o.Status}
+	}
+
+	o.Status = "canceled"
+	if err := s.repo.Save(o); err != nil {
+		return err
+	}
+
+	return s.notifier.Notify(o, "your order was canceled")
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotFound is returned when an order does not exist
+var ErrNotFound = errors.New("order not found")
+
+// StatusError is returned when an order cannot change from its status
+type StatusError struct {
+	Status string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("order is %s", e.Status)
+}
+
+// Order is a customer's order
+type Order struct {
+	ID       string
+	Customer string
+	Status   string
+}
+
+// OrderRepository stores orders
+type OrderRepository interface {
+	Get(id string) (*Order, error)
+	Save(o *Order) error
+}
+
+// Notifier tells customers about their orders
+type Notifier interface {
+	Notify(o *Order, msg string) error
+}
@@ -18,23 +18,49 @@ func ExtractAndFormat(ctx context.Context, target types.Target, opts types.Optio
 	}
 
 	// Step 2: Format based on requested format
+	if err := formatResult(result, opts); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// formatResult renders a result into result.Rendered, trimming to the budget if one is set
+func formatResult(result *types.Result, opts types.Options) error {
+	var err error
+	if limit := budgetBytes(opts); limit > 0 {
+		result.Rendered, err = fitToBudget(result, opts, limit)
+	} else {
+		result.Rendered, err = render(result, opts)
+	}
+	return err
+}
+
+// render formats a result in the requested output format
+func render(result *types.Result, opts types.Options) (string, error) {
 	switch opts.Format {
 	case "markdown", "":
-		result.Rendered, err = format.ToMarkdown(result.Extract, opts)
+		rendered, err := format.ToMarkdownWithMetadata(result.Extract, result.Metadata, opts)
 		if err != nil {
-			return nil, fmt.Errorf("failed to format markdown: %w", err)
+			return "", fmt.Errorf("failed to format markdown: %w", err)
 		}
+		return rendered, nil
 	case "json":
-		result.Rendered, err = format.ToJSON(result.Extract, opts)
+		rendered, err := format.ToJSONWithMetadata(result.Extract, result.Metadata, opts)
+		if err != nil {
+			return "", fmt.Errorf("failed to format json: %w", err)
+		}
+		return rendered, nil
+	case "result":
+		rendered, err := format.ToResultJSON(*result)
 		if err != nil {
-			return nil, fmt.Errorf("failed to format json: %w", err)
+			return "", fmt.Errorf("failed to format result json: %w", err)
 		}
+		return rendered, nil
 	case "html":
 		// TODO: Implement HTML formatter
-		result.Rendered = "HTML formatting not yet implemented"
+		return "HTML formatting not yet implemented", nil
 	default:
-		result.Rendered = fmt.Sprintf("Unknown format: %s", opts.Format)
+		return fmt.Sprintf("Unknown format: %s", opts.Format), nil
 	}
-
-	return result, nil
 }
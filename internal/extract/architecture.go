@@ -0,0 +1,120 @@
+package extract
+
+import (
+	"sort"
+
+	"github.com/extract-scope-go/go-scope/internal/rules"
+	"github.com/extract-scope-go/go-scope/internal/types"
+)
+
+// CheckArchitecture walks the reference graph of every symbol in a layer and
+// reports dependencies on layers the rules do not allow. Packages in no layer
+// are looked through, so a domain → util → adapter chain is still caught.
+func (w *Workspace) CheckArchitecture(cfg *rules.Config) []types.Violation {
+	modulePath := w.modulePath()
+	edges := make(map[string][]types.Reference)
+
+	var violations []types.Violation
+	for _, entry := range w.symbols {
+		from := entry.symbol
+		fromLayer := cfg.LayerOf(from.Package, modulePath)
+		if fromLayer == "" {
+			continue
+		}
+
+		visited := map[string]bool{from.ID(): true}
+		var queue [][]types.Reference
+		for _, ref := range w.directReferences(from, edges) {
+			queue = append(queue, []types.Reference{ref})
+		}
+
+		for len(queue) > 0 {
+			chain := queue[0]
+			queue = queue[1:]
+
+			to := chain[len(chain)-1].Symbol
+			if visited[to.ID()] {
+				continue
+			}
+			visited[to.ID()] = true
+
+			toLayer := cfg.LayerOf(to.Package, modulePath)
+			if toLayer == "" {
+				for _, next := range w.directReferences(to, edges) {
+					queue = append(queue, append(chain[:len(chain):len(chain)], next))
+				}
+				continue
+			}
+
+			if !cfg.Allows(fromLayer, toLayer) {
+				violations = append(violations, types.Violation{
+					From:      withoutCode(from),
+					FromLayer: fromLayer,
+					To:        withoutCode(to),
+					ToLayer:   toLayer,
+					Chain:     chainWithoutCode(chain),
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		a, b := violations[i].From, violations[j].From
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+
+	return violations
+}
+
+// directReferences returns the symbols sym references within the module,
+// memoized in edges by symbol ID
+func (w *Workspace) directReferences(sym types.Symbol, edges map[string][]types.Reference) []types.Reference {
+	if refs, ok := edges[sym.ID()]; ok {
+		return refs
+	}
+
+	collector := NewCollector(w.locator.pkgs, w.locator.fset, 1)
+	collected, _, err := collector.Collect(&sym)
+
+	var refs []types.Reference
+	if err == nil {
+		for _, ref := range collected {
+			if ref.External || ref.Symbol.ID() == sym.ID() {
+				continue
+			}
+			refs = append(refs, ref)
+		}
+	}
+
+	edges[sym.ID()] = refs
+	return refs
+}
+
+// modulePath returns the path of the workspace's main module
+func (w *Workspace) modulePath() string {
+	for _, pkg := range w.locator.pkgs {
+		if pkg.Module != nil && pkg.Module.Main {
+			return pkg.Module.Path
+		}
+	}
+	return ""
+}
+
+// withoutCode drops a symbol's code, keeping reports small
+func withoutCode(sym types.Symbol) types.Symbol {
+	sym.Code = ""
+	return sym
+}
+
+// chainWithoutCode copies a reference chain without symbol code
+func chainWithoutCode(chain []types.Reference) []types.Reference {
+	out := make([]types.Reference, len(chain))
+	for i, ref := range chain {
+		ref.Symbol = withoutCode(ref.Symbol)
+		out[i] = ref
+	}
+	return out
+}
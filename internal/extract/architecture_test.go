@@ -0,0 +1,80 @@
+package extract
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/extract-scope-go/go-scope/internal/rules"
+	"github.com/extract-scope-go/go-scope/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCheckArchitectureClean tests that the hexagonal example follows its own rules
+func TestCheckArchitectureClean(t *testing.T) {
+	// Given: The example module and its rules file
+	ws := loadExample(t, "ex2")
+	cfg, err := rules.Load(filepath.Join(ws.Root(), rules.DefaultFile))
+	require.NoError(t, err)
+
+	// When: We check the architecture
+	violations := ws.CheckArchitecture(cfg)
+
+	// Then: There should be no violations
+	assert.Empty(t, violations)
+}
+
+// TestCheckArchitectureDirectViolation tests a forbidden direct dependency
+func TestCheckArchitectureDirectViolation(t *testing.T) {
+	// Given: Rules where adapters may not use the domain
+	ws := loadExample(t, "ex2")
+	cfg := &rules.Config{Layers: []rules.Layer{
+		{Name: "domain", Packages: []string{"internal/domain"}},
+		{Name: "adapters", Packages: []string{"example.com/ex2/internal/adapters/..."}},
+	}}
+
+	// When: We check the architecture
+	violations := ws.CheckArchitecture(cfg)
+
+	// Then: The repository's use of domain.User is reported with a one-step chain
+	v := findViolation(violations, "example.com/ex2/internal/adapters/memory.Repository.Get", "example.com/ex2/internal/domain.User")
+	require.NotNil(t, v, "violations: %v", violations)
+	assert.Equal(t, "adapters", v.FromLayer)
+	assert.Equal(t, "domain", v.ToLayer)
+	require.Len(t, v.Chain, 1)
+	assert.Empty(t, v.From.Code)
+
+	for _, v := range violations {
+		assert.Equal(t, "adapters", v.FromLayer)
+	}
+}
+
+// TestCheckArchitectureTransitiveViolation tests a violation through a package in no layer
+func TestCheckArchitectureTransitiveViolation(t *testing.T) {
+	// Given: cmd may depend on nothing, and app is in no layer
+	ws := loadExample(t, "ex2")
+	cfg := &rules.Config{Layers: []rules.Layer{
+		{Name: "cmd", Packages: []string{"cmd/..."}},
+		{Name: "domain", Packages: []string{"internal/domain/..."}},
+	}}
+
+	// When: We check the architecture
+	violations := ws.CheckArchitecture(cfg)
+
+	// Then: main reaches the repository port through NewUserService
+	v := findViolation(violations, "example.com/ex2/cmd/app.main", "example.com/ex2/internal/domain.UserRepository")
+	require.NotNil(t, v, "violations: %v", violations)
+	require.Len(t, v.Chain, 2)
+	assert.Equal(t, "NewUserService", v.Chain[0].Symbol.Name)
+	assert.Equal(t, "example.com/ex2/internal/app.NewUserService", v.Chain[1].ReferencedByID)
+}
+
+// findViolation returns the violation between two symbol IDs
+func findViolation(violations []types.Violation, from, to string) *types.Violation {
+	for i, v := range violations {
+		if v.From.ID() == from && v.To.ID() == to {
+			return &violations[i]
+		}
+	}
+	return nil
+}
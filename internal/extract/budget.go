@@ -0,0 +1,181 @@
+package extract
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+)
+
+// bytesPerToken approximates how many bytes of Go source or markdown make
+// one LLM token. It is deliberately conservative for code.
+const bytesPerToken = 4
+
+// reasonPriority ranks why a reference was included; higher is kept longer
+var reasonPriority = map[string]int{
+	"direct-call":          5,
+	"field-access":         4,
+	"type-reference":       4,
+	"returns-interface":    3,
+	"interface-contract":   3,
+	"di-binding":           2,
+	"requires-dep":         2,
+	"implements-interface": 1,
+}
+
+// budgetBytes returns the effective byte budget for opts, or 0 for unlimited
+func budgetBytes(opts types.Options) int {
+	limit := opts.MaxBytes
+	if opts.MaxTokens > 0 {
+		tokenBytes := opts.MaxTokens * bytesPerToken
+		if limit == 0 || tokenBytes < limit {
+			limit = tokenBytes
+		}
+	}
+	return limit
+}
+
+// fitToBudget renders the result, degrading references until it fits in limit bytes.
+// Lowest-priority references are reduced to signatures first, then dropped, and
+// finally the target's own code is shortened. The returned output never exceeds
+// limit; if even the bare target cannot fit an error is returned.
+func fitToBudget(result *types.Result, opts types.Options, limit int) (string, error) {
+	rendered, err := render(result, opts)
+	if err != nil || len(rendered) <= limit {
+		return rendered, err
+	}
+
+	original := result.Extract
+	order := degradeOrder(original.References)
+
+	// attempt renders the extract with the first stubbed references reduced
+	// to signatures and the first omitted references dropped
+	attempt := func(stubbed, omitted int, targetLines int) (string, error) {
+		ext := original
+		ext.References = nil
+		report := &types.BudgetReport{MaxBytes: limit, MaxTokens: limit / bytesPerToken}
+
+		drop := make(map[int]bool)
+		for _, i := range order[:omitted] {
+			drop[i] = true
+			report.Omitted = append(report.Omitted, original.References[i].Symbol.ID())
+		}
+
+		stub := make(map[int]bool)
+		for _, i := range order[:stubbed] {
+			ref := original.References[i]
+			if drop[i] || ref.Stub || ref.Render == "signature" || ref.Render == "name" {
+				continue
+			}
+			stub[i] = true
+			report.Stubbed = append(report.Stubbed, ref.Symbol.ID())
+		}
+
+		for i, ref := range original.References {
+			if drop[i] {
+				continue
+			}
+			if stub[i] {
+				ref = stubReference(ref)
+			}
+			ext.References = append(ext.References, ref)
+		}
+
+		if targetLines >= 0 {
+			ext.Target.Code = truncateLines(ext.Target.Code, targetLines)
+			report.TargetTruncated = true
+		}
+
+		ext.Budget = report
+		result.Extract = ext
+		return render(result, opts)
+	}
+
+	// fits reports whether an attempt fits, keeping the last rendering
+	fits := func(stubbed, omitted, targetLines int) bool {
+		rendered, err = attempt(stubbed, omitted, targetLines)
+		return err == nil && len(rendered) <= limit
+	}
+
+	n := len(order)
+
+	// Pass 1: reduce the fewest references to signatures
+	if k := sort.Search(n+1, func(k int) bool { return fits(k, 0, -1) }); k <= n {
+		ok := fits(k, 0, -1)
+		return finish(ok, rendered, err)
+	}
+
+	// Pass 2: with everything stubbed, drop the fewest references
+	if k := sort.Search(n+1, func(k int) bool { return fits(n, k, -1) }); k <= n {
+		ok := fits(n, k, -1)
+		return finish(ok, rendered, err)
+	}
+
+	// Pass 3: keep as many lines of the target as fit
+	lines := strings.Count(original.Target.Code, "\n") + 1
+	if k := sort.Search(lines, func(k int) bool { return fits(n, n, lines-1-k) }); k < lines {
+		ok := fits(n, n, lines-1-k)
+		return finish(ok, rendered, err)
+	}
+
+	if err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("output does not fit in a budget of %d bytes (~%d tokens)", limit, limit/bytesPerToken)
+}
+
+// finish returns the rendering of the final attempt, which must fit
+func finish(ok bool, rendered string, err error) (string, error) {
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", fmt.Errorf("budget attempt did not fit on re-render")
+	}
+	return rendered, nil
+}
+
+// degradeOrder returns reference indexes from lowest to highest priority:
+// deeper references first, then weaker reasons, then less frequently used
+func degradeOrder(refs []types.Reference) []int {
+	order := make([]int, len(refs))
+	for i := range refs {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(a, b int) bool {
+		ra, rb := refs[order[a]], refs[order[b]]
+		if ra.Depth != rb.Depth {
+			return ra.Depth > rb.Depth
+		}
+		if pa, pb := reasonPriority[ra.Reason], reasonPriority[rb.Reason]; pa != pb {
+			return pa < pb
+		}
+		if ra.Uses != rb.Uses {
+			return ra.Uses < rb.Uses
+		}
+		return ra.Symbol.ID() > rb.Symbol.ID()
+	})
+
+	return order
+}
+
+// stubReference reduces a reference to its signature
+func stubReference(ref types.Reference) types.Reference {
+	if ref.Signature == "" {
+		ref.Signature = signatureOf(ref.Symbol)
+	}
+	ref.Symbol.Code = ""
+	ref.Stub = true
+	return ref
+}
+
+// truncateLines keeps the first n lines of code, marking the cut
+func truncateLines(code string, n int) string {
+	lines := strings.Split(code, "\n")
+	if n >= len(lines) {
+		return code
+	}
+	return strings.Join(lines[:n], "\n") + "\n\t// ... truncated to fit budget"
+}
@@ -0,0 +1,124 @@
+package extract
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBudgetBytes tests combining token and byte budgets
+func TestBudgetBytes(t *testing.T) {
+	assert.Equal(t, 0, budgetBytes(types.Options{}))
+	assert.Equal(t, 1000, budgetBytes(types.Options{MaxBytes: 1000}))
+	assert.Equal(t, 400, budgetBytes(types.Options{MaxTokens: 100}))
+	assert.Equal(t, 400, budgetBytes(types.Options{MaxTokens: 100, MaxBytes: 1000}))
+	assert.Equal(t, 300, budgetBytes(types.Options{MaxTokens: 100, MaxBytes: 300}))
+}
+
+// TestDegradeOrder tests that low-priority references are degraded first
+func TestDegradeOrder(t *testing.T) {
+	// Given: References at different depths, reasons and use counts
+	refs := []types.Reference{
+		{Symbol: types.Symbol{Name: "direct"}, Depth: 1, Reason: "direct-call", Uses: 1},
+		{Symbol: types.Symbol{Name: "deep"}, Depth: 2, Reason: "direct-call", Uses: 5},
+		{Symbol: types.Symbol{Name: "impl"}, Depth: 1, Reason: "implements-interface", Uses: 1},
+		{Symbol: types.Symbol{Name: "hot"}, Depth: 1, Reason: "direct-call", Uses: 3},
+	}
+
+	// When: We order them for degradation
+	order := degradeOrder(refs)
+
+	// Then: Deeper first, then weaker reasons, then less used
+	var names []string
+	for _, i := range order {
+		names = append(names, refs[i].Symbol.Name)
+	}
+	assert.Equal(t, []string{"deep", "impl", "direct", "hot"}, names)
+}
+
+// TestExtractWithinBudget tests that rendered output always fits the budget
+func TestExtractWithinBudget(t *testing.T) {
+	root := filepath.Join("..", "..", "examples", "ex1")
+	file := filepath.Join(root, "pkg", "math", "add.go")
+	target := types.Target{Root: root, File: file, Line: 7, Column: 1}
+
+	// Given: The size of the untrimmed extract
+	full, err := ExtractAndFormat(context.Background(), target, types.Options{Depth: 1})
+	require.NoError(t, err)
+	require.Nil(t, full.Extract.Budget)
+
+	for _, format := range []string{"markdown", "json", "result"} {
+		t.Run(format, func(t *testing.T) {
+			untrimmed, err := ExtractAndFormat(context.Background(), target, types.Options{Depth: 1, Format: format})
+			require.NoError(t, err)
+
+			// When: We ask for noticeably less than that
+			limit := len(untrimmed.Rendered) - 200
+			result, err := ExtractAndFormat(context.Background(), target, types.Options{
+				Depth:    1,
+				Format:   format,
+				MaxBytes: limit,
+			})
+
+			// Then: The output should fit and record what was trimmed
+			require.NoError(t, err)
+			assert.LessOrEqual(t, len(result.Rendered), limit)
+			require.NotNil(t, result.Extract.Budget)
+			assert.Equal(t, limit, result.Extract.Budget.MaxBytes)
+			assert.NotEmpty(t, append(result.Extract.Budget.Stubbed, result.Extract.Budget.Omitted...))
+		})
+	}
+}
+
+// TestExtractBudgetStubsBeforeOmitting tests that references degrade to signatures first
+func TestExtractBudgetStubsBeforeOmitting(t *testing.T) {
+	root := filepath.Join("..", "..", "examples", "ex1")
+	file := filepath.Join(root, "pkg", "math", "add.go")
+	target := types.Target{Root: root, File: file, Line: 7, Column: 1}
+
+	untrimmed, err := ExtractAndFormat(context.Background(), target, types.Options{Depth: 1})
+	require.NoError(t, err)
+
+	// Given: A budget just below the full markdown size
+	result, err := ExtractAndFormat(context.Background(), target, types.Options{
+		Depth:    1,
+		MaxBytes: len(untrimmed.Rendered) - 20,
+	})
+
+	// Then: validateInputs should survive as a signature
+	require.NoError(t, err)
+	assert.Empty(t, result.Extract.Budget.Omitted)
+	assert.Contains(t, result.Rendered, "func validateInputs(a, b int) bool\n")
+	assert.NotContains(t, result.Rendered, "return a >= 0 && b >= 0")
+	assert.Contains(t, result.Rendered, "## Budget")
+}
+
+// TestExtractBudgetTruncatesTarget tests the last resort of shortening the target
+func TestExtractBudgetTruncatesTarget(t *testing.T) {
+	root := filepath.Join("..", "..", "examples", "ex1")
+	file := filepath.Join(root, "pkg", "math", "add.go")
+	target := types.Target{Root: root, File: file, Line: 7, Column: 1}
+
+	// Given: A budget too small for anything but part of the target
+	bare, err := ExtractAndFormat(context.Background(), target, types.Options{Depth: 0})
+	require.NoError(t, err)
+	limit := len(bare.Rendered) + 250
+
+	// When: We extract with dependencies anyway
+	result, err := ExtractAndFormat(context.Background(), target, types.Options{Depth: 1, MaxBytes: limit})
+
+	// Then: Everything else should go and the target be cut short
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(result.Rendered), limit)
+	assert.True(t, result.Extract.Budget.TargetTruncated)
+	assert.True(t, strings.Contains(result.Rendered, "truncated to fit budget"))
+
+	// And: A budget that cannot hold even the header is an error
+	_, err = ExtractAndFormat(context.Background(), target, types.Options{Depth: 1, MaxBytes: 50})
+	assert.Error(t, err)
+}
@@ -6,18 +6,20 @@ import (
 	"go/ast"
 	"go/token"
 	gotypes "go/types"
+	"time"
 
-	"github.com/extract-scope-go/go-scope/internal/extract/di"
 	"github.com/extract-scope-go/go-scope/internal/types"
 	"golang.org/x/tools/go/packages"
 )
 
 // Collector gathers dependencies for a target symbol using depth-limited BFS
 type Collector struct {
-	pkgs     []*packages.Package
-	fset     *token.FileSet
-	visited  visitedSet
-	maxDepth int
+	pkgs      []*packages.Package
+	fset      *token.FileSet
+	visited   visitedSet
+	maxDepth  int
+	fullDepth int // Deepest level rendered as full code (0 = all)
+	stubDepth int // Deepest level rendered as signatures (0 = all)
 }
 
 // NewCollector creates a new dependency collector
@@ -30,6 +32,29 @@ func NewCollector(pkgs []*packages.Package, fset *token.FileSet, maxDepth int) *
 	}
 }
 
+// SetRenderDepths chooses how references are rendered by depth: full code up
+// to fullDepth, signature and doc up to stubDepth, and name only beyond that.
+// Zero disables the respective limit.
+func (c *Collector) SetRenderDepths(fullDepth, stubDepth int) {
+	c.fullDepth = fullDepth
+	c.stubDepth = stubDepth
+}
+
+// applyRenderMode sets a reference's rendering mode from its depth
+func (c *Collector) applyRenderMode(ref *types.Reference) {
+	switch {
+	case c.stubDepth > 0 && ref.Depth > c.stubDepth:
+		ref.Render = "name"
+	case c.fullDepth > 0 && ref.Depth > c.fullDepth:
+		ref.Render = "signature"
+		if ref.Signature == "" {
+			ref.Signature = signatureOf(ref.Symbol)
+		}
+	default:
+		ref.Render = "full"
+	}
+}
+
 // Collect gathers dependencies starting from target symbol
 func (c *Collector) Collect(target *types.Symbol) ([]types.Reference, []string, error) {
 	if c.maxDepth == 0 {
@@ -78,12 +103,13 @@ func (c *Collector) Collect(target *types.Symbol) ([]types.Reference, []string,
 
 		// Add to results
 		for _, ref := range refs {
+			c.applyRenderMode(&ref)
 			references = append(references, ref)
 
 			// Add referenced objects to queue if not external
 			if !ref.External && ref.Symbol.Name != "" {
 				// Try to find and queue this reference
-				refPkg, refNode, err := c.findSymbolByName(ref.Symbol.Package, ref.Symbol.Name)
+				refPkg, refNode, err := c.findSymbolByName(ref.Symbol.Package, ref.Symbol.Receiver, ref.Symbol.Name)
 				if err == nil && refNode != nil {
 					refObj := c.getObjectFromNode(refPkg, refNode)
 					if refObj != nil {
@@ -172,8 +198,8 @@ func (c *Collector) findSymbolNode(sym *types.Symbol) (*packages.Package, ast.No
 	return pkg, foundNode, nil
 }
 
-// findSymbolByName finds a symbol by package and name
-func (c *Collector) findSymbolByName(pkgPath, name string) (*packages.Package, ast.Node, error) {
+// findSymbolByName finds a symbol by package, receiver (methods only) and name
+func (c *Collector) findSymbolByName(pkgPath, recv, name string) (*packages.Package, ast.Node, error) {
 	// Find the package
 	var pkg *packages.Package
 	for _, p := range c.pkgs {
@@ -195,9 +221,9 @@ func (c *Collector) findSymbolByName(pkgPath, name string) (*packages.Package, a
 				return false
 			}
 
-			// Check for function declarations
+			// Check for function declarations, telling methods apart by receiver
 			if fn, ok := n.(*ast.FuncDecl); ok {
-				if fn.Name.Name == name {
+				if fn.Name.Name == name && c.receiverBase(fn) == types.ReceiverBase(recv) {
 					foundNode = fn
 					return false
 				}
@@ -222,6 +248,15 @@ func (c *Collector) findSymbolByName(pkgPath, name string) (*packages.Package, a
 	return nil, nil, fmt.Errorf("symbol not found: %s.%s", pkgPath, name)
 }
 
+// receiverBase returns the receiver type name of a method declaration, or "" for functions
+func (c *Collector) receiverBase(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return ""
+	}
+	locator := &Locator{fset: c.fset}
+	return types.ReceiverBase(locator.exprToString(fn.Recv.List[0].Type))
+}
+
 // getObjectFromNode gets gotypes.Object from AST node
 func (c *Collector) getObjectFromNode(pkg *packages.Package, node ast.Node) gotypes.Object {
 	switch n := node.(type) {
@@ -303,13 +338,13 @@ func (c *Collector) findReferences(obj gotypes.Object, depth int) ([]types.Refer
 		// Check for identifiers (function calls, variable uses)
 		if ident, ok := n.(*ast.Ident); ok {
 			if usedObj := pkg.TypesInfo.Uses[ident]; usedObj != nil {
-				ref, ext := c.makeReference(usedObj, depth, obj.Name())
+				ref, ext := c.makeReference(usedObj, depth, obj)
 				if ref != nil {
 					// Check if already in references
 					found := false
-					for _, existing := range references {
-						if existing.Symbol.Package == ref.Symbol.Package &&
-							existing.Symbol.Name == ref.Symbol.Name {
+					for i := range references {
+						if references[i].Symbol.ID() == ref.Symbol.ID() {
+							references[i].Uses++
 							found = true
 							break
 						}
@@ -328,12 +363,12 @@ func (c *Collector) findReferences(obj gotypes.Object, depth int) ([]types.Refer
 		// Check for selector expressions (pkg.Function calls)
 		if sel, ok := n.(*ast.SelectorExpr); ok {
 			if selObj := pkg.TypesInfo.Uses[sel.Sel]; selObj != nil {
-				ref, ext := c.makeReference(selObj, depth, obj.Name())
+				ref, ext := c.makeReference(selObj, depth, obj)
 				if ref != nil {
 					found := false
-					for _, existing := range references {
-						if existing.Symbol.Package == ref.Symbol.Package &&
-							existing.Symbol.Name == ref.Symbol.Name {
+					for i := range references {
+						if references[i].Symbol.ID() == ref.Symbol.ID() {
+							references[i].Uses++
 							found = true
 							break
 						}
@@ -355,8 +390,9 @@ func (c *Collector) findReferences(obj gotypes.Object, depth int) ([]types.Refer
 	return references, external
 }
 
-// makeReference creates a Reference from gotypes.Object
-func (c *Collector) makeReference(obj gotypes.Object, depth int, referencedBy string) (*types.Reference, string) {
+// makeReference creates a Reference from gotypes.Object, recording the
+// referencing object by both name and fully qualified ID
+func (c *Collector) makeReference(obj gotypes.Object, depth int, referencedBy gotypes.Object) (*types.Reference, string) {
 	if obj == nil {
 		return nil, ""
 	}
@@ -386,6 +422,10 @@ func (c *Collector) makeReference(obj gotypes.Object, depth int, referencedBy st
 	switch obj.(type) {
 	case *gotypes.Func:
 		sym.Kind = "func"
+		if recv := receiverName(obj); recv != "" {
+			sym.Kind = "method"
+			sym.Receiver = recv
+		}
 	case *gotypes.TypeName:
 		sym.Kind = "type"
 	case *gotypes.Var:
@@ -410,12 +450,14 @@ func (c *Collector) makeReference(obj gotypes.Object, depth int, referencedBy st
 	}
 
 	ref := &types.Reference{
-		Symbol:       sym,
-		Depth:        depth,
-		External:     isExternal,
-		Stub:         isExternal,
-		ReferencedBy: referencedBy,
-		Reason:       "direct-call", // Simplified for now
+		Symbol:         sym,
+		Depth:          depth,
+		External:       isExternal,
+		Stub:           isExternal,
+		ReferencedBy:   referencedBy.Name(),
+		ReferencedByID: objectID(referencedBy),
+		Uses:           1,
+		Reason:         "direct-call", // Simplified for now
 	}
 
 	// Create external reference string
@@ -519,6 +561,20 @@ func (c *Collector) makeKey(obj gotypes.Object) symbolKey {
 
 // ExtractSymbol is the main entry point for symbol extraction
 func ExtractSymbol(ctx context.Context, target types.Target, opts types.Options) (*types.Result, error) {
+	start := time.Now()
+
+	// Step 1: Load packages
+	locator := NewLocator()
+	if err := locator.loadPackages(target.Root, target.File); err != nil {
+		return nil, fmt.Errorf("failed to locate symbol: failed to load packages: %w", err)
+	}
+
+	return extractLoaded(ctx, locator, target, opts, start)
+}
+
+// extractLoaded extracts a symbol from the locator's already loaded packages.
+// Time since start up to now is recorded as package loading.
+func extractLoaded(ctx context.Context, locator *Locator, target types.Target, opts types.Options, start time.Time) (*types.Result, error) {
 	// Set defaults
 	if opts.Depth < 0 {
 		opts.Depth = 1
@@ -526,20 +582,32 @@ func ExtractSymbol(ctx context.Context, target types.Target, opts types.Options)
 	if opts.Format == "" {
 		opts.Format = "markdown"
 	}
+	if opts.FullDepth > 0 && opts.StubDepth > 0 && opts.StubDepth < opts.FullDepth {
+		return nil, fmt.Errorf("stub depth %d is lower than full depth %d", opts.StubDepth, opts.FullDepth)
+	}
 
-	// Step 1: Locate the target symbol
-	locator := NewLocator()
-	symbol, err := locator.LocateSymbol(target.Root, target.File, target.Line, target.Column)
+	metadata := types.Metadata{
+		ExtractedAt: start,
+		Options:     opts,
+	}
+	phase := time.Now()
+	metadata.Timings.Load = phase.Sub(start)
+
+	// Locate the target symbol
+	symbol, err := locator.locate(target.File, target.Line, target.Column)
 	if err != nil {
 		return nil, fmt.Errorf("failed to locate symbol: %w", err)
 	}
+	metadata.Timings.Locate, phase = time.Since(phase), time.Now()
 
 	// Step 2: Collect dependencies
 	collector := NewCollector(locator.pkgs, locator.fset, opts.Depth)
+	collector.SetRenderDepths(opts.FullDepth, opts.StubDepth)
 	references, external, err := collector.Collect(symbol)
 	if err != nil {
 		return nil, fmt.Errorf("failed to collect dependencies: %w", err)
 	}
+	metadata.Timings.Collect, phase = time.Since(phase), time.Now()
 
 	// Step 3: Analyze interfaces and implementations
 	allSymbols := []types.Symbol{*symbol}
@@ -548,39 +616,95 @@ func ExtractSymbol(ctx context.Context, target types.Target, opts types.Options)
 	}
 
 	interfaceAnalyzer := NewInterfaceAnalyzer(locator.pkgs, locator.fset)
+	interfaceAnalyzer.SetIndex(locator.implementationIndex())
 	interfaceMappings := interfaceAnalyzer.AnalyzeInterfaces(allSymbols)
+	interfaceGaps := interfaceAnalyzer.FindGaps(allSymbols, interfaceMappings)
 
 	// Add interface relationship references
 	interfaceRefs := ExtractInterfaceReferences(interfaceMappings, opts.Depth+1)
+	for i := range interfaceRefs {
+		collector.applyRenderMode(&interfaceRefs[i])
+	}
 	references = append(references, interfaceRefs...)
+	metadata.Timings.Interfaces, phase = time.Since(phase), time.Now()
 
 	// Step 4: Detect DI framework and analyze bindings
-	diDetector := di.NewDetector(locator.pkgs, locator.fset)
+	diDetector, warning := locator.diDetector()
+	if warning != "" {
+		metadata.Warnings = append(metadata.Warnings, warning)
+	}
 	detectedFramework := diDetector.DetectFramework()
 	diBindings := diDetector.AnalyzeDIBindings(allSymbols)
+	var diPackages []types.DIPackage
+	for _, pkg := range diDetector.PackageFrameworks() {
+		for _, sym := range allSymbols {
+			if sym.Package == pkg.Package {
+				diPackages = append(diPackages, pkg)
+				break
+			}
+		}
+	}
+	var options *types.ConstructorOptions
+	if isConstructor(interfaceAnalyzer, diBindings, *symbol) {
+		options = diDetector.ConstructorOptions(*symbol, allSymbols)
+	}
+	metadata.Timings.DI, phase = time.Since(phase), time.Now()
+
+	// Step 5: Follow untrusted input through the target (security mode)
+	var taints []types.TaintPath
+	if opts.Taint {
+		taints = taintThrough(taintPaths(locator, taintDepth), *symbol)
+	}
 
-	// Step 5: Build extract
+	// Step 6: Trace the errors the target can return
+	errorPaths := errorPaths(locator, *symbol, references)
+
+	// Step 7: Summarize goroutines, channels, locks and contexts
+	concurrency := concurrency(locator, *symbol, references)
+
+	// Step 8: Build extract
 	extract := types.Extract{
 		Target:              *symbol,
 		References:          references,
 		External:            external,
 		InterfaceMappings:   interfaceMappings,
+		InterfaceGaps:       interfaceGaps,
 		DIBindings:          diBindings,
 		DetectedDIFramework: detectedFramework,
-	}
+		DIFrameworks:        diDetector.DetectFrameworks(),
+		DIPackages:          diPackages,
+		Options:             options,
+		Taints:              taints,
+		ErrorPaths:          errorPaths,
+		Concurrency:         concurrency,
+	}
+	locator.roleClassifier().ClassifyExtract(&extract)
+	classifyEffects(locator, &extract)
+	metadata.Timings.Analysis = time.Since(phase)
+
+	// Step 9: Build result (formatting is done by the API layer to avoid circular imports)
+	fillModuleMetadata(&metadata, locator, symbol, target.Root)
+	metadata.TotalSymbols = len(references) + 1 // +1 for target
+	metadata.TotalLines = countLines(*symbol, references)
+	metadata.Timings.Total = time.Since(metadata.ExtractedAt)
 
-	// Step 4: Format output (will be done by API layer to avoid circular imports)
-	rendered := "" // Empty for now, API layer will format
-
-	// Step 5: Build result
 	result := &types.Result{
 		Extract:  extract,
-		Rendered: rendered,
-		Metadata: types.Metadata{
-			Options:      opts,
-			TotalSymbols: len(references) + 1, // +1 for target
-		},
+		Metadata: metadata,
 	}
 
 	return result, nil
 }
+
+// isConstructor reports whether a target's options are worth describing: it
+// is the provider of a DI binding, or it builds a type of its own package, so
+// that a function merely taking a config struct is not mistaken for one
+func isConstructor(analyzer *InterfaceAnalyzer, bindings []types.DIBinding, target types.Symbol) bool {
+	for _, binding := range bindings {
+		if binding.Provider.ID() == target.ID() {
+			return true
+		}
+	}
+	typeName := analyzer.constructorType(target)
+	return typeName != nil && typeName.Pkg() != nil && typeName.Pkg().Path() == target.Package
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/extract-scope-go/go-scope/internal/types"
 	"github.com/stretchr/testify/assert"
@@ -208,3 +209,130 @@ func TestCollectUnexportedSymbol(t *testing.T) {
 	}
 	assert.True(t, found, "Should include unexported symbol from same package")
 }
+
+// TestCollectRecordsReferencingID tests that references carry the referencing symbol's ID
+func TestCollectRecordsReferencingID(t *testing.T) {
+	// Given: Add calls validateInputs
+	root := filepath.Join("..", "..", "examples", "ex1")
+	file := filepath.Join(root, "pkg", "math", "add.go")
+	line := 7
+
+	// When: We collect with depth 1
+	result, err := ExtractAndFormat(context.Background(), types.Target{
+		Root:   root,
+		File:   file,
+		Line:   line,
+		Column: 1,
+	}, types.Options{
+		Depth: 1,
+	})
+
+	// Then: validateInputs should point back to Add by its fully qualified ID
+	require.NoError(t, err)
+
+	found := false
+	for _, ref := range result.Extract.References {
+		if ref.Symbol.Name == "validateInputs" {
+			found = true
+			assert.Equal(t, "Add", ref.ReferencedBy)
+			assert.Equal(t, result.Extract.Target.ID(), ref.ReferencedByID)
+			assert.Equal(t, "example.com/ex1/pkg/math.Add", ref.ReferencedByID)
+		}
+	}
+	assert.True(t, found, "Should have found validateInputs dependency")
+}
+
+// TestExtractMetadata tests that extraction metadata is populated
+func TestExtractMetadata(t *testing.T) {
+	// Given: Example 1, a main module declaring go 1.22
+	root := filepath.Join("..", "..", "examples", "ex1")
+	file := filepath.Join(root, "pkg", "math", "add.go")
+
+	// When: We extract Add
+	result, err := ExtractSymbol(context.Background(), types.Target{
+		Root:   root,
+		File:   file,
+		Line:   7,
+		Column: 1,
+	}, types.Options{
+		Depth: 1,
+	})
+
+	// Then: Module, Go version, size and timing should be recorded
+	require.NoError(t, err)
+	meta := result.Metadata
+	assert.False(t, meta.ExtractedAt.IsZero())
+	assert.Equal(t, "example.com/ex1", meta.Module)
+	assert.Equal(t, "(devel)", meta.ModuleVersion)
+	assert.Equal(t, "go1.22", meta.GoVersion)
+	assert.NotEmpty(t, meta.Toolchain)
+	assert.Equal(t, len(result.Extract.References)+1, meta.TotalSymbols)
+
+	// Add (7 lines) plus validateInputs (3 lines), each counted once
+	assert.Equal(t, 10, meta.TotalLines)
+
+	assert.Greater(t, meta.Timings.Load, time.Duration(0))
+	assert.GreaterOrEqual(t, meta.Timings.Total,
+		meta.Timings.Load+meta.Timings.Locate+meta.Timings.Collect+meta.Timings.Interfaces+meta.Timings.DI+meta.Timings.Analysis)
+}
+
+// TestApplyRenderMode tests choosing full, signature or name rendering by depth
+func TestApplyRenderMode(t *testing.T) {
+	// Given: A collector showing full code to depth 1 and signatures to depth 3
+	collector := NewCollector(nil, nil, 5)
+	collector.SetRenderDepths(1, 3)
+
+	code := "func Helper(x int) int {\n\treturn x\n}"
+	want := map[int]string{1: "full", 2: "signature", 3: "signature", 4: "name", 5: "name"}
+
+	for depth, mode := range want {
+		ref := types.Reference{Symbol: types.Symbol{Name: "Helper", Kind: "func", Code: code}, Depth: depth}
+
+		// When: We apply the render mode
+		collector.applyRenderMode(&ref)
+
+		// Then: The mode follows the depth limits
+		assert.Equal(t, mode, ref.Render, "depth %d", depth)
+		if mode == "signature" {
+			assert.Equal(t, "func Helper(x int) int", ref.Signature)
+		}
+		assert.Equal(t, code, ref.Symbol.Code, "code is kept for the result format")
+	}
+
+	// Zero limits render everything in full
+	unlimited := NewCollector(nil, nil, 5)
+	ref := types.Reference{Depth: 5}
+	unlimited.applyRenderMode(&ref)
+	assert.Equal(t, "full", ref.Render)
+}
+
+// TestExtractRejectsStubDepthBelowFullDepth tests that signatures cannot stop before full code does
+func TestExtractRejectsStubDepthBelowFullDepth(t *testing.T) {
+	// Given: A workspace for example 1
+	ws := loadExample(t, "ex1")
+	target := types.Target{File: filepath.Join(ws.Root(), "pkg", "math", "add.go"), Line: 7, Column: 1}
+
+	// When: The stub depth is lower than the full depth
+	_, err := ws.Extract(context.Background(), target, types.Options{Depth: 3, FullDepth: 2, StubDepth: 1})
+
+	// Then: The extraction fails
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "stub depth 1 is lower than full depth 2")
+}
+
+// TestExtractNoConstructorOptions tests that functions taking a config struct without constructing anything have no options
+func TestExtractNoConstructorOptions(t *testing.T) {
+	// Given: extractLoaded, taking types.Options and returning a result built elsewhere
+	ws, err := LoadWorkspace(filepath.Join("..", ".."))
+	require.NoError(t, err)
+	sym, err := ws.Resolve("extractLoaded")
+	require.NoError(t, err)
+
+	// When: We extract it
+	result, err := ws.Extract(context.Background(), types.Target{File: sym.File, Line: sym.Line, Column: 1}, types.Options{Depth: 0})
+
+	// Then: No constructor options are described
+	require.NoError(t, err)
+	assert.Nil(t, result.Extract.Options)
+	assert.NotContains(t, result.Rendered, "Constructor Options")
+}
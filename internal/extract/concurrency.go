@@ -0,0 +1,418 @@
+package extract
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	gotypes "go/types"
+	"sort"
+	"strings"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+)
+
+// concurrencyCalls are the sync and errgroup methods reported as sites, by
+// object ID
+var concurrencyCalls = map[string]string{
+	"sync.Mutex.Lock":                        "lock",
+	"sync.Mutex.Unlock":                      "unlock",
+	"sync.RWMutex.Lock":                      "lock",
+	"sync.RWMutex.Unlock":                    "unlock",
+	"sync.RWMutex.RLock":                     "rlock",
+	"sync.RWMutex.RUnlock":                   "runlock",
+	"sync.WaitGroup.Add":                     "wg-add",
+	"sync.WaitGroup.Done":                    "wg-done",
+	"sync.WaitGroup.Wait":                    "wg-wait",
+	"golang.org/x/sync/errgroup.Group.Go":    "errgroup-go",
+	"golang.org/x/sync/errgroup.Group.TryGo": "errgroup-go",
+	"golang.org/x/sync/errgroup.Group.Wait":  "errgroup-wait",
+}
+
+// unlockKinds are the site kinds releasing each kind of lock
+var unlockKinds = map[string]string{"lock": "unlock", "rlock": "runlock"}
+
+// lockMethods are the mutex methods of each lock site kind
+var lockMethods = map[string]string{"lock": "Lock", "unlock": "Unlock", "rlock": "RLock", "runlock": "RUnlock"}
+
+// concurrencyScan collects the concurrency sites and issues of functions
+type concurrencyScan struct {
+	a      *flowAnalysis
+	result *types.Concurrency
+}
+
+// concurrency summarizes the goroutines, channel operations, locks, wait
+// groups, errgroups and context arguments of the target and its referenced
+// functions, and of the methods of a target type. It flags locks released
+// without defer, goroutines started without a context, sends on unbuffered
+// channels inside loops and functions replacing their context with
+// context.Background or context.TODO. It returns nil when none of the
+// functions does anything concurrent.
+func concurrency(locator *Locator, target types.Symbol, references []types.Reference) *types.Concurrency {
+	a := newFlowAnalysis(locator, 0)
+	scope := map[string]bool{target.ID(): true}
+	for _, ref := range references {
+		scope[ref.Symbol.ID()] = true
+	}
+
+	var fns []*flowFunc
+	for obj, fn := range a.funcs {
+		id := objectID(obj)
+		if scope[id] || strings.HasPrefix(id, target.ID()+".") {
+			fns = append(fns, fn)
+		}
+	}
+	sort.Slice(fns, func(i, j int) bool {
+		pi, pj := locator.fset.Position(fns[i].decl.Pos()), locator.fset.Position(fns[j].decl.Pos())
+		if pi.Filename != pj.Filename {
+			return pi.Filename < pj.Filename
+		}
+		return pi.Offset < pj.Offset
+	})
+
+	s := &concurrencyScan{a: a, result: &types.Concurrency{}}
+	for _, fn := range fns {
+		s.function(fn)
+	}
+	if len(s.result.Functions) == 0 {
+		return nil
+	}
+	return s.result
+}
+
+// function records the sites of one function and the issues among them
+func (s *concurrencyScan) function(fn *flowFunc) {
+	summary := types.ConcurrencyFunction{
+		Function: objectID(fn.obj),
+		Context:  hasContextParam(fn.obj.Type().(*gotypes.Signature)),
+	}
+	var locks []types.ConcurrencySite
+	deferredUnlocks := make(map[string]bool) // Kind and value of deferred unlocks
+
+	ast.Inspect(fn.decl.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.GoStmt:
+			value := gotypes.ExprString(node.Call.Fun)
+			if _, ok := node.Call.Fun.(*ast.FuncLit); ok {
+				value = "func literal"
+			}
+			summary.Sites = append(summary.Sites, s.site(node, "go", value))
+			if !passesContext(fn, node.Call) {
+				s.issue(fn, node, "goroutine-without-context", "goroutine started without a context, so it cannot be canceled")
+			}
+		case *ast.SendStmt:
+			value := gotypes.ExprString(node.Chan)
+			summary.Sites = append(summary.Sites, s.site(node, "send", value))
+			if inLoop(fn, node) && !isSelectCase(fn, node) && s.unbuffered(fn, node.Chan) {
+				s.issue(fn, node, "unbuffered-send-in-loop", fmt.Sprintf("send on unbuffered channel %s in a loop blocks every iteration until a receiver is ready", value))
+			}
+		case *ast.UnaryExpr:
+			if node.Op == token.ARROW {
+				summary.Sites = append(summary.Sites, s.site(node, "receive", gotypes.ExprString(node.X)))
+			}
+		case *ast.SelectStmt:
+			summary.Sites = append(summary.Sites, s.site(node, "select", ""))
+		case *ast.RangeStmt:
+			if t := fn.pkg.TypesInfo.TypeOf(node.X); t != nil {
+				if _, ok := t.Underlying().(*gotypes.Chan); ok {
+					summary.Sites = append(summary.Sites, s.site(node, "receive", gotypes.ExprString(node.X)))
+				}
+			}
+		case *ast.CallExpr:
+			site, ok := s.call(fn, node, summary.Context)
+			if !ok {
+				return true
+			}
+			summary.Sites = append(summary.Sites, site)
+			if _, ok := unlockKinds[site.Kind]; ok && !site.Deferred {
+				locks = append(locks, site)
+			}
+			if site.Deferred {
+				deferredUnlocks[site.Kind+" "+site.Value] = true
+			}
+		}
+		return true
+	})
+
+	for _, lock := range locks {
+		unlock := unlockKinds[lock.Kind]
+		if deferredUnlocks[unlock+" "+lock.Value] {
+			continue
+		}
+		s.result.Issues = append(s.result.Issues, types.ConcurrencyIssue{
+			Kind:     "lock-without-defer",
+			Message:  fmt.Sprintf("%[1]s.%[2]s() has no deferred %[1]s.%[3]s(), so a panic or early return keeps it held", lock.Value, lockMethods[lock.Kind], lockMethods[unlock]),
+			Function: summary.Function,
+			File:     lock.File,
+			Line:     lock.Line,
+			Code:     lock.Code,
+		})
+	}
+
+	if len(summary.Sites) > 0 {
+		s.result.Functions = append(s.result.Functions, summary)
+	}
+}
+
+// call returns the site of a close, a channel make, a sync or errgroup
+// method, or a call taking a context, and whether the call is one
+func (s *concurrencyScan) call(fn *flowFunc, call *ast.CallExpr, hasContext bool) (types.ConcurrencySite, bool) {
+	info := fn.pkg.TypesInfo
+	if tv, ok := info.Types[call.Fun]; ok && tv.IsType() {
+		return types.ConcurrencySite{}, false
+	}
+
+	callee := calleeObject(fn.pkg, call.Fun)
+	if builtin, ok := callee.(*gotypes.Builtin); ok {
+		switch {
+		case builtin.Name() == "close" && len(call.Args) == 1:
+			return s.site(call, "close", gotypes.ExprString(call.Args[0])), true
+		case builtin.Name() == "make":
+			if t := info.TypeOf(call); t != nil {
+				if _, ok := t.Underlying().(*gotypes.Chan); ok {
+					return s.site(call, "make-chan", gotypes.ExprString(call)), true
+				}
+			}
+		}
+		return types.ConcurrencySite{}, false
+	}
+
+	if callee != nil {
+		if kind, ok := concurrencyCalls[objectID(callee)]; ok {
+			value := gotypes.ExprString(call.Fun)
+			if sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr); ok {
+				value = gotypes.ExprString(sel.X)
+			}
+			site := s.site(call, kind, value)
+			site.Deferred = isDeferred(fn, call)
+			return site, true
+		}
+		if callee.Pkg() != nil && callee.Pkg().Path() == "context" {
+			return types.ConcurrencySite{}, false
+		}
+	}
+
+	var sig *gotypes.Signature
+	if t := info.TypeOf(call.Fun); t != nil {
+		sig, _ = t.Underlying().(*gotypes.Signature)
+	}
+	if sig == nil || sig.Params().Len() == 0 || len(call.Args) == 0 || !isNamedType(sig.Params().At(0).Type(), "context", "Context") {
+		return types.ConcurrencySite{}, false
+	}
+	value := gotypes.ExprString(call.Fun)
+	if !s.newContext(fn, call.Args[0]) {
+		return s.site(call, "ctx-pass", value), true
+	}
+	if hasContext {
+		s.issue(fn, call, "context-not-propagated", fmt.Sprintf("%s gets %s instead of the function's context", value, gotypes.ExprString(call.Args[0])))
+	}
+	return s.site(call, "ctx-new", value), true
+}
+
+// site describes an operation at a node
+func (s *concurrencyScan) site(node ast.Node, kind, value string) types.ConcurrencySite {
+	pos := s.a.locator.fset.Position(node.Pos())
+	return types.ConcurrencySite{
+		Kind:  kind,
+		Value: value,
+		File:  pos.Filename,
+		Line:  pos.Line,
+		Code:  s.a.sourceLine(pos.Filename, pos.Line),
+	}
+}
+
+// issue records a likely mistake at a node
+func (s *concurrencyScan) issue(fn *flowFunc, node ast.Node, kind, message string) {
+	pos := s.a.locator.fset.Position(node.Pos())
+	s.result.Issues = append(s.result.Issues, types.ConcurrencyIssue{
+		Kind:     kind,
+		Message:  message,
+		Function: objectID(fn.obj),
+		File:     pos.Filename,
+		Line:     pos.Line,
+		Code:     s.a.sourceLine(pos.Filename, pos.Line),
+	})
+}
+
+// newContext reports whether a context argument is a fresh root context:
+// a context.Background or context.TODO call, or a variable assigned one
+func (s *concurrencyScan) newContext(fn *flowFunc, expr ast.Expr) bool {
+	expr = ast.Unparen(expr)
+	if call, ok := expr.(*ast.CallExpr); ok {
+		callee := calleeObject(fn.pkg, call.Fun)
+		if callee == nil {
+			return false
+		}
+		id := objectID(callee)
+		return id == "context.Background" || id == "context.TODO"
+	}
+
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	v, ok := fn.pkg.TypesInfo.Uses[ident].(*gotypes.Var)
+	if !ok {
+		return false
+	}
+	for _, site := range s.a.sites[v] {
+		if site.fn.pkg.TypesInfo.Defs[site.ident] != v {
+			continue
+		}
+		if value := assignedValue(site.fn, site.ident); value != nil {
+			return s.newContext(site.fn, value)
+		}
+	}
+	return false
+}
+
+// unbuffered reports whether a channel is known to be made without a
+// buffer, from the make assigned to its variable or field
+func (s *concurrencyScan) unbuffered(fn *flowFunc, ch ast.Expr) bool {
+	var obj gotypes.Object
+	switch e := ast.Unparen(ch).(type) {
+	case *ast.Ident:
+		obj = fn.pkg.TypesInfo.Uses[e]
+	case *ast.SelectorExpr:
+		obj = fn.pkg.TypesInfo.Uses[e.Sel]
+	}
+	if obj == nil {
+		return false
+	}
+
+	found := false
+	for _, site := range s.a.sites[obj] {
+		call, ok := ast.Unparen(assignedValue(site.fn, site.ident)).(*ast.CallExpr)
+		if !ok {
+			continue
+		}
+		if builtin, ok := calleeObject(site.fn.pkg, call.Fun).(*gotypes.Builtin); !ok || builtin.Name() != "make" {
+			continue
+		}
+		if len(call.Args) < 2 {
+			found = true
+			continue
+		}
+		size := site.fn.pkg.TypesInfo.Types[call.Args[1]].Value
+		if size == nil || constant.Sign(size) != 0 {
+			return false
+		}
+		found = true
+	}
+	return found
+}
+
+// assignedValue returns the expression stored into the variable or field
+// an identifier names, by an assignment, a declaration or a composite
+// literal key, or nil
+func assignedValue(fn *flowFunc, ident *ast.Ident) ast.Expr {
+	var node ast.Node = ident
+	if sel, ok := fn.parent(ident).(*ast.SelectorExpr); ok && sel.Sel == ident {
+		node = sel
+	}
+
+	switch parent := fn.parent(node).(type) {
+	case *ast.AssignStmt:
+		if len(parent.Lhs) != len(parent.Rhs) {
+			return nil
+		}
+		for i, lhs := range parent.Lhs {
+			if lhs == node {
+				return parent.Rhs[i]
+			}
+		}
+	case *ast.ValueSpec:
+		if len(parent.Names) != len(parent.Values) {
+			return nil
+		}
+		for i, name := range parent.Names {
+			if name == node {
+				return parent.Values[i]
+			}
+		}
+	case *ast.KeyValueExpr:
+		if parent.Key == node {
+			return parent.Value
+		}
+	}
+	return nil
+}
+
+// hasContextParam reports whether a function takes a context.Context
+func hasContextParam(sig *gotypes.Signature) bool {
+	for i := 0; i < sig.Params().Len(); i++ {
+		if isNamedType(sig.Params().At(i).Type(), "context", "Context") {
+			return true
+		}
+	}
+	return false
+}
+
+// passesContext reports whether a goroutine gets a context: as an argument,
+// or captured by the function literal it runs
+func passesContext(fn *flowFunc, call *ast.CallExpr) bool {
+	info := fn.pkg.TypesInfo
+	for _, arg := range call.Args {
+		if isNamedType(info.TypeOf(arg), "context", "Context") {
+			return true
+		}
+	}
+
+	lit, ok := call.Fun.(*ast.FuncLit)
+	if !ok {
+		return false
+	}
+	captured := false
+	ast.Inspect(lit.Body, func(n ast.Node) bool {
+		ident, ok := n.(*ast.Ident)
+		if !ok || captured {
+			return !captured
+		}
+		v, ok := info.Uses[ident].(*gotypes.Var)
+		if ok && (v.Pos() < lit.Pos() || v.Pos() >= lit.End()) && isNamedType(v.Type(), "context", "Context") {
+			captured = true
+		}
+		return true
+	})
+	return captured
+}
+
+// isDeferred reports whether a call or other node runs when the function
+// returns: it is deferred, or in a deferred function literal
+func isDeferred(fn *flowFunc, node ast.Node) bool {
+	for n := fn.parent(node); n != nil; n = fn.parent(n) {
+		switch node := n.(type) {
+		case *ast.DeferStmt:
+			return true
+		case *ast.FuncLit:
+			outer, ok := fn.parent(node).(*ast.CallExpr)
+			if !ok || outer.Fun != node {
+				return false
+			}
+			_, ok = fn.parent(outer).(*ast.DeferStmt)
+			return ok
+		}
+	}
+	return false
+}
+
+// inLoop reports whether a node runs in a loop of its function or function
+// literal
+func inLoop(fn *flowFunc, node ast.Node) bool {
+	for n := fn.parent(node); n != nil; n = fn.parent(n) {
+		switch n.(type) {
+		case *ast.ForStmt, *ast.RangeStmt:
+			return true
+		case *ast.FuncLit, *ast.FuncDecl:
+			return false
+		}
+	}
+	return false
+}
+
+// isSelectCase reports whether a send is the communication of a select
+// case, which does not block the other cases
+func isSelectCase(fn *flowFunc, send *ast.SendStmt) bool {
+	clause, ok := fn.parent(send).(*ast.CommClause)
+	return ok && clause.Comm == send
+}
@@ -0,0 +1,131 @@
+package extract
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// concurrencyIssues lists issues as "kind function line"
+func concurrencyIssues(c *types.Concurrency) []string {
+	var issues []string
+	for _, issue := range c.Issues {
+		issues = append(issues, fmt.Sprintf("%s %s %d", issue.Kind, filepath.Base(issue.Function), issue.Line))
+	}
+	return issues
+}
+
+// TestConcurrencyPool tests summarizing the goroutines, channels and locks of a type's methods
+func TestConcurrencyPool(t *testing.T) {
+	// Given: The worker pool example
+	ws := loadExample(t, "ex8")
+
+	// When: We extract the Pool type
+	c := extractAt(t, ws, types.Options{Depth: 1}, 12, 6, "worker", "pool.go").Concurrency
+	require.NotNil(t, c)
+
+	// Then: Each method with concurrent operations is summarized in source order
+	var functions []string
+	for _, fn := range c.Functions {
+		functions = append(functions, filepath.Base(fn.Function))
+	}
+	assert.Equal(t, []string{"worker.Pool.Start", "worker.Pool.Submit", "worker.Pool.Stop", "worker.Pool.Done", "worker.Pool.work", "worker.Pool.finish"}, functions)
+
+	work := c.Functions[4]
+	assert.True(t, work.Context)
+	var sites []string
+	for _, site := range work.Sites {
+		sites = append(sites, fmt.Sprintf("%s %s %d %t", site.Kind, site.Value, site.Line, site.Deferred))
+	}
+	assert.Equal(t, []string{
+		"wg-done p.wg 60 true",
+		"select  62 false",
+		"receive p.jobs 63 false",
+		"ctx-pass job 64 false",
+		"send p.results 66 false",
+		"receive ctx.Done() 67 false",
+	}, sites)
+
+	// And: The unbuffered send in the worker loop and the lock without defer are flagged,
+	// but not the buffered queue or the deferred unlock
+	assert.Equal(t, []string{
+		"unbuffered-send-in-loop worker.Pool.work 66",
+		"lock-without-defer worker.Pool.finish 75",
+	}, concurrencyIssues(c))
+}
+
+// TestConcurrencyContexts tests following contexts into errgroups, callees and goroutines
+func TestConcurrencyContexts(t *testing.T) {
+	// Given: The worker pool example
+	ws := loadExample(t, "ex8")
+
+	// When/Then: RunAll propagates its context through an errgroup
+	c := extractAt(t, ws, types.Options{Depth: 1}, 10, 0, "worker", "run.go").Concurrency
+	require.NotNil(t, c)
+	var kinds []string
+	for _, site := range c.Functions[0].Sites {
+		kinds = append(kinds, site.Kind)
+	}
+	assert.Equal(t, []string{"ctx-pass", "errgroup-go", "ctx-pass", "errgroup-wait"}, kinds)
+	assert.Empty(t, c.Issues)
+
+	// And: Retry replaces its context with context.TODO
+	c = extractAt(t, ws, types.Options{Depth: 1}, 21, 0, "worker", "run.go").Concurrency
+	require.NotNil(t, c)
+	assert.Equal(t, []string{"context-not-propagated worker.Retry 24"}, concurrencyIssues(c))
+
+	// And: Warm starts goroutines it cannot stop
+	c = extractAt(t, ws, types.Options{Depth: 1}, 32, 0, "worker", "run.go").Concurrency
+	require.NotNil(t, c)
+	assert.Equal(t, []string{"goroutine-without-context worker.Warm 34"}, concurrencyIssues(c))
+}
+
+// TestConcurrencyNone tests targets without concurrent operations
+func TestConcurrencyNone(t *testing.T) {
+	// Given: The hexagonal example's rename use case
+	ws := loadExample(t, "ex2")
+
+	// When: We extract it
+	c := extractAt(t, ws, types.Options{Depth: 1}, 17, 0, "internal", "app", "service.go").Concurrency
+
+	// Then: There is no concurrency summary
+	assert.Nil(t, c)
+}
+
+// TestConcurrencyTypeErrors tests extracting a function whose package does not type-check
+func TestConcurrencyTypeErrors(t *testing.T) {
+	// Given: A module calling, ranging over and making values of undefined names
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/broken\n\ngo 1.24\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "run.go"), []byte(`package broken
+
+import "context"
+
+func Run(ctx context.Context) {
+	undefinedFn(ctx)
+	for range undefinedVar {
+	}
+	_ = make(undefinedType)
+	done := make(chan struct{})
+	close(done)
+}
+`), 0o644))
+	ws, err := LoadWorkspace(dir)
+	require.NoError(t, err)
+
+	// When: We extract the function
+	c := extractAt(t, ws, types.Options{Depth: 1}, 5, 0, "run.go").Concurrency
+
+	// Then: The well-typed operations are summarized and the broken ones skipped
+	require.NotNil(t, c)
+	var kinds []string
+	for _, site := range c.Functions[0].Sites {
+		kinds = append(kinds, site.Kind)
+	}
+	assert.Equal(t, []string{"make-chan", "close"}, kinds)
+}
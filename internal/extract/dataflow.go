@@ -0,0 +1,1260 @@
+package extract
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	gotypes "go/types"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+	"golang.org/x/tools/go/packages"
+)
+
+// DataFlow slices the flow of the variable, parameter or field at a
+// position: forward to the statements and functions it reaches, backward to
+// those it comes from, or both. Flows follow assignments, calls (through
+// interfaces to the module's implementations, and into and out of function
+// literals), returns, struct fields and stores through shared pointers,
+// slices and maps, crossing at most depth function boundaries.
+func (w *Workspace) DataFlow(target types.Target, direction string, depth int) (*types.DataFlow, error) {
+	switch direction {
+	case "forward", "backward", "both":
+	default:
+		return nil, fmt.Errorf("unknown direction: %s", direction)
+	}
+
+	v, err := w.variableAt(target)
+	if err != nil {
+		return nil, err
+	}
+
+	a := newFlowAnalysis(w.locator, depth)
+	if direction != "backward" {
+		a.forward(v, 0, false)
+	}
+	if direction != "forward" {
+		a.backward(v, 0, false)
+	}
+
+	return &types.DataFlow{
+		Variable:  a.variable(v),
+		Direction: direction,
+		Depth:     depth,
+		Steps:     a.sortedSteps(),
+		Functions: a.functions(w.symbols),
+	}, nil
+}
+
+// variableAt finds the variable named at a position. Without a column, the
+// first variable declared or used on the line is taken.
+func (w *Workspace) variableAt(target types.Target) (*gotypes.Var, error) {
+	pkg, file := w.locator.findFileInPackages(target.File)
+	if pkg == nil {
+		return nil, fmt.Errorf("file not found in loaded packages: %s", target.File)
+	}
+	pos := w.locator.findPosition(file, target.Line, target.Column)
+	if !pos.IsValid() {
+		return nil, fmt.Errorf("invalid position: line %d, column %d", target.Line, target.Column)
+	}
+
+	var found *gotypes.Var
+	ast.Inspect(file, func(n ast.Node) bool {
+		ident, ok := n.(*ast.Ident)
+		if !ok || found != nil {
+			return found == nil
+		}
+		if w.locator.fset.Position(ident.Pos()).Line != target.Line {
+			return true
+		}
+		if target.Column > 1 && (pos < ident.Pos() || pos >= ident.End()) {
+			return true
+		}
+		obj := pkg.TypesInfo.Defs[ident]
+		if obj == nil {
+			obj = pkg.TypesInfo.Uses[ident]
+		}
+		if v, ok := obj.(*gotypes.Var); ok && ident.Name != "_" {
+			found = v
+		}
+		return true
+	})
+
+	if found == nil {
+		return nil, fmt.Errorf("no variable at %s:%d:%d", target.File, target.Line, target.Column)
+	}
+	return found, nil
+}
+
+// flowFunc is a function declaration a slice can pass through
+type flowFunc struct {
+	obj     *gotypes.Func
+	pkg     *packages.Package
+	decl    *ast.FuncDecl
+	parents map[ast.Node]ast.Node // Built on first use
+}
+
+// parent returns the node enclosing a node of the declaration
+func (fn *flowFunc) parent(node ast.Node) ast.Node {
+	if fn.parents == nil {
+		fn.parents = make(map[ast.Node]ast.Node)
+		var stack []ast.Node
+		ast.Inspect(fn.decl, func(n ast.Node) bool {
+			if n == nil {
+				stack = stack[:len(stack)-1]
+				return false
+			}
+			if len(stack) > 0 {
+				fn.parents[n] = stack[len(stack)-1]
+			}
+			stack = append(stack, n)
+			return true
+		})
+	}
+	return fn.parents[node]
+}
+
+// inLiteral reports whether a node belongs to a function literal, whose
+// returns are not the declaration's
+func (fn *flowFunc) inLiteral(node ast.Node) bool {
+	return fn.literal(node) != nil
+}
+
+// literal returns the innermost function literal a node belongs to, or nil
+func (fn *flowFunc) literal(node ast.Node) *ast.FuncLit {
+	for n := fn.parent(node); n != nil; n = fn.parent(n) {
+		if lit, ok := n.(*ast.FuncLit); ok {
+			return lit
+		}
+	}
+	return nil
+}
+
+// flowSite is an identifier declaring or referring to a variable
+type flowSite struct {
+	fn    *flowFunc
+	ident *ast.Ident
+}
+
+// flowCall is a call of a module function
+type flowCall struct {
+	fn   *flowFunc
+	call *ast.CallExpr
+}
+
+// flowKey is a variable already sliced in one direction
+type flowKey struct {
+	obj       gotypes.Object
+	direction string
+}
+
+// flowIndex indexes the variables and calls of the module's function
+// declarations. It is built once per load and shared by every analysis.
+type flowIndex struct {
+	locator *Locator
+	funcs   map[*gotypes.Func]*flowFunc
+	owners  map[gotypes.Object]*flowFunc  // Function declaring each parameter and local
+	sites   map[gotypes.Object][]flowSite // Declarations and uses of each variable
+	calls   map[*gotypes.Func][]flowCall  // Call sites of each function, through interfaces
+	returns map[*gotypes.Var]bool         // Whether a parameter reaches its function's results
+	lines   map[string][]string           // Source lines by file
+
+	literals map[gotypes.Object][]flowLiteral    // Function literals assigned to each variable
+	bound    map[*ast.FuncLit][]*gotypes.Var     // Variables each function literal is assigned to
+	aliases  map[gotypes.Object][]gotypes.Object // Variables sharing storage: pointers, slices and maps
+}
+
+// flowLiteral is a function literal and the declaration it is written in
+type flowLiteral struct {
+	fn  *flowFunc
+	lit *ast.FuncLit
+}
+
+// flowIndex returns the module's flow index, built on first use
+func (l *Locator) flowIndex() *flowIndex {
+	if l.flow == nil {
+		l.flow = newFlowIndex(l)
+	}
+	return l.flow
+}
+
+// newFlowIndex indexes the variables and calls of every function
+func newFlowIndex(locator *Locator) *flowIndex {
+	ix := &flowIndex{
+		locator: locator,
+		funcs:   make(map[*gotypes.Func]*flowFunc),
+		owners:  make(map[gotypes.Object]*flowFunc),
+		sites:   make(map[gotypes.Object][]flowSite),
+		calls:   make(map[*gotypes.Func][]flowCall),
+		returns: make(map[*gotypes.Var]bool),
+		lines:   make(map[string][]string),
+
+		literals: make(map[gotypes.Object][]flowLiteral),
+		bound:    make(map[*ast.FuncLit][]*gotypes.Var),
+		aliases:  make(map[gotypes.Object][]gotypes.Object),
+	}
+
+	var funcs []*flowFunc
+	for _, pkg := range locator.pkgs {
+		for _, astFile := range pkg.Syntax {
+			for _, decl := range astFile.Decls {
+				fd, ok := decl.(*ast.FuncDecl)
+				if !ok || fd.Body == nil {
+					continue
+				}
+				if obj, ok := pkg.TypesInfo.Defs[fd.Name].(*gotypes.Func); ok {
+					fn := &flowFunc{obj: obj, pkg: pkg, decl: fd}
+					ix.funcs[obj] = fn
+					funcs = append(funcs, fn)
+				}
+			}
+		}
+	}
+
+	for _, fn := range funcs {
+		ast.Inspect(fn.decl, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.Ident:
+				if v, ok := fn.pkg.TypesInfo.Defs[node].(*gotypes.Var); ok {
+					if !v.IsField() {
+						ix.owners[v] = fn
+					}
+					ix.sites[v] = append(ix.sites[v], flowSite{fn, node})
+				} else if v, ok := fn.pkg.TypesInfo.Uses[node].(*gotypes.Var); ok {
+					ix.sites[v] = append(ix.sites[v], flowSite{fn, node})
+				}
+			case *ast.AssignStmt:
+				if len(node.Lhs) == len(node.Rhs) {
+					for i, lhs := range node.Lhs {
+						if ident, ok := ast.Unparen(lhs).(*ast.Ident); ok {
+							ix.bind(fn, ident, node.Rhs[i])
+						}
+					}
+				}
+			case *ast.ValueSpec:
+				if len(node.Names) == len(node.Values) {
+					for i, name := range node.Names {
+						ix.bind(fn, name, node.Values[i])
+					}
+				}
+			case *ast.CallExpr:
+				if callee, ok := calleeObject(fn.pkg, node.Fun).(*gotypes.Func); ok {
+					for _, target := range ix.targets(callee) {
+						ix.calls[target] = append(ix.calls[target], flowCall{fn, node})
+						for i, arg := range node.Args {
+							if param := flowParam(target, i); param != nil {
+								ix.alias(fn, param, arg)
+							}
+						}
+					}
+				}
+			}
+			return true
+		})
+	}
+	return ix
+}
+
+// bind records a value assigned to a variable: the function literal it
+// holds, or the variable whose storage it shares
+func (ix *flowIndex) bind(fn *flowFunc, ident *ast.Ident, value ast.Expr) {
+	obj := fn.pkg.TypesInfo.Defs[ident]
+	if obj == nil {
+		obj = fn.pkg.TypesInfo.Uses[ident]
+	}
+	v, ok := obj.(*gotypes.Var)
+	if !ok {
+		return
+	}
+	if lit, ok := ast.Unparen(value).(*ast.FuncLit); ok {
+		ix.literals[v] = append(ix.literals[v], flowLiteral{fn, lit})
+		ix.bound[lit] = append(ix.bound[lit], v)
+		return
+	}
+	ix.alias(fn, v, value)
+}
+
+// alias records that a variable shares the storage of an expression: the
+// address of a variable, a slice of one, or a pointer, slice or map held by
+// one. Values stored through either then reach both.
+func (ix *flowIndex) alias(fn *flowFunc, v *gotypes.Var, expr ast.Expr) {
+	var base ast.Expr
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.UnaryExpr:
+		if e.Op == token.AND {
+			base = e.X
+		}
+	case *ast.SliceExpr:
+		base = e.X
+	case *ast.Ident:
+		if t := fn.pkg.TypesInfo.TypeOf(e); t != nil && sharesStorage(t) {
+			base = e
+		}
+	}
+	ident, ok := ast.Unparen(base).(*ast.Ident)
+	if !ok {
+		return
+	}
+	w, ok := fn.pkg.TypesInfo.Uses[ident].(*gotypes.Var)
+	if !ok || w == v {
+		return
+	}
+	ix.aliases[v] = append(ix.aliases[v], w)
+	ix.aliases[w] = append(ix.aliases[w], v)
+}
+
+// sharesStorage reports whether copies of a value refer to the same storage
+func sharesStorage(typ gotypes.Type) bool {
+	switch typ.Underlying().(type) {
+	case *gotypes.Pointer, *gotypes.Slice, *gotypes.Map:
+		return true
+	}
+	return false
+}
+
+// flowAnalysis slices value flows through the module's function
+// declarations, keeping the state of one query over the shared index
+type flowAnalysis struct {
+	*flowIndex
+	maxDepth int
+	visited  map[flowKey]int // Shallowest depth each variable was sliced at
+	steps    []types.FlowStep
+	stepKeys map[string]bool
+	trail    []types.FlowStep // Forward steps leading to the value being followed
+
+	// onCall, when set, sees every call a value followed forward is passed
+	// to, as an argument or the receiver (index -1), or converted by (callee
+	// is then a type name)
+	onCall func(fn *flowFunc, call *ast.CallExpr, callee gotypes.Object, index, depth int)
+}
+
+// newFlowAnalysis starts a query over the module's flow index
+func newFlowAnalysis(locator *Locator, maxDepth int) *flowAnalysis {
+	return &flowAnalysis{
+		flowIndex: locator.flowIndex(),
+		maxDepth:  maxDepth,
+		visited:   make(map[flowKey]int),
+		stepKeys:  make(map[string]bool),
+	}
+}
+
+// targets returns the module functions a call of callee may run: the
+// function itself, or for an interface method, the implementations' methods
+func (ix *flowIndex) targets(callee *gotypes.Func) []*gotypes.Func {
+	callee = callee.Origin()
+	recv := callee.Type().(*gotypes.Signature).Recv()
+	if recv == nil || !gotypes.IsInterface(recv.Type()) {
+		if ix.funcs[callee] != nil {
+			return []*gotypes.Func{callee}
+		}
+		return nil
+	}
+
+	named, ok := recv.Type().(*gotypes.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return nil
+	}
+	index := ix.locator.implementationIndex()
+	iface := types.Symbol{Package: named.Obj().Pkg().Path(), Name: named.Obj().Name()}
+
+	var targets []*gotypes.Func
+	for _, impl := range index.Implementations(iface) {
+		typeName := index.byID[impl.Type.ID()]
+		if typeName == nil {
+			continue
+		}
+		obj, _, _ := gotypes.LookupFieldOrMethod(gotypes.NewPointer(typeName.Type()), false, callee.Pkg(), callee.Name())
+		if method, ok := obj.(*gotypes.Func); ok && ix.funcs[method.Origin()] != nil {
+			targets = append(targets, method.Origin())
+		}
+	}
+	return targets
+}
+
+// visit reports whether a variable still needs slicing at a depth
+func (a *flowAnalysis) visit(obj gotypes.Object, direction string, depth int) bool {
+	if depth > a.maxDepth {
+		return false
+	}
+	key := flowKey{obj, direction}
+	if seen, ok := a.visited[key]; ok && seen <= depth {
+		return false
+	}
+	a.visited[key] = depth
+	return true
+}
+
+// step records a statement the value flows through, once, and returns it
+func (a *flowAnalysis) step(fn *flowFunc, node ast.Node, direction, kind, value string, depth int) types.FlowStep {
+	if depth > a.maxDepth {
+		return types.FlowStep{}
+	}
+	pos := a.locator.fset.Position(node.Pos())
+	step := types.FlowStep{
+		Direction: direction,
+		Kind:      kind,
+		Value:     value,
+		Function:  objectID(fn.obj),
+		File:      pos.Filename,
+		Line:      pos.Line,
+		Code:      a.sourceLine(pos.Filename, pos.Line),
+		Depth:     depth,
+	}
+
+	key := fmt.Sprintf("%s\x00%s\x00%s\x00%d\x00%s", direction, kind, pos.Filename, pos.Line, value)
+	if !a.stepKeys[key] {
+		a.stepKeys[key] = true
+		a.steps = append(a.steps, step)
+	}
+	return step
+}
+
+// keep adds a step to the trail until the enclosing flowUp returns
+func (a *flowAnalysis) keep(step types.FlowStep) {
+	if step.Kind != "" {
+		a.trail = append(a.trail, step)
+	}
+}
+
+// follow runs next with steps on the trail leading to the value it follows
+func (a *flowAnalysis) follow(next func(), steps ...types.FlowStep) {
+	n := len(a.trail)
+	for _, step := range steps {
+		if step.Kind != "" {
+			a.trail = append(a.trail, step)
+		}
+	}
+	next()
+	a.trail = a.trail[:n]
+}
+
+// sourceLine returns one trimmed line of a file, reading each file once
+func (ix *flowIndex) sourceLine(file string, line int) string {
+	lines, ok := ix.lines[file]
+	if !ok {
+		if content, err := os.ReadFile(file); err == nil {
+			lines = strings.Split(string(content), "\n")
+		}
+		ix.lines[file] = lines
+	}
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+	return strings.TrimSpace(lines[line-1])
+}
+
+// forward follows a variable to every expression reading it. FromCall marks
+// variables of a function entered through a call, whose results flow back
+// to that call rather than to every caller.
+func (a *flowAnalysis) forward(obj *gotypes.Var, depth int, fromCall bool) {
+	if !a.visit(obj, "forward", depth) {
+		return
+	}
+	for _, site := range a.sites[obj] {
+		if site.fn.pkg.TypesInfo.Defs[site.ident] != nil {
+			continue
+		}
+		var expr ast.Expr = site.ident
+		var load types.FlowStep
+		if sel, ok := site.fn.parent(site.ident).(*ast.SelectorExpr); ok && sel.Sel == site.ident {
+			expr = sel
+			if a.assigned(site.fn, sel) {
+				continue
+			}
+			if obj.IsField() {
+				load = a.step(site.fn, sel, "forward", "field-load", gotypes.ExprString(sel), depth)
+			}
+		} else if a.assigned(site.fn, site.ident) {
+			continue
+		}
+		a.follow(func() { a.flowUp(site.fn, expr, depth, fromCall) }, load)
+	}
+}
+
+// assigned reports whether an expression is the target of an assignment
+func (a *flowAnalysis) assigned(fn *flowFunc, expr ast.Expr) bool {
+	switch p := fn.parent(expr).(type) {
+	case *ast.AssignStmt:
+		for _, lhs := range p.Lhs {
+			if lhs == expr {
+				return true
+			}
+		}
+	case *ast.KeyValueExpr:
+		return p.Key == expr
+	}
+	return false
+}
+
+// flowUp follows a value from an expression to the statement consuming it
+func (a *flowAnalysis) flowUp(fn *flowFunc, node ast.Expr, depth int, fromCall bool) {
+	// Calls carrying the value stay on the trail until it is consumed
+	n := len(a.trail)
+	defer func() { a.trail = a.trail[:n] }()
+
+	info := fn.pkg.TypesInfo
+	for {
+		switch p := fn.parent(node).(type) {
+		case *ast.ParenExpr, *ast.BinaryExpr, *ast.UnaryExpr, *ast.StarExpr, *ast.SliceExpr, *ast.TypeAssertExpr, *ast.CompositeLit:
+			node = p.(ast.Expr)
+
+		case *ast.IndexExpr:
+			if p.X != node {
+				return // Used as an index, not as the value
+			}
+			node = p
+
+		case *ast.SelectorExpr:
+			if call, ok := fn.parent(p).(*ast.CallExpr); ok && call.Fun == p && info.Selections[p] != nil {
+				// A method called on the value
+				if !a.intoCall(fn, call, -1, depth) {
+					return
+				}
+				node = call
+				continue
+			}
+			node = p
+
+		case *ast.KeyValueExpr:
+			if p.Value != node {
+				return
+			}
+			if key, ok := p.Key.(*ast.Ident); ok {
+				if field, ok := info.Uses[key].(*gotypes.Var); ok && field.IsField() {
+					store := a.step(fn, p, "forward", "field-store", fieldName(field), depth)
+					a.follow(func() { a.forward(field, depth+1, false) }, store)
+				}
+			}
+			lit, ok := fn.parent(p).(*ast.CompositeLit)
+			if !ok {
+				return
+			}
+			node = lit
+
+		case *ast.CallExpr:
+			if p.Fun == node {
+				return
+			}
+			if tv, ok := info.Types[p.Fun]; ok && tv.IsType() {
+				if a.onCall != nil {
+					a.onCall(fn, p, calleeObject(fn.pkg, p.Fun), 0, depth)
+				}
+				node = p // Conversion
+				continue
+			}
+			index := 0
+			for i, arg := range p.Args {
+				if arg == node {
+					index = i
+				}
+			}
+			if !a.intoCall(fn, p, index, depth) {
+				return
+			}
+			node = p
+
+		case *ast.AssignStmt:
+			for i, rhs := range p.Rhs {
+				if rhs != node {
+					continue
+				}
+				if len(p.Lhs) == len(p.Rhs) {
+					a.assignTo(fn, p.Lhs[i], depth, fromCall)
+				} else {
+					for _, lhs := range p.Lhs {
+						a.assignTo(fn, lhs, depth, fromCall)
+					}
+				}
+			}
+			return
+
+		case *ast.ValueSpec:
+			for i, value := range p.Values {
+				if value != node {
+					continue
+				}
+				names := p.Names
+				if len(p.Names) == len(p.Values) {
+					names = p.Names[i : i+1]
+				}
+				for _, name := range names {
+					a.assignTo(fn, name, depth, fromCall)
+				}
+			}
+			return
+
+		case *ast.ReturnStmt:
+			ret := a.step(fn, p, "forward", "return", "return", depth)
+			if lit := fn.literal(p); lit != nil {
+				// A closure's result flows to the calls of the closure
+				for _, call := range a.literalCalls(fn, lit) {
+					result := a.step(call.fn, call.call, "forward", "call-result", gotypes.ExprString(call.call.Fun), depth+1)
+					if depth+1 <= a.maxDepth {
+						a.follow(func() { a.flowUp(call.fn, call.call, depth+1, false) }, ret, result)
+					}
+				}
+				return
+			}
+			if fromCall {
+				return
+			}
+			for _, call := range a.calls[fn.obj] {
+				result := a.step(call.fn, call.call, "forward", "call-result", gotypes.ExprString(call.call.Fun), depth+1)
+				if depth+1 <= a.maxDepth {
+					a.follow(func() { a.flowUp(call.fn, call.call, depth+1, false) }, ret, result)
+				}
+			}
+			return
+
+		case *ast.RangeStmt:
+			if p.X == node {
+				for _, lhs := range []ast.Expr{p.Key, p.Value} {
+					if lhs != nil {
+						a.assignTo(fn, lhs, depth, fromCall)
+					}
+				}
+			}
+			return
+
+		case *ast.SendStmt:
+			if p.Value == node {
+				a.step(fn, p, "forward", "send", gotypes.ExprString(p.Chan), depth)
+			}
+			return
+
+		case *ast.IfStmt, *ast.SwitchStmt, *ast.ForStmt, *ast.CaseClause:
+			a.step(fn, node, "forward", "use", gotypes.ExprString(node), depth)
+			return
+
+		default:
+			return
+		}
+	}
+}
+
+// intoCall follows a value passed to a call, as an argument or the receiver
+// (index -1), into the module functions it may run. It reports whether the
+// call's result carries the value: when a callee returns the parameter, or
+// conservatively for functions outside the module. A call whose result
+// carries the value is left on the trail.
+func (a *flowAnalysis) intoCall(fn *flowFunc, call *ast.CallExpr, index, depth int) bool {
+	label := gotypes.ExprString(call.Fun)
+	callee, ok := calleeObject(fn.pkg, call.Fun).(*gotypes.Func)
+	if !ok {
+		if builtin, ok := calleeObject(fn.pkg, call.Fun).(*gotypes.Builtin); ok {
+			return builtin.Name() == "append"
+		}
+		return a.intoLiterals(fn, call, index, depth)
+	}
+
+	arg := a.step(fn, call, "forward", "call-arg", label, depth)
+	if a.onCall != nil {
+		a.onCall(fn, call, callee, index, depth)
+	}
+	targets := a.targets(callee)
+	if len(targets) == 0 {
+		a.keep(arg)
+		return true
+	}
+
+	derived := false
+	for _, target := range targets {
+		param := flowParam(target, index)
+		if param == nil {
+			continue
+		}
+		a.follow(func() { a.forward(param, depth+1, true) }, arg)
+		if a.returnsParam(a.funcs[target], param) {
+			derived = true
+		}
+	}
+	if derived {
+		a.keep(arg)
+	}
+	return derived
+}
+
+// intoLiterals follows a value passed to a call of a function value into
+// the function literals it holds. Their returns flow back to the call; a
+// function value of unknown origin conservatively carries the value.
+func (a *flowAnalysis) intoLiterals(fn *flowFunc, call *ast.CallExpr, index, depth int) bool {
+	literals := a.calledLiterals(fn, call)
+	if len(literals) == 0 || index < 0 {
+		return true
+	}
+
+	arg := a.step(fn, call, "forward", "call-arg", gotypes.ExprString(call.Fun), depth)
+	for _, literal := range literals {
+		sig, ok := literal.fn.pkg.TypesInfo.TypeOf(literal.lit).(*gotypes.Signature)
+		if !ok {
+			continue
+		}
+		if param := signatureParam(sig, index); param != nil {
+			a.follow(func() { a.forward(param, depth+1, false) }, arg)
+		}
+	}
+	return false
+}
+
+// calledLiterals returns the function literals a call may run: the literal
+// called in place, or those assigned to the called variable
+func (ix *flowIndex) calledLiterals(fn *flowFunc, call *ast.CallExpr) []flowLiteral {
+	switch f := ast.Unparen(call.Fun).(type) {
+	case *ast.FuncLit:
+		return []flowLiteral{{fn, f}}
+	case *ast.Ident:
+		if v, ok := fn.pkg.TypesInfo.Uses[f].(*gotypes.Var); ok {
+			return ix.literals[v]
+		}
+	}
+	return nil
+}
+
+// literalCalls returns the calls of a function literal: in place, or
+// through the variables it is assigned to
+func (ix *flowIndex) literalCalls(fn *flowFunc, lit *ast.FuncLit) []flowCall {
+	var node ast.Node = lit
+	for {
+		paren, ok := fn.parent(node).(*ast.ParenExpr)
+		if !ok {
+			break
+		}
+		node = paren
+	}
+	if call, ok := fn.parent(node).(*ast.CallExpr); ok && call.Fun == node {
+		return []flowCall{{fn, call}}
+	}
+
+	var calls []flowCall
+	for _, v := range ix.bound[lit] {
+		for _, site := range ix.sites[v] {
+			if call, ok := site.fn.parent(site.ident).(*ast.CallExpr); ok && call.Fun == site.ident {
+				calls = append(calls, flowCall{site.fn, call})
+			}
+		}
+	}
+	return calls
+}
+
+// assignTo follows a value into the target of an assignment
+func (a *flowAnalysis) assignTo(fn *flowFunc, lhs ast.Expr, depth int, fromCall bool) {
+	info := fn.pkg.TypesInfo
+	switch t := ast.Unparen(lhs).(type) {
+	case *ast.Ident:
+		obj := info.Defs[t]
+		if obj == nil {
+			obj = info.Uses[t]
+		}
+		if v, ok := obj.(*gotypes.Var); ok && t.Name != "_" {
+			assign := a.step(fn, t, "forward", "assign", t.Name, depth)
+			a.follow(func() {
+				if a.owners[v] != nil {
+					a.forward(v, depth, fromCall)
+				} else {
+					a.forward(v, depth+1, false) // A package-level variable
+				}
+			}, assign)
+		}
+	case *ast.SelectorExpr:
+		if v, ok := info.Uses[t.Sel].(*gotypes.Var); ok {
+			store := a.step(fn, t, "forward", "field-store", gotypes.ExprString(t), depth)
+			a.follow(func() { a.forward(v, depth+1, false) }, store)
+		}
+	case *ast.IndexExpr:
+		a.assignTo(fn, t.X, depth, fromCall) // The container now holds the value
+		a.storeThrough(fn, t, t.X, depth)
+	case *ast.StarExpr:
+		a.assignTo(fn, t.X, depth, fromCall)
+		a.storeThrough(fn, t, t.X, depth)
+	}
+}
+
+// storeThrough follows a value stored into the storage a variable refers
+// to, an element or pointee, to every variable sharing that storage
+func (a *flowAnalysis) storeThrough(fn *flowFunc, store ast.Node, expr ast.Expr, depth int) {
+	ident, ok := ast.Unparen(expr).(*ast.Ident)
+	if !ok {
+		return
+	}
+	v, ok := fn.pkg.TypesInfo.Uses[ident].(*gotypes.Var)
+	if !ok {
+		return
+	}
+
+	seen := map[gotypes.Object]bool{v: true}
+	queue := []gotypes.Object{v}
+	for len(queue) > 0 {
+		for _, other := range a.aliases[queue[0]] {
+			if seen[other] {
+				continue
+			}
+			seen[other] = true
+			queue = append(queue, other)
+
+			w := other.(*gotypes.Var)
+			d := depth
+			if a.owners[w] != fn {
+				d = depth + 1 // Shared with another function, through a call
+			}
+			step := a.step(fn, store, "forward", "alias", w.Name(), depth)
+			a.follow(func() { a.forward(w, d, false) }, step)
+		}
+		queue = queue[1:]
+	}
+}
+
+// returnsParam reports whether a parameter reaches a result of its
+// function, through the function's own assignments
+func (ix *flowIndex) returnsParam(fn *flowFunc, param *gotypes.Var) bool {
+	if result, ok := ix.returns[param]; ok {
+		return result
+	}
+	ix.returns[param] = false
+
+	info := fn.pkg.TypesInfo
+	derived := map[gotypes.Object]bool{param: true}
+	mentions := func(expr ast.Node) bool {
+		found := false
+		ast.Inspect(expr, func(n ast.Node) bool {
+			if _, ok := n.(*ast.FuncLit); ok || found {
+				return false
+			}
+			if ident, ok := n.(*ast.Ident); ok && derived[info.Uses[ident]] {
+				found = true
+			}
+			return true
+		})
+		return found
+	}
+	mark := func(lhs ast.Expr) bool {
+		ident, ok := ast.Unparen(lhs).(*ast.Ident)
+		if !ok {
+			return false
+		}
+		obj := info.Defs[ident]
+		if obj == nil {
+			obj = info.Uses[ident]
+		}
+		if obj == nil || derived[obj] {
+			return false
+		}
+		derived[obj] = true
+		return true
+	}
+
+	// Propagate through assignments until nothing changes
+	for changed := true; changed; {
+		changed = false
+		ast.Inspect(fn.decl.Body, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.FuncLit:
+				return false
+			case *ast.AssignStmt:
+				for i, rhs := range node.Rhs {
+					if !mentions(rhs) {
+						continue
+					}
+					if len(node.Lhs) == len(node.Rhs) {
+						changed = mark(node.Lhs[i]) || changed
+					} else {
+						for _, lhs := range node.Lhs {
+							changed = mark(lhs) || changed
+						}
+					}
+				}
+			case *ast.ValueSpec:
+				for _, value := range node.Values {
+					if mentions(value) {
+						for _, name := range node.Names {
+							changed = mark(name) || changed
+						}
+					}
+				}
+			case *ast.RangeStmt:
+				if mentions(node.X) {
+					for _, lhs := range []ast.Expr{node.Key, node.Value} {
+						if lhs != nil {
+							changed = mark(lhs) || changed
+						}
+					}
+				}
+			}
+			return true
+		})
+	}
+
+	result := false
+	ast.Inspect(fn.decl.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			for _, expr := range node.Results {
+				if mentions(expr) {
+					result = true
+				}
+			}
+		}
+		return !result
+	})
+	ix.returns[param] = result
+	return result
+}
+
+// backward follows a variable to every assignment of it, and a parameter
+// to the arguments of its calls
+func (a *flowAnalysis) backward(obj *gotypes.Var, depth int, fromCall bool) {
+	if !a.visit(obj, "backward", depth) {
+		return
+	}
+
+	if fn := a.owners[obj]; fn != nil {
+		if index, ok := paramIndex(fn.obj, obj); ok {
+			a.step(fn, a.declaration(obj), "backward", "param", obj.Name(), depth)
+			if !fromCall {
+				for _, call := range a.calls[fn.obj] {
+					arg := callArg(call.fn, call.call, index)
+					if arg == nil {
+						continue
+					}
+					a.step(call.fn, call.call, "backward", "call-arg", gotypes.ExprString(arg), depth+1)
+					a.sources(call.fn, arg, depth+1, false)
+				}
+			}
+		}
+	}
+
+	for _, site := range a.sites[obj] {
+		a.definedAt(site, obj, depth, fromCall)
+	}
+}
+
+// declaration returns the identifier declaring a variable
+func (a *flowAnalysis) declaration(obj *gotypes.Var) ast.Node {
+	for _, site := range a.sites[obj] {
+		if site.fn.pkg.TypesInfo.Defs[site.ident] == obj {
+			return site.ident
+		}
+	}
+	return a.owners[obj].decl
+}
+
+// definedAt follows the value a site assigns to a variable back to its sources
+func (a *flowAnalysis) definedAt(site flowSite, obj *gotypes.Var, depth int, fromCall bool) {
+	fn := site.fn
+	var target ast.Expr = site.ident
+	if sel, ok := fn.parent(site.ident).(*ast.SelectorExpr); ok && sel.Sel == site.ident {
+		target = sel
+	}
+	kind := "assign"
+	if obj.IsField() {
+		kind = "field-store"
+	}
+
+	switch p := fn.parent(target).(type) {
+	case *ast.AssignStmt:
+		for i, lhs := range p.Lhs {
+			if lhs != target {
+				continue
+			}
+			a.step(fn, p, "backward", kind, gotypes.ExprString(target), depth)
+			if len(p.Lhs) == len(p.Rhs) {
+				a.sources(fn, p.Rhs[i], depth, fromCall)
+			} else {
+				a.sources(fn, p.Rhs[0], depth, fromCall)
+			}
+		}
+
+	case *ast.ValueSpec:
+		for i, name := range p.Names {
+			if name != target || len(p.Values) == 0 {
+				continue
+			}
+			a.step(fn, p, "backward", kind, name.Name, depth)
+			if len(p.Names) == len(p.Values) {
+				a.sources(fn, p.Values[i], depth, fromCall)
+			} else {
+				a.sources(fn, p.Values[0], depth, fromCall)
+			}
+		}
+
+	case *ast.KeyValueExpr:
+		if p.Key == target {
+			a.step(fn, p, "backward", kind, fieldName(obj), depth)
+			a.sources(fn, p.Value, depth, fromCall)
+		}
+
+	case *ast.RangeStmt:
+		if p.Key == target || p.Value == target {
+			a.step(fn, p, "backward", "range", gotypes.ExprString(p.X), depth)
+			a.sources(fn, p.X, depth, fromCall)
+		}
+
+	case *ast.UnaryExpr:
+		// Filled in through its address by a call: json.Unmarshal(data, &v)
+		call, ok := fn.parent(p).(*ast.CallExpr)
+		if !ok || p.Op != token.AND {
+			return
+		}
+		a.step(fn, call, "backward", "call-result", gotypes.ExprString(call.Fun), depth)
+		a.callSources(fn, call, depth, fromCall, p)
+	}
+}
+
+// sources follows an expression back to the variables, fields and calls
+// its value comes from
+func (a *flowAnalysis) sources(fn *flowFunc, expr ast.Expr, depth int, fromCall bool) {
+	info := fn.pkg.TypesInfo
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		if v, ok := info.Uses[e].(*gotypes.Var); ok {
+			if a.owners[v] != nil {
+				a.backward(v, depth, fromCall)
+			} else {
+				a.backward(v, depth+1, false)
+			}
+		}
+
+	case *ast.SelectorExpr:
+		v, ok := info.Uses[e.Sel].(*gotypes.Var)
+		if !ok {
+			return // A method value
+		}
+		if v.IsField() {
+			a.step(fn, e, "backward", "field-load", gotypes.ExprString(e), depth)
+			a.sources(fn, e.X, depth, fromCall)
+		}
+		a.backward(v, depth+1, false)
+
+	case *ast.CallExpr:
+		if tv, ok := info.Types[e.Fun]; ok && tv.IsType() {
+			for _, arg := range e.Args {
+				a.sources(fn, arg, depth, fromCall)
+			}
+			return
+		}
+		callee, _ := calleeObject(fn.pkg, e.Fun).(*gotypes.Func)
+		if callee == nil {
+			a.callSources(fn, e, depth, fromCall, nil)
+			return
+		}
+		a.step(fn, e, "backward", "call-result", gotypes.ExprString(e.Fun), depth)
+		targets := a.targets(callee)
+		if len(targets) == 0 {
+			a.callSources(fn, e, depth, fromCall, nil)
+			return
+		}
+		for _, target := range targets {
+			a.returnSources(a.funcs[target], depth+1)
+		}
+
+	case *ast.CompositeLit:
+		for _, elt := range e.Elts {
+			if kv, ok := elt.(*ast.KeyValueExpr); ok {
+				elt = kv.Value
+			}
+			a.sources(fn, elt, depth, fromCall)
+		}
+	case *ast.BinaryExpr:
+		a.sources(fn, e.X, depth, fromCall)
+		a.sources(fn, e.Y, depth, fromCall)
+	case *ast.UnaryExpr:
+		a.sources(fn, e.X, depth, fromCall)
+	case *ast.StarExpr:
+		a.sources(fn, e.X, depth, fromCall)
+	case *ast.IndexExpr:
+		a.sources(fn, e.X, depth, fromCall)
+	case *ast.SliceExpr:
+		a.sources(fn, e.X, depth, fromCall)
+	case *ast.TypeAssertExpr:
+		a.sources(fn, e.X, depth, fromCall)
+	}
+}
+
+// callSources follows the result of a call outside the module back to its
+// arguments and receiver, except the argument receiving the result
+func (a *flowAnalysis) callSources(fn *flowFunc, call *ast.CallExpr, depth int, fromCall bool, skip ast.Expr) {
+	if sel, ok := call.Fun.(*ast.SelectorExpr); ok && fn.pkg.TypesInfo.Selections[sel] != nil {
+		a.sources(fn, sel.X, depth, fromCall)
+	}
+	for _, arg := range call.Args {
+		if arg != skip {
+			a.sources(fn, arg, depth, fromCall)
+		}
+	}
+}
+
+// returnSources follows the results a function returns back to their
+// sources, skipping errors
+func (a *flowAnalysis) returnSources(fn *flowFunc, depth int) {
+	if depth > a.maxDepth {
+		return
+	}
+	ast.Inspect(fn.decl.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			for _, result := range node.Results {
+				if typ := fn.pkg.TypesInfo.TypeOf(result); typ != nil && typ.String() == "error" {
+					continue
+				}
+				if ident, ok := result.(*ast.Ident); ok && ident.Name == "nil" {
+					continue
+				}
+				a.step(fn, node, "backward", "return", gotypes.ExprString(result), depth)
+				a.sources(fn, result, depth, true)
+			}
+		}
+		return true
+	})
+}
+
+// flowParam returns a function's parameter at an argument index, the
+// variadic parameter past the end, or the receiver for index -1
+func flowParam(fn *gotypes.Func, index int) *gotypes.Var {
+	sig := fn.Type().(*gotypes.Signature)
+	if index < 0 {
+		return sig.Recv()
+	}
+	return signatureParam(sig, index)
+}
+
+// signatureParam returns the parameter receiving the argument at an index,
+// the variadic parameter for those past the end
+func signatureParam(sig *gotypes.Signature, index int) *gotypes.Var {
+	params := sig.Params()
+	if index >= params.Len() {
+		if !sig.Variadic() {
+			return nil
+		}
+		index = params.Len() - 1
+	}
+	return params.At(index)
+}
+
+// paramIndex returns the argument index of a parameter, -1 for the receiver
+func paramIndex(fn *gotypes.Func, v *gotypes.Var) (int, bool) {
+	sig := fn.Type().(*gotypes.Signature)
+	if sig.Recv() == v {
+		return -1, true
+	}
+	for i := 0; i < sig.Params().Len(); i++ {
+		if sig.Params().At(i) == v {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// callArg returns the argument a call passes at an index, or its receiver
+// for index -1
+func callArg(fn *flowFunc, call *ast.CallExpr, index int) ast.Expr {
+	if index < 0 {
+		if sel, ok := call.Fun.(*ast.SelectorExpr); ok && fn.pkg.TypesInfo.Selections[sel] != nil {
+			return sel.X
+		}
+		return nil
+	}
+	if index >= len(call.Args) {
+		return nil
+	}
+	return call.Args[index]
+}
+
+// fieldName names a struct field by its struct, "User.Name", when known
+func fieldName(field *gotypes.Var) string {
+	if field.Origin() != nil && field.Pkg() != nil {
+		scope := field.Pkg().Scope()
+		for _, name := range scope.Names() {
+			typeName, ok := scope.Lookup(name).(*gotypes.TypeName)
+			if !ok {
+				continue
+			}
+			if st, ok := typeName.Type().Underlying().(*gotypes.Struct); ok {
+				for i := 0; i < st.NumFields(); i++ {
+					if st.Field(i) == field.Origin() {
+						return typeName.Name() + "." + field.Name()
+					}
+				}
+			}
+		}
+	}
+	return field.Name()
+}
+
+// variable describes the variable a slice starts from
+func (a *flowAnalysis) variable(v *gotypes.Var) types.FlowVariable {
+	pos := a.locator.fset.Position(v.Pos())
+	result := types.FlowVariable{
+		Name: v.Name(),
+		Type: gotypes.TypeString(v.Type(), nil),
+		Kind: "var",
+		File: pos.Filename,
+		Line: pos.Line,
+	}
+
+	fn := a.owners[v]
+	switch {
+	case v.IsField():
+		result.Kind = "field"
+		result.Name = fieldName(v)
+	case fn != nil:
+		result.Function = objectID(fn.obj)
+		result.Kind = "local"
+		sig := fn.obj.Type().(*gotypes.Signature)
+		if sig.Recv() == v {
+			result.Kind = "receiver"
+		}
+		if _, ok := paramIndex(fn.obj, v); ok && sig.Recv() != v {
+			result.Kind = "param"
+		}
+		for i := 0; i < sig.Results().Len(); i++ {
+			if sig.Results().At(i) == v {
+				result.Kind = "result"
+			}
+		}
+	}
+	return result
+}
+
+// sortedSteps orders the steps by direction, depth and position
+func (a *flowAnalysis) sortedSteps() []types.FlowStep {
+	steps := append([]types.FlowStep{}, a.steps...)
+	sort.SliceStable(steps, func(i, j int) bool {
+		if steps[i].Direction != steps[j].Direction {
+			return steps[i].Direction == "backward"
+		}
+		if steps[i].Depth != steps[j].Depth {
+			return steps[i].Depth < steps[j].Depth
+		}
+		if steps[i].File != steps[j].File {
+			return steps[i].File < steps[j].File
+		}
+		return steps[i].Line < steps[j].Line
+	})
+	return steps
+}
+
+// functions returns the symbols of the functions the steps pass through,
+// in order of first step
+func (a *flowAnalysis) functions(symbols []indexedSymbol) []types.Symbol {
+	byID := make(map[string]types.Symbol)
+	for _, entry := range symbols {
+		if fn, ok := entry.obj.(*gotypes.Func); ok && a.funcs[fn] != nil {
+			byID[entry.symbol.ID()] = entry.symbol
+		}
+	}
+
+	functions := []types.Symbol{}
+	seen := make(map[string]bool)
+	for _, step := range a.sortedSteps() {
+		if sym, ok := byID[step.Function]; ok && !seen[step.Function] {
+			seen[step.Function] = true
+			functions = append(functions, sym)
+		}
+	}
+	return functions
+}
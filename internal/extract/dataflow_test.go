@@ -0,0 +1,85 @@
+package extract
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flowSteps lists the steps of a slice as "kind value file:line"
+func flowSteps(flow *types.DataFlow) []string {
+	var steps []string
+	for _, step := range flow.Steps {
+		steps = append(steps, fmt.Sprintf("%s %s %s:%d", step.Kind, step.Value, filepath.Base(step.File), step.Line))
+	}
+	return steps
+}
+
+// TestDataFlowForward tests following a request parameter through an interface call
+func TestDataFlowForward(t *testing.T) {
+	// Given: The hexagonal example, whose handler passes query parameters to a port
+	ws := loadExample(t, "ex2")
+	target := types.Target{File: filepath.Join("..", "..", "examples", "ex2", "internal", "adapters", "rest", "handler.go"), Line: 20, Column: 52}
+
+	// When: We slice the request forward two functions deep
+	flow, err := ws.DataFlow(target, "forward", 2)
+	require.NoError(t, err)
+
+	// Then: The value reaches the use case behind the port, and the fields it stores
+	assert.Equal(t, "r", flow.Variable.Name)
+	assert.Equal(t, "param", flow.Variable.Kind)
+	assert.Equal(t, "*net/http.Request", flow.Variable.Type)
+
+	steps := flowSteps(flow)
+	assert.Contains(t, steps, "assign query handler.go:21")
+	assert.Contains(t, steps, "call-arg h.users.Rename handler.go:22")
+	assert.Contains(t, steps, "field-store u.Name service.go:23")
+	assert.Contains(t, steps, "field-load u.Name notifier.go:21")
+
+	var functions []string
+	for _, fn := range flow.Functions {
+		functions = append(functions, fn.Receiver+"."+fn.Name)
+	}
+	assert.Contains(t, functions, "*Handler.ServeHTTP")
+	assert.Contains(t, functions, "*UserService.Rename")
+}
+
+// TestDataFlowBackward tests tracing a parameter back to its callers' arguments
+func TestDataFlowBackward(t *testing.T) {
+	// Given: The name parameter of the rename use case
+	ws := loadExample(t, "ex2")
+	target := types.Target{File: filepath.Join("..", "..", "examples", "ex2", "internal", "app", "service.go"), Line: 17, Column: 34}
+
+	// When: We slice it backward one function deep
+	flow, err := ws.DataFlow(target, "backward", 1)
+	require.NoError(t, err)
+
+	// Then: Both callers' arguments lead back to their sources
+	steps := flowSteps(flow)
+	assert.Contains(t, steps, "param name service.go:17")
+	assert.Contains(t, steps, `call-arg "Grace" main.go:28`)
+	assert.Contains(t, steps, `call-arg query.Get("name") handler.go:22`)
+	assert.Contains(t, steps, "param r handler.go:20")
+
+	// And: Depth bounds the slice
+	flow, err = ws.DataFlow(target, "backward", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"param name service.go:17"}, flowSteps(flow))
+}
+
+// TestDataFlowErrors tests rejecting positions without a variable and unknown directions
+func TestDataFlowErrors(t *testing.T) {
+	// Given: The hexagonal example
+	ws := loadExample(t, "ex2")
+	file := filepath.Join("..", "..", "examples", "ex2", "internal", "adapters", "rest", "handler.go")
+
+	// When/Then: A comment line and a bad direction fail
+	_, err := ws.DataFlow(types.Target{File: file, Line: 19}, "forward", 2)
+	assert.Error(t, err)
+	_, err = ws.DataFlow(types.Target{File: file, Line: 20}, "sideways", 2)
+	assert.Error(t, err)
+}
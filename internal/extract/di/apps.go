@@ -0,0 +1,298 @@
+package di
+
+import (
+	"fmt"
+	"go/ast"
+	gotypes "go/types"
+	"path"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+	"golang.org/x/tools/go/packages"
+)
+
+// appIndex finds the applications built with Fx, dig and samber/do: every
+// fx.New, dig.New and do.New call is one, named after the function making it
+// ("app.main"). Values are followed through variables, parameters, function
+// results, fx.Module and fx.Options, the way Wire sets are followed from an
+// injector, so each registration can be placed in the apps it reaches.
+type appIndex struct {
+	d      *Detector
+	values map[gotypes.Object][]source // Expressions assigned to each variable, and arguments passed to each parameter
+	roots  map[*ast.CallExpr]string    // Calls creating a container → app name
+	fx     map[*ast.CallExpr][]string  // fx.Provide, Invoke, Decorate and Supply calls → apps
+}
+
+// source is an expression and the package it is written in
+type source struct {
+	pkg  *packages.Package
+	expr ast.Expr
+}
+
+// containerApps returns the module's app index, built on first use
+func (d *Detector) containerApps() *appIndex {
+	if d.apps == nil {
+		d.apps = newAppIndex(d)
+	}
+	return d.apps
+}
+
+// newAppIndex indexes the values and container roots of the loaded packages
+func newAppIndex(d *Detector) *appIndex {
+	ix := &appIndex{
+		d:      d,
+		values: make(map[gotypes.Object][]source),
+		roots:  make(map[*ast.CallExpr]string),
+		fx:     make(map[*ast.CallExpr][]string),
+	}
+
+	params := make(map[*gotypes.Func]*gotypes.Signature)
+	for _, pkg := range d.pkgs {
+		for _, astFile := range pkg.Syntax {
+			for _, decl := range astFile.Decls {
+				if fd, ok := decl.(*ast.FuncDecl); ok {
+					if fn, ok := pkg.TypesInfo.Defs[fd.Name].(*gotypes.Func); ok {
+						params[fn] = fn.Type().(*gotypes.Signature)
+					}
+				}
+			}
+		}
+	}
+
+	taken := make(map[string]int)
+	for _, pkg := range d.pkgs {
+		for _, astFile := range pkg.Syntax {
+			for _, decl := range astFile.Decls {
+				enclosing := declName(decl)
+				ast.Inspect(decl, func(n ast.Node) bool {
+					switch node := n.(type) {
+					case *ast.AssignStmt:
+						if len(node.Lhs) == len(node.Rhs) {
+							for i := range node.Lhs {
+								ix.assign(pkg, node.Lhs[i], node.Rhs[i])
+							}
+						}
+					case *ast.ValueSpec:
+						if len(node.Names) == len(node.Values) {
+							for i := range node.Names {
+								ix.assign(pkg, node.Names[i], node.Values[i])
+							}
+						}
+					case *ast.CallExpr:
+						if isContainerRoot(pkg, node) {
+							name := path.Base(pkg.PkgPath) + "." + enclosing
+							if taken[name]++; taken[name] > 1 {
+								name += fmt.Sprintf("#%d", taken[name])
+							}
+							ix.roots[node] = name
+						}
+						fn, ok := usedObject(pkg, node.Fun).(*gotypes.Func)
+						if !ok || params[fn.Origin()] == nil {
+							return true
+						}
+						sig := params[fn.Origin()]
+						last := sig.Params().Len() - 1
+						for i, arg := range node.Args {
+							if i > last {
+								if !sig.Variadic() || last < 0 {
+									break
+								}
+								i = last
+							}
+							param := sig.Params().At(i)
+							ix.values[param] = append(ix.values[param], source{pkg, arg})
+						}
+					}
+					return true
+				})
+			}
+		}
+	}
+
+	for _, pkg := range d.pkgs {
+		for _, astFile := range pkg.Syntax {
+			ast.Inspect(astFile, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok || fxFunc(pkg, call) != "New" {
+					return true
+				}
+				app := ix.roots[call]
+				seen := make(map[any]bool)
+				for _, arg := range call.Args {
+					ix.follow(pkg, arg, seen, func(pkg *packages.Package, expr ast.Expr) bool {
+						return ix.fxOption(pkg, expr, app, seen)
+					})
+				}
+				return true
+			})
+		}
+	}
+
+	return ix
+}
+
+// assign records an expression assigned to a variable
+func (ix *appIndex) assign(pkg *packages.Package, lhs, rhs ast.Expr) {
+	ident, ok := lhs.(*ast.Ident)
+	if !ok {
+		return
+	}
+	if obj := pkg.TypesInfo.ObjectOf(ident); obj != nil {
+		ix.values[obj] = append(ix.values[obj], source{pkg, rhs})
+	}
+}
+
+// fxOption places the registrations of an Fx option in an app, descending
+// into fx.Module and fx.Options. It reports whether expr was an Fx option.
+func (ix *appIndex) fxOption(pkg *packages.Package, expr ast.Expr, app string, seen map[any]bool) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+
+	var options []ast.Expr
+	switch fxFunc(pkg, call) {
+	case "Provide", "Invoke", "Decorate", "Supply":
+		if !containsString(ix.fx[call], app) {
+			ix.fx[call] = append(ix.fx[call], app)
+		}
+		return true
+	case "Module":
+		if len(call.Args) > 0 {
+			options = call.Args[1:]
+		}
+	case "Options":
+		options = call.Args
+	case "":
+		return false
+	default:
+		return true
+	}
+
+	for _, option := range options {
+		ix.follow(pkg, option, seen, func(pkg *packages.Package, expr ast.Expr) bool {
+			return ix.fxOption(pkg, expr, app, seen)
+		})
+	}
+	return true
+}
+
+// of returns the apps whose container a dig or do receiver expression
+// ("c", "admin", "injector") refers to
+func (ix *appIndex) of(pkg *packages.Package, expr ast.Expr) []string {
+	var apps []string
+	var visit func(pkg *packages.Package, expr ast.Expr) bool
+	seen := make(map[any]bool)
+	visit = func(pkg *packages.Package, expr ast.Expr) bool {
+		call, ok := expr.(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+		if name, ok := ix.roots[call]; ok {
+			if !containsString(apps, name) {
+				apps = append(apps, name)
+			}
+			return true
+		}
+		if digMethod(pkg, call) == "Scope" {
+			ix.follow(pkg, call.Fun.(*ast.SelectorExpr).X, seen, visit)
+			return true
+		}
+		return false
+	}
+	ix.follow(pkg, expr, seen, visit)
+	return apps
+}
+
+// follow calls visit on expr and, unless visit handles it, on the
+// expressions its value comes from: those assigned to a variable or passed
+// to a parameter, a function's results, append's arguments, and the
+// elements of a composite literal
+func (ix *appIndex) follow(pkg *packages.Package, expr ast.Expr, seen map[any]bool, visit func(*packages.Package, ast.Expr) bool) {
+	expr = ast.Unparen(expr)
+	if seen[expr] {
+		return
+	}
+	seen[expr] = true
+	if visit(pkg, expr) {
+		return
+	}
+
+	switch e := expr.(type) {
+	case *ast.Ident, *ast.SelectorExpr:
+		for _, src := range ix.values[usedObject(pkg, e)] {
+			ix.follow(src.pkg, src.expr, seen, visit)
+		}
+	case *ast.UnaryExpr:
+		ix.follow(pkg, e.X, seen, visit)
+	case *ast.CompositeLit:
+		for _, elt := range e.Elts {
+			ix.follow(pkg, elt, seen, visit)
+		}
+	case *ast.CallExpr:
+		if builtin, ok := usedObject(pkg, e.Fun).(*gotypes.Builtin); ok && builtin.Name() == "append" {
+			for _, arg := range e.Args {
+				ix.follow(pkg, arg, seen, visit)
+			}
+			return
+		}
+		body, bodyPkg := ix.d.funcBody(pkg, e.Fun)
+		if body == nil {
+			return
+		}
+		ast.Inspect(body, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.FuncLit:
+				return false
+			case *ast.ReturnStmt:
+				for _, result := range node.Results {
+					ix.follow(bodyPkg, result, seen, visit)
+				}
+			}
+			return true
+		})
+	}
+}
+
+// isContainerRoot reports whether a call creates an Fx app, a dig container
+// or a do injector
+func isContainerRoot(pkg *packages.Package, call *ast.CallExpr) bool {
+	if fxFunc(pkg, call) == "New" || digFunc(pkg, call) == "New" {
+		return true
+	}
+	name, _ := doCall(pkg, call)
+	return name == "New" || name == "NewWithOpts"
+}
+
+// declName names a top-level declaration: a function, a method as
+// "Recv.Name", or the first variable of a var block
+func declName(decl ast.Decl) string {
+	switch dd := decl.(type) {
+	case *ast.FuncDecl:
+		if dd.Recv != nil && len(dd.Recv.List) > 0 {
+			recv := dd.Recv.List[0].Type
+			if star, ok := recv.(*ast.StarExpr); ok {
+				recv = star.X
+			}
+			if ident, ok := recv.(*ast.Ident); ok {
+				return ident.Name + "." + dd.Name.Name
+			}
+		}
+		return dd.Name.Name
+	case *ast.GenDecl:
+		for _, spec := range dd.Specs {
+			if vs, ok := spec.(*ast.ValueSpec); ok && len(vs.Names) > 0 {
+				return vs.Names[0].Name
+			}
+		}
+	}
+	return "glob"
+}
+
+// addApps adds apps to a binding's, keeping each once
+func addApps(binding *types.DIBinding, apps []string) {
+	for _, app := range apps {
+		if !containsString(binding.Apps, app) {
+			binding.Apps = append(binding.Apps, app)
+		}
+	}
+}
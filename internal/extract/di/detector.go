@@ -1,9 +1,11 @@
 package di
 
 import (
+	"fmt"
 	"go/ast"
 	"go/token"
 	gotypes "go/types"
+	"strconv"
 	"strings"
 
 	"github.com/extract-scope-go/go-scope/internal/types"
@@ -12,101 +14,172 @@ import (
 
 // Detector identifies dependency injection patterns
 type Detector struct {
-	pkgs []*packages.Package
-	fset *token.FileSet
+	pkgs       []*packages.Package
+	fset       *token.FileSet
+	frameworks []Framework
+	detected   []types.DIPackage // Frameworks per package, computed on first use
+
+	requestCalls map[gotypes.Object]bool            // Functions called while serving an HTTP request, computed on first use
+	injectors    map[*packages.Package]wireInjector // Packages loaded with the wireinject tag, see wireInjectorPackage
+	apps         *appIndex                          // Fx, dig and do apps, see containerApps
 }
 
-// NewDetector creates a new DI detector
+// NewDetector creates a new DI detector for the built-in frameworks
 func NewDetector(pkgs []*packages.Package, fset *token.FileSet) *Detector {
 	return &Detector{
-		pkgs: pkgs,
-		fset: fset,
+		pkgs:       pkgs,
+		fset:       fset,
+		frameworks: []Framework{wireFramework{}, fxFramework{}, digFramework{}, doFramework{}},
+		injectors:  make(map[*packages.Package]wireInjector),
 	}
 }
 
-// DetectFramework identifies which DI framework is in use
+// Register adds a framework, detected after the built-in containers and
+// before falling back to manual constructor injection
+func (d *Detector) Register(framework Framework) {
+	d.frameworks = append(d.frameworks, framework)
+	d.detected = nil
+}
+
+// DetectFramework identifies the primary DI framework: the first detected
 func (d *Detector) DetectFramework() string {
-	if d.hasWire() {
-		return "wire"
+	if frameworks := d.DetectFrameworks(); len(frameworks) > 0 {
+		return frameworks[0]
 	}
-	if d.hasFx() {
-		return "fx"
+	return "none"
+}
+
+// DetectFrameworks returns every DI framework some package uses, containers
+// in registration order, then manual
+func (d *Detector) DetectFrameworks() []string {
+	var names []string
+	for _, framework := range d.allFrameworks() {
+		if d.uses(framework.Name()) && !containsString(names, framework.Name()) {
+			names = append(names, framework.Name())
+		}
 	}
-	if d.hasManualDI() {
-		return "manual"
+	return names
+}
+
+// PackageFrameworks returns the DI frameworks each package uses, in package
+// order, omitting packages that use none. A package whose constructors take
+// dependencies counts as manual unless it uses a container.
+func (d *Detector) PackageFrameworks() []types.DIPackage {
+	if d.detected != nil {
+		return d.detected
 	}
-	return "none"
+
+	d.detected = []types.DIPackage{}
+	for _, pkg := range d.pkgs {
+		var names []string
+		for _, framework := range d.frameworks {
+			if !containsString(names, framework.Name()) && framework.Detect(d, pkg) {
+				names = append(names, framework.Name())
+			}
+		}
+		if len(names) == 0 && (manualFramework{}).Detect(d, pkg) {
+			names = append(names, "manual")
+		}
+		if len(names) > 0 {
+			d.detected = append(d.detected, types.DIPackage{Package: pkg.PkgPath, Frameworks: names})
+		}
+	}
+	return d.detected
 }
 
-// AnalyzeDIBindings extracts all DI bindings
+// AnalyzeDIBindings merges the bindings of every detected framework. Manual
+// bindings are only inferred in manual packages, and not for constructors a
+// container already provides. Providers taking options or a config struct
+// list them.
 func (d *Detector) AnalyzeDIBindings(symbols []types.Symbol) []types.DIBinding {
-	framework := d.DetectFramework()
-
-	switch framework {
-	case "wire":
-		return d.analyzeWireBindings(symbols)
-	case "fx":
-		return d.analyzeFxBindings(symbols)
-	case "manual":
-		return d.analyzeManualBindings(symbols)
-	default:
-		return []types.DIBinding{}
+	bindings := []types.DIBinding{}
+	bound := make(map[string]bool)
+
+	for _, framework := range d.allFrameworks() {
+		if !d.uses(framework.Name()) {
+			continue
+		}
+		for _, binding := range framework.Analyze(d, symbols) {
+			if binding.Framework == "manual" && bound[binding.Provider.ID()] {
+				continue
+			}
+			bound[binding.Provider.ID()] = true
+			binding.Options = d.ConstructorOptions(binding.Provider, symbols)
+			bindings = append(bindings, binding)
+		}
 	}
+	return bindings
+}
+
+// allFrameworks returns the registered frameworks followed by the manual
+// fallback
+func (d *Detector) allFrameworks() []Framework {
+	return append(append([]Framework{}, d.frameworks...), manualFramework{})
+}
+
+// uses reports whether some package uses the named framework
+func (d *Detector) uses(name string) bool {
+	for _, pkg := range d.PackageFrameworks() {
+		if containsString(pkg.Frameworks, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// usesIn reports whether the package uses the named framework
+func (d *Detector) usesIn(pkgPath, name string) bool {
+	for _, pkg := range d.PackageFrameworks() {
+		if pkg.Package == pkgPath {
+			return containsString(pkg.Frameworks, name)
+		}
+	}
+	return false
 }
 
 // hasWire checks for Google Wire
 func (d *Detector) hasWire() bool {
 	for _, pkg := range d.pkgs {
-		for _, astFile := range pkg.Syntax {
-			// Check for wireinject build tag
-			for _, comment := range astFile.Comments {
-				for _, c := range comment.List {
-					text := c.Text
-					if strings.Contains(text, "wireinject") ||
-						strings.Contains(text, "+build wireinject") {
-						return true
-					}
-				}
-			}
+		if usesWire(pkg) {
+			return true
+		}
+	}
+	return false
+}
 
-			// Check for wire imports
-			for _, imp := range astFile.Imports {
-				if imp.Path.Value == `"github.com/google/wire"` {
+// usesWire reports whether a package has wireinject files or imports Wire
+func usesWire(pkg *packages.Package) bool {
+	for _, astFile := range pkg.Syntax {
+		// Check for wireinject build tag
+		for _, comment := range astFile.Comments {
+			for _, c := range comment.List {
+				if strings.Contains(c.Text, "wireinject") {
 					return true
 				}
 			}
 		}
 	}
-	return false
+	return imports(pkg, wirePath)
 }
 
 // hasFx checks for Uber Fx
 func (d *Detector) hasFx() bool {
 	for _, pkg := range d.pkgs {
-		for _, astFile := range pkg.Syntax {
-			for _, imp := range astFile.Imports {
-				if imp.Path.Value == `"go.uber.org/fx"` {
-					return true
-				}
-			}
+		if imports(pkg, fxPath) {
+			return true
 		}
 	}
 	return false
 }
 
-// hasManualDI checks for manual DI patterns (constructors that take dependencies)
-func (d *Detector) hasManualDI() bool {
-	// If we have constructor functions (New*) that take parameters, it's manual DI
-	for _, pkg := range d.pkgs {
-		scope := pkg.Types.Scope()
-		for _, name := range scope.Names() {
-			obj := scope.Lookup(name)
-			if fn, ok := obj.(*gotypes.Func); ok {
-				if strings.HasPrefix(fn.Name(), "New") {
-					sig := fn.Type().(*gotypes.Signature)
-					if sig.Params().Len() > 0 {
-						return true
-					}
+// imports reports whether any file of a package imports one of the paths
+func imports(pkg *packages.Package, paths ...string) bool {
+	for _, astFile := range pkg.Syntax {
+		for _, imp := range astFile.Imports {
+			path, _ := strconv.Unquote(imp.Path.Value)
+			for _, p := range paths {
+				if path == p {
+					return true
 				}
 			}
 		}
@@ -114,88 +187,31 @@ func (d *Detector) hasManualDI() bool {
 	return false
 }
 
-// analyzeWireBindings parses Wire configuration
-func (d *Detector) analyzeWireBindings(symbols []types.Symbol) []types.DIBinding {
-	var bindings []types.DIBinding
-
+// hasManualDI checks for manual DI patterns (constructors that take dependencies)
+func (d *Detector) hasManualDI() bool {
 	for _, pkg := range d.pkgs {
-		for _, astFile := range pkg.Syntax {
-			// Look for wire.NewSet calls
-			ast.Inspect(astFile, func(n ast.Node) bool {
-				call, ok := n.(*ast.CallExpr)
-				if !ok {
-					return true
-				}
-
-				// Check if this is wire.NewSet
-				sel, ok := call.Fun.(*ast.SelectorExpr)
-				if !ok {
-					return true
-				}
-
-				pkgIdent, ok := sel.X.(*ast.Ident)
-				if !ok || pkgIdent.Name != "wire" || sel.Sel.Name != "NewSet" {
-					return true
-				}
-
-				// Extract providers from NewSet arguments
-				for _, arg := range call.Args {
-					if ident, ok := arg.(*ast.Ident); ok {
-						// This is a provider function
-						binding := d.createBindingFromProvider(pkg, ident.Name, symbols, "wire")
-						if binding != nil {
-							bindings = append(bindings, *binding)
-						}
-					}
-				}
-
-				return true
-			})
+		if hasConstructorInjection(pkg) {
+			return true
 		}
 	}
-
-	return bindings
+	return false
 }
 
-// analyzeFxBindings parses Uber Fx configuration
-func (d *Detector) analyzeFxBindings(symbols []types.Symbol) []types.DIBinding {
-	var bindings []types.DIBinding
-
-	for _, pkg := range d.pkgs {
-		for _, astFile := range pkg.Syntax {
-			// Look for fx.Provide calls
-			ast.Inspect(astFile, func(n ast.Node) bool {
-				call, ok := n.(*ast.CallExpr)
-				if !ok {
-					return true
-				}
-
-				sel, ok := call.Fun.(*ast.SelectorExpr)
-				if !ok {
-					return true
-				}
-
-				pkgIdent, ok := sel.X.(*ast.Ident)
-				if !ok || pkgIdent.Name != "fx" || sel.Sel.Name != "Provide" {
-					return true
-				}
-
-				// Extract providers from Provide arguments
-				for _, arg := range call.Args {
-					if ident, ok := arg.(*ast.Ident); ok {
-						binding := d.createBindingFromProvider(pkg, ident.Name, symbols, "fx")
-						if binding != nil {
-							bindings = append(bindings, *binding)
-						}
-					}
-				}
-
+// hasConstructorInjection reports whether a package declares constructor
+// functions (New*) that take parameters
+func hasConstructorInjection(pkg *packages.Package) bool {
+	if pkg.Types == nil {
+		return false
+	}
+	scope := pkg.Types.Scope()
+	for _, name := range scope.Names() {
+		if fn, ok := scope.Lookup(name).(*gotypes.Func); ok && strings.HasPrefix(fn.Name(), "New") {
+			if fn.Type().(*gotypes.Signature).Params().Len() > 0 {
 				return true
-			})
+			}
 		}
 	}
-
-	return bindings
+	return false
 }
 
 // analyzeManualBindings infers DI from constructor patterns
@@ -214,24 +230,6 @@ func (d *Detector) analyzeManualBindings(symbols []types.Symbol) []types.DIBindi
 	return bindings
 }
 
-// createBindingFromProvider creates a DIBinding from a provider function
-func (d *Detector) createBindingFromProvider(pkg *packages.Package, providerName string, symbols []types.Symbol, framework string) *types.DIBinding {
-	// Find the provider function in symbols
-	var provider *types.Symbol
-	for i, sym := range symbols {
-		if sym.Name == providerName && sym.Package == pkg.PkgPath {
-			provider = &symbols[i]
-			break
-		}
-	}
-
-	if provider == nil {
-		return nil
-	}
-
-	return d.createBindingFromConstructor(*provider, symbols, framework)
-}
-
 // createBindingFromConstructor analyzes a constructor to create a binding
 func (d *Detector) createBindingFromConstructor(constructor types.Symbol, symbols []types.Symbol, framework string) *types.DIBinding {
 	// Find the function object
@@ -258,7 +256,11 @@ func (d *Detector) createBindingFromConstructor(constructor types.Symbol, symbol
 		Provider:     constructor,
 		Dependencies: []types.Symbol{},
 		Framework:    framework,
-		Scope:        "singleton", // Default assumption
+		Scope:        "singleton",
+	}
+	if d.requestScoped(funcObj) {
+		// Built anew for every request by a handler or middleware
+		binding.Scope = "request"
 	}
 
 	// Extract parameters (dependencies)
@@ -279,6 +281,7 @@ func (d *Detector) createBindingFromConstructor(constructor types.Symbol, symbol
 		if productSym != nil {
 			binding.Product = *productSym
 		}
+		addHooks(binding, closeHooks(returnType)...)
 	}
 
 	return binding
@@ -303,3 +306,232 @@ func (d *Detector) findSymbolForType(typ gotypes.Type, symbols []types.Symbol) *
 
 	return nil
 }
+
+// relevantBindings keeps the bindings whose provider, product or a
+// dependency is among the symbols
+func relevantBindings(bindings []types.DIBinding, symbols []types.Symbol) []types.DIBinding {
+	ids := make(map[string]bool, len(symbols))
+	for _, sym := range symbols {
+		ids[sym.ID()] = true
+	}
+
+	relevant := []types.DIBinding{}
+	for _, binding := range bindings {
+		keep := ids[binding.Provider.ID()] || binding.Product.Name != "" && ids[binding.Product.ID()]
+		for _, dep := range binding.Dependencies {
+			keep = keep || ids[dep.ID()]
+		}
+		if keep {
+			relevant = append(relevant, binding)
+		}
+	}
+	return relevant
+}
+
+// funcSymbol returns the symbol of a function expression: a declared
+// function or method, or a function literal named like the compiler does
+// ("main.func1")
+func (d *Detector) funcSymbol(pkg *packages.Package, expr ast.Expr, literals map[*ast.FuncLit]string, symbols []types.Symbol) types.Symbol {
+	if lit, ok := ast.Unparen(expr).(*ast.FuncLit); ok {
+		pos := d.position(lit.Pos())
+		return types.Symbol{
+			Package: pkg.PkgPath,
+			Name:    literals[lit],
+			Kind:    "func",
+			File:    pos.Filename,
+			Line:    pos.Line,
+			EndLine: d.position(lit.End()).Line,
+		}
+	}
+
+	if obj := usedObject(pkg, expr); obj != nil {
+		return d.objectSymbol(obj, symbols)
+	}
+	return types.Symbol{Package: pkg.PkgPath, Name: gotypes.ExprString(expr), Kind: "func"}
+}
+
+// funcBody returns the body of a function expression and the package
+// declaring it
+func (d *Detector) funcBody(pkg *packages.Package, expr ast.Expr) (*ast.BlockStmt, *packages.Package) {
+	if lit, ok := ast.Unparen(expr).(*ast.FuncLit); ok {
+		return lit.Body, pkg
+	}
+
+	fn, ok := usedObject(pkg, expr).(*gotypes.Func)
+	if !ok {
+		return nil, nil
+	}
+	if decl, p := d.declaration(fn); decl != nil {
+		return decl.Body, p
+	}
+	return nil, nil
+}
+
+// declaration returns the declaration of a module function and the package
+// declaring it
+func (d *Detector) declaration(fn *gotypes.Func) (*ast.FuncDecl, *packages.Package) {
+	fn = fn.Origin()
+	if fn.Pkg() == nil {
+		return nil, nil
+	}
+	for _, p := range d.pkgs {
+		if p.PkgPath != fn.Pkg().Path() {
+			continue
+		}
+		for _, astFile := range p.Syntax {
+			for _, decl := range astFile.Decls {
+				if fd, ok := decl.(*ast.FuncDecl); ok && p.TypesInfo.Defs[fd.Name] == fn {
+					return fd, p
+				}
+			}
+		}
+	}
+	return nil, nil
+}
+
+// typeSymbol returns the module symbol declaring a type, looking through
+// pointers and slices, or nil for built-in and external types
+func (d *Detector) typeSymbol(typ gotypes.Type, symbols []types.Symbol) *types.Symbol {
+	switch t := typ.(type) {
+	case *gotypes.Pointer:
+		return d.typeSymbol(t.Elem(), symbols)
+	case *gotypes.Slice:
+		return d.typeSymbol(t.Elem(), symbols)
+	case *gotypes.Named:
+		obj := t.Origin().Obj()
+		if obj.Pkg() == nil || !d.inModule(obj.Pkg().Path()) {
+			return nil
+		}
+		sym := d.objectSymbol(obj, symbols)
+		return &sym
+	}
+	return nil
+}
+
+// objectSymbol returns the symbol for a declared object, preferring the
+// full copy among symbols
+func (d *Detector) objectSymbol(obj gotypes.Object, symbols []types.Symbol) types.Symbol {
+	pos := d.position(obj.Pos())
+	sym := types.Symbol{
+		Name:     obj.Name(),
+		Exported: obj.Exported(),
+		File:     pos.Filename,
+		Line:     pos.Line,
+	}
+	if obj.Pkg() != nil {
+		sym.Package = obj.Pkg().Path()
+	}
+
+	switch o := obj.(type) {
+	case *gotypes.Func:
+		sym.Kind = "func"
+		if recv := o.Type().(*gotypes.Signature).Recv(); recv != nil {
+			sym.Kind = "method"
+			sym.Receiver = gotypes.TypeString(recv.Type(), gotypes.RelativeTo(obj.Pkg()))
+		}
+	case *gotypes.TypeName:
+		switch o.Type().Underlying().(type) {
+		case *gotypes.Interface:
+			sym.Kind = "interface"
+		case *gotypes.Struct:
+			sym.Kind = "struct"
+		default:
+			sym.Kind = "type"
+		}
+	case *gotypes.Var:
+		sym.Kind = "var"
+	case *gotypes.Const:
+		sym.Kind = "const"
+	}
+
+	id := sym.ID()
+	for _, candidate := range symbols {
+		if candidate.ID() == id {
+			return candidate
+		}
+	}
+	return sym
+}
+
+// inModule reports whether a package path is one of the loaded packages
+func (d *Detector) inModule(pkgPath string) bool {
+	for _, pkg := range d.pkgs {
+		if pkg.PkgPath == pkgPath {
+			return true
+		}
+	}
+	return false
+}
+
+// position resolves a position in the detector's file set, falling back to
+// the packages' own file set
+func (d *Detector) position(pos token.Pos) token.Position {
+	if d.fset != nil && d.fset.File(pos) != nil {
+		return d.fset.Position(pos)
+	}
+	for _, pkg := range d.pkgs {
+		if pkg.Fset != nil && pkg.Fset.File(pos) != nil {
+			return pkg.Fset.Position(pos)
+		}
+	}
+	return token.Position{}
+}
+
+// usedObject returns the object an identifier, selector or instantiation
+// refers to
+func usedObject(pkg *packages.Package, expr ast.Expr) gotypes.Object {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		return pkg.TypesInfo.Uses[e]
+	case *ast.SelectorExpr:
+		return pkg.TypesInfo.Uses[e.Sel]
+	case *ast.IndexExpr:
+		return usedObject(pkg, e.X)
+	case *ast.IndexListExpr:
+		return usedObject(pkg, e.X)
+	}
+	return nil
+}
+
+// funcLiteralNames names a file's function literals as the compiler does:
+// the enclosing declaration followed by ".func1", ".func2", ...
+func funcLiteralNames(file *ast.File) map[*ast.FuncLit]string {
+	names := make(map[*ast.FuncLit]string)
+	for _, decl := range file.Decls {
+		var enclosing string
+		switch dd := decl.(type) {
+		case *ast.FuncDecl:
+			enclosing = dd.Name.Name
+		case *ast.GenDecl:
+			for _, spec := range dd.Specs {
+				if vs, ok := spec.(*ast.ValueSpec); ok && len(vs.Names) > 0 {
+					enclosing = vs.Names[0].Name
+					break
+				}
+			}
+		}
+		if enclosing == "" {
+			enclosing = "glob"
+		}
+
+		count := 0
+		ast.Inspect(decl, func(n ast.Node) bool {
+			if lit, ok := n.(*ast.FuncLit); ok {
+				count++
+				names[lit] = fmt.Sprintf("%s.func%d", enclosing, count)
+			}
+			return true
+		})
+	}
+	return names
+}
+
+// containsSymbol reports whether a symbol with the same ID is in the list
+func containsSymbol(list []types.Symbol, sym types.Symbol) bool {
+	for _, s := range list {
+		if s.ID() == sym.ID() {
+			return true
+		}
+	}
+	return false
+}
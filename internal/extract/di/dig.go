@@ -0,0 +1,198 @@
+package di
+
+import (
+	"fmt"
+	"go/ast"
+	gotypes "go/types"
+	"strconv"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+	"golang.org/x/tools/go/packages"
+)
+
+// digPath is the import path of Uber dig
+const digPath = "go.uber.org/dig"
+
+// analyzeDigBindings models dig containers from every Provide, Invoke and
+// Decorate call on a dig.Container or dig.Scope. Constructors are read like
+// Fx ones, with dig.In and dig.Out structs, and the dig.Name, dig.Group and
+// dig.As options; a child scope's name is reported as the binding's module.
+func (d *Detector) analyzeDigBindings(symbols []types.Symbol) []types.DIBinding {
+	var bindings []types.DIBinding
+
+	for _, pkg := range d.pkgs {
+		scopes := digScopes(pkg)
+		for _, astFile := range pkg.Syntax {
+			literals := funcLiteralNames(astFile)
+
+			ast.Inspect(astFile, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok || len(call.Args) == 0 {
+					return true
+				}
+
+				var kind string
+				switch digMethod(pkg, call) {
+				case "Provide":
+					kind = "provide"
+				case "Invoke":
+					kind = "invoke"
+				case "Decorate":
+					kind = "decorate"
+				default:
+					return true
+				}
+
+				sig, ok := pkg.TypesInfo.TypeOf(call.Args[0]).(*gotypes.Signature)
+				if !ok {
+					return true
+				}
+				provider := &fxProvider{expr: call.Args[0], sig: sig}
+				if kind == "provide" {
+					applyDigOptions(pkg, provider, call.Args[1:])
+				}
+
+				receiver := call.Fun.(*ast.SelectorExpr).X
+				binding := d.fxBinding(pkg, provider, kind, digScopeName(pkg, receiver, scopes), literals, symbols)
+				binding.Framework = "dig"
+				binding.Apps = d.containerApps().of(pkg, receiver)
+				if binding.Module != "" {
+					// Shared within the child scope and its descendants
+					binding.Scope = "scoped"
+				}
+				bindings = append(bindings, binding)
+				return true
+			})
+		}
+	}
+
+	return bindings
+}
+
+// applyDigOptions reads dig.Name, dig.Group and dig.As into a constructor's
+// result tags and interfaces. Unlike fx.As, each dig.As target applies to
+// every result.
+func applyDigOptions(pkg *packages.Package, provider *fxProvider, opts []ast.Expr) {
+	var tag string
+	for _, opt := range opts {
+		call, ok := opt.(*ast.CallExpr)
+		if !ok {
+			continue
+		}
+		switch digFunc(pkg, call) {
+		case "Name":
+			if args := stringArgs(call); len(args) == 1 {
+				tag += fmt.Sprintf("name:%q ", args[0])
+			}
+		case "Group":
+			if args := stringArgs(call); len(args) == 1 {
+				tag += fmt.Sprintf("group:%q ", args[0])
+			}
+		case "As":
+			for _, target := range call.Args {
+				if typ := newType(pkg, target); typ != nil {
+					provider.as = append(provider.as, []gotypes.Type{typ})
+				}
+			}
+		}
+	}
+
+	if tag != "" {
+		for i := 0; i < provider.sig.Results().Len(); i++ {
+			provider.resultTags = append(provider.resultTags, tag)
+		}
+	}
+}
+
+// digScopes maps the variables of a package holding a child scope,
+// "admin := c.Scope(\"admin\")", to the scope's name
+func digScopes(pkg *packages.Package) map[gotypes.Object]string {
+	scopes := make(map[gotypes.Object]string)
+	record := func(lhs ast.Expr, rhs ast.Expr) {
+		ident, ok := lhs.(*ast.Ident)
+		if !ok {
+			return
+		}
+		call, ok := rhs.(*ast.CallExpr)
+		if !ok || digMethod(pkg, call) != "Scope" || len(call.Args) == 0 {
+			return
+		}
+		lit, ok := call.Args[0].(*ast.BasicLit)
+		if !ok {
+			return
+		}
+		if name, err := strconv.Unquote(lit.Value); err == nil {
+			if obj := pkg.TypesInfo.ObjectOf(ident); obj != nil {
+				scopes[obj] = name
+			}
+		}
+	}
+
+	for _, astFile := range pkg.Syntax {
+		ast.Inspect(astFile, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.AssignStmt:
+				if len(node.Lhs) == len(node.Rhs) {
+					for i := range node.Lhs {
+						record(node.Lhs[i], node.Rhs[i])
+					}
+				}
+			case *ast.ValueSpec:
+				if len(node.Names) == len(node.Values) {
+					for i := range node.Names {
+						record(node.Names[i], node.Values[i])
+					}
+				}
+			}
+			return true
+		})
+	}
+	return scopes
+}
+
+// digScopeName returns the name of the child scope a receiver expression
+// refers to, or "" for a root container
+func digScopeName(pkg *packages.Package, receiver ast.Expr, scopes map[gotypes.Object]string) string {
+	if call, ok := ast.Unparen(receiver).(*ast.CallExpr); ok && digMethod(pkg, call) == "Scope" && len(call.Args) > 0 {
+		if lit, ok := call.Args[0].(*ast.BasicLit); ok {
+			name, _ := strconv.Unquote(lit.Value)
+			return name
+		}
+	}
+	if obj := usedObject(pkg, receiver); obj != nil {
+		return scopes[obj]
+	}
+	return ""
+}
+
+// digMethod returns the name of the dig.Container or dig.Scope method a
+// call invokes, or ""
+func digMethod(pkg *packages.Package, call *ast.CallExpr) string {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+	selection, ok := pkg.TypesInfo.Selections[sel]
+	if !ok || selection.Kind() != gotypes.MethodVal {
+		return ""
+	}
+	fn := selection.Obj()
+	if fn.Pkg() == nil || fn.Pkg().Path() != digPath {
+		return ""
+	}
+	return fn.Name()
+}
+
+// digFunc returns the name of the dig package function a call invokes, or ""
+func digFunc(pkg *packages.Package, call *ast.CallExpr) string {
+	fn, ok := usedObject(pkg, call.Fun).(*gotypes.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != digPath {
+		return ""
+	}
+	return fn.Name()
+}
+
+// isDigObject reports whether an object is the named dig declaration
+func isDigObject(obj gotypes.Object, name string) bool {
+	return obj.Pkg() != nil && obj.Pkg().Path() == digPath && obj.Name() == name
+}
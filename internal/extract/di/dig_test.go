@@ -0,0 +1,51 @@
+package di
+
+import (
+	"testing"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAnalyzeDigBindings tests dig constructors, options, parameter structs and scopes
+func TestAnalyzeDigBindings(t *testing.T) {
+	// Given: The dig container of the example
+	detector := loadDetector(t, "ex6")
+	require.Equal(t, "dig", detector.DetectFramework())
+
+	// When: We analyze it
+	bindings := detector.analyzeDigBindings(nil)
+
+	// Then: dig.As and dig.Name shape what constructors provide
+	var stores []types.DIValue
+	for _, binding := range bindings {
+		if binding.Provider.Name == "NewMemStore" {
+			stores = append(stores, binding.Provides...)
+		}
+	}
+	assert.Equal(t, []types.DIValue{
+		{Type: "example.com/ex6/internal/store.Store"},
+		{Type: "example.com/ex6/internal/store.Store", Name: "cache"},
+	}, stores)
+
+	// dig.In and dig.Out fields carry their tags
+	service := findBinding(t, bindings, "provide", "NewService")
+	assert.Equal(t, "dig", service.Framework)
+	assert.Equal(t, []types.DIValue{
+		{Type: "example.com/ex6/internal/store.Store"},
+		{Type: "example.com/ex6/internal/store.Store", Name: "cache", Optional: true},
+	}, service.Requires)
+	handlers := findBinding(t, bindings, "provide", "NewHandlers")
+	assert.Equal(t, []types.DIValue{{Type: "net/http.Handler", Group: "handlers"}}, handlers.Provides)
+
+	// Child scopes are modules
+	admin := findBinding(t, bindings, "provide", "NewAdmin")
+	assert.Equal(t, "admin", admin.Module)
+	logging := findBinding(t, bindings, "decorate", "WithLogging")
+	assert.Equal(t, "admin", logging.Module)
+
+	invoke := findBinding(t, bindings, "invoke", "Container.func1")
+	assert.Equal(t, "", invoke.Module)
+	assert.Equal(t, []types.DIValue{{Type: "net/http.Handler", Group: "handlers"}}, invoke.Requires)
+}
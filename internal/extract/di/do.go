@@ -0,0 +1,216 @@
+package di
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	gotypes "go/types"
+	"strings"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+	"golang.org/x/tools/go/packages"
+)
+
+// doPaths are the import paths of samber/do
+var doPaths = []string{"github.com/samber/do", "github.com/samber/do/v2"}
+
+// analyzeDoBindings models samber/do injectors. Every Provide and Override
+// variant is a binding of the service type it is instantiated with; a
+// provider function's dependencies are the services it invokes from the
+// injector. Services invoked elsewhere are reported as one invoke binding
+// per enclosing function.
+func (d *Detector) analyzeDoBindings(symbols []types.Symbol) []types.DIBinding {
+	var bindings []types.DIBinding
+
+	// Invocations inside provider bodies are their dependencies
+	type span struct{ pos, end token.Pos }
+	var providerBodies []span
+
+	for _, pkg := range d.pkgs {
+		for _, astFile := range pkg.Syntax {
+			literals := funcLiteralNames(astFile)
+
+			ast.Inspect(astFile, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				name, typ := doCall(pkg, call)
+				name = strings.Replace(name, "Override", "Provide", 1)
+				if !strings.HasPrefix(name, "Provide") || typ == nil || len(call.Args) < 2 {
+					return true
+				}
+
+				binding := types.DIBinding{
+					Dependencies: []types.Symbol{},
+					Framework:    "do",
+					Scope:        "singleton",
+					Init:         "lazy",
+					Kind:         "provide",
+				}
+				if strings.Contains(name, "Transient") {
+					binding.Scope = "transient"
+					binding.Init = ""
+				}
+
+				value := types.DIValue{Type: gotypes.TypeString(typ, nil)}
+				if strings.Contains(name, "Named") {
+					value.Name = constantString(pkg, call.Args[1])
+				}
+				binding.Provides = []types.DIValue{value}
+				binding.Apps = d.containerApps().of(pkg, call.Args[0])
+				if sym := d.typeSymbol(typ, symbols); sym != nil {
+					binding.Product = *sym
+				}
+				// The injector shuts down and health-checks the services it built
+				binding.Hooks = closeHooks(typ)
+
+				arg := call.Args[len(call.Args)-1]
+				if strings.HasSuffix(name, "Value") {
+					pos := d.position(arg.Pos())
+					binding.Kind = "supply"
+					binding.Init = "eager"
+					binding.Provider = types.Symbol{
+						Package: pkg.PkgPath,
+						Name:    supplyName(arg),
+						Kind:    "value",
+						File:    pos.Filename,
+						Line:    pos.Line,
+					}
+				} else {
+					binding.Provider = d.funcSymbol(pkg, arg, literals, symbols)
+					if body, bodyPkg := d.funcBody(pkg, arg); body != nil {
+						providerBodies = append(providerBodies, span{body.Pos(), body.End()})
+						d.addDoInvocations(&binding, bodyPkg, body, symbols)
+					}
+				}
+
+				bindings = append(bindings, binding)
+				return true
+			})
+		}
+	}
+
+	inProvider := func(node ast.Node) bool {
+		for _, body := range providerBodies {
+			if node.Pos() >= body.pos && node.End() <= body.end {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, pkg := range d.pkgs {
+		for _, astFile := range pkg.Syntax {
+			literals := funcLiteralNames(astFile)
+
+			ast.Inspect(astFile, func(n ast.Node) bool {
+				var body *ast.BlockStmt
+				var fn ast.Expr
+				switch node := n.(type) {
+				case *ast.FuncDecl:
+					body, fn = node.Body, node.Name
+				case *ast.FuncLit:
+					body, fn = node.Body, node
+				default:
+					return true
+				}
+				if body == nil || inProvider(body) {
+					return true
+				}
+
+				binding := types.DIBinding{
+					Provider:     d.funcSymbol(pkg, fn, literals, symbols),
+					Dependencies: []types.Symbol{},
+					Framework:    "do",
+					Scope:        "singleton",
+					Kind:         "invoke",
+				}
+				if decl, ok := n.(*ast.FuncDecl); ok {
+					binding.Provider = d.objectSymbol(pkg.TypesInfo.Defs[decl.Name], symbols)
+				}
+				d.addDoInvocations(&binding, pkg, body, symbols)
+				if len(binding.Requires) > 0 {
+					bindings = append(bindings, binding)
+				}
+				return true
+			})
+		}
+	}
+
+	return bindings
+}
+
+// addDoInvocations records the services a function body invokes from an
+// injector as the binding's dependencies, and the injector's apps as its own,
+// skipping nested function literals, which are bindings of their own
+func (d *Detector) addDoInvocations(binding *types.DIBinding, pkg *packages.Package, body *ast.BlockStmt, symbols []types.Symbol) {
+	ast.Inspect(body, func(n ast.Node) bool {
+		if _, ok := n.(*ast.FuncLit); ok {
+			return false
+		}
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		name, typ := doCall(pkg, call)
+		name = strings.TrimPrefix(name, "Must")
+		if !strings.HasPrefix(name, "Invoke") || typ == nil {
+			return true
+		}
+
+		value := types.DIValue{Type: gotypes.TypeString(typ, nil)}
+		if strings.Contains(name, "Named") && len(call.Args) > 1 {
+			value.Name = constantString(pkg, call.Args[1])
+		}
+		binding.Requires = append(binding.Requires, value)
+		if len(call.Args) > 0 {
+			addApps(binding, d.containerApps().of(pkg, call.Args[0]))
+		}
+		if sym := d.typeSymbol(typ, symbols); sym != nil && !containsSymbol(binding.Dependencies, *sym) {
+			binding.Dependencies = append(binding.Dependencies, *sym)
+		}
+		return true
+	})
+}
+
+// doCall returns the name of the samber/do function a call invokes and the
+// service type it is instantiated with
+func doCall(pkg *packages.Package, call *ast.CallExpr) (string, gotypes.Type) {
+	fun := call.Fun
+	switch f := fun.(type) {
+	case *ast.IndexExpr:
+		fun = f.X
+	case *ast.IndexListExpr:
+		fun = f.X
+	}
+
+	var ident *ast.Ident
+	switch f := fun.(type) {
+	case *ast.Ident:
+		ident = f
+	case *ast.SelectorExpr:
+		ident = f.Sel
+	default:
+		return "", nil
+	}
+
+	fn, ok := pkg.TypesInfo.Uses[ident].(*gotypes.Func)
+	if !ok || fn.Pkg() == nil || !containsString(doPaths, fn.Pkg().Path()) {
+		return "", nil
+	}
+	instance, ok := pkg.TypesInfo.Instances[ident]
+	if !ok || instance.TypeArgs.Len() == 0 {
+		return fn.Name(), nil
+	}
+	return fn.Name(), instance.TypeArgs.At(0)
+}
+
+// constantString returns the value of a constant string expression, or ""
+func constantString(pkg *packages.Package, expr ast.Expr) string {
+	tv, ok := pkg.TypesInfo.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return ""
+	}
+	return constant.StringVal(tv.Value)
+}
@@ -0,0 +1,42 @@
+package di
+
+import (
+	"testing"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAnalyzeDoBindings tests samber/do providers, named values and invocations
+func TestAnalyzeDoBindings(t *testing.T) {
+	// Given: The samber/do injector of the example
+	detector := loadDetector(t, "ex6")
+
+	// When: We analyze it
+	bindings := detector.analyzeDoBindings(nil)
+
+	// Then: Providers provide the type they are instantiated with
+	config := findBinding(t, bindings, "provide", "NewConfig")
+	assert.Equal(t, "do", config.Framework)
+	assert.Equal(t, []types.DIValue{{Type: "example.com/ex6/internal/mail.Config"}}, config.Provides)
+	assert.Empty(t, config.Requires)
+
+	region := findBinding(t, bindings, "supply", `"eu-west-1"`)
+	assert.Equal(t, []types.DIValue{{Type: "string", Name: "region"}}, region.Provides)
+
+	// A provider's invocations are its dependencies
+	mailer := findBinding(t, bindings, "provide", "Injector.func1")
+	assert.Equal(t, []types.DIValue{{Type: "*example.com/ex6/internal/mail.Mailer"}}, mailer.Provides)
+	assert.Equal(t, []types.DIValue{
+		{Type: "example.com/ex6/internal/mail.Config"},
+		{Type: "string", Name: "region"},
+	}, mailer.Requires)
+	assert.Equal(t, "Mailer", mailer.Product.Name)
+
+	// Invocations elsewhere are grouped by function
+	notify := findBinding(t, bindings, "invoke", "Notify")
+	assert.Equal(t, []types.DIValue{{Type: "*example.com/ex6/internal/mail.Mailer"}}, notify.Requires)
+	for _, binding := range bindings {
+		assert.NotEqual(t, "Injector", binding.Provider.Name, "provider bodies are not invocations")
+	}
+}
@@ -0,0 +1,88 @@
+package di
+
+import (
+	"github.com/extract-scope-go/go-scope/internal/types"
+	"golang.org/x/tools/go/packages"
+)
+
+// Framework detects one kind of DI container in a module's packages and
+// models its bindings. Built-in frameworks cover Wire, Fx, dig and
+// samber/do; others, such as home-grown registries, are added with
+// Detector.Register.
+type Framework interface {
+	Name() string                                                  // Reported framework name, "fx"
+	Detect(d *Detector, pkg *packages.Package) bool                // Whether the package uses the container
+	Analyze(d *Detector, symbols []types.Symbol) []types.DIBinding // Bindings involving the symbols
+}
+
+// wireFramework is Google Wire
+type wireFramework struct{}
+
+func (wireFramework) Name() string { return "wire" }
+
+func (wireFramework) Detect(d *Detector, pkg *packages.Package) bool {
+	return usesWire(pkg)
+}
+
+func (wireFramework) Analyze(d *Detector, symbols []types.Symbol) []types.DIBinding {
+	return relevantBindings(d.analyzeWireBindings(symbols), symbols)
+}
+
+// fxFramework is Uber Fx
+type fxFramework struct{}
+
+func (fxFramework) Name() string { return "fx" }
+
+func (fxFramework) Detect(d *Detector, pkg *packages.Package) bool {
+	return imports(pkg, fxPath)
+}
+
+func (fxFramework) Analyze(d *Detector, symbols []types.Symbol) []types.DIBinding {
+	return relevantBindings(d.analyzeFxBindings(symbols), symbols)
+}
+
+// digFramework is Uber dig used directly, without Fx
+type digFramework struct{}
+
+func (digFramework) Name() string { return "dig" }
+
+func (digFramework) Detect(d *Detector, pkg *packages.Package) bool {
+	return imports(pkg, digPath)
+}
+
+func (digFramework) Analyze(d *Detector, symbols []types.Symbol) []types.DIBinding {
+	return relevantBindings(d.analyzeDigBindings(symbols), symbols)
+}
+
+// doFramework is samber/do, v1 or v2
+type doFramework struct{}
+
+func (doFramework) Name() string { return "do" }
+
+func (doFramework) Detect(d *Detector, pkg *packages.Package) bool {
+	return imports(pkg, doPaths...)
+}
+
+func (doFramework) Analyze(d *Detector, symbols []types.Symbol) []types.DIBinding {
+	return relevantBindings(d.analyzeDoBindings(symbols), symbols)
+}
+
+// manualFramework is constructor injection without a container, detected in
+// packages using no container
+type manualFramework struct{}
+
+func (manualFramework) Name() string { return "manual" }
+
+func (manualFramework) Detect(d *Detector, pkg *packages.Package) bool {
+	return hasConstructorInjection(pkg)
+}
+
+func (manualFramework) Analyze(d *Detector, symbols []types.Symbol) []types.DIBinding {
+	var bindings []types.DIBinding
+	for _, binding := range d.analyzeManualBindings(symbols) {
+		if d.usesIn(binding.Provider.Package, "manual") {
+			bindings = append(bindings, binding)
+		}
+	}
+	return bindings
+}
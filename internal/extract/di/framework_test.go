@@ -0,0 +1,43 @@
+package di
+
+import (
+	"testing"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPackageFrameworks tests detecting frameworks per package in a mixed module
+func TestPackageFrameworks(t *testing.T) {
+	// Given: The Fx example, whose server package only uses constructors
+	detector := loadDetector(t, "ex4")
+
+	// When: We detect frameworks
+	frameworks := detector.DetectFrameworks()
+	packages := detector.PackageFrameworks()
+
+	// Then: Fx is the primary framework and manual injection is reported for the server package
+	assert.Equal(t, []string{"fx", "manual"}, frameworks)
+	assert.Equal(t, "fx", detector.DetectFramework())
+	assert.Contains(t, packages, types.DIPackage{Package: "example.com/ex4/internal/server", Frameworks: []string{"manual"}})
+	assert.Contains(t, packages, types.DIPackage{Package: "example.com/ex4/cmd/app", Frameworks: []string{"fx"}})
+}
+
+// TestAnalyzeDIBindingsMerged tests that a constructor bound by a container is not reported again as manual
+func TestAnalyzeDIBindingsMerged(t *testing.T) {
+	// Given: The server, whose constructor is provided to Fx
+	detector := loadDetector(t, "ex4")
+	symbols := []types.Symbol{{Name: "Server", Kind: "struct", Package: "example.com/ex4/internal/server"}}
+
+	// When: We analyze bindings for it
+	bindings := detector.AnalyzeDIBindings(symbols)
+
+	// Then: NewServer has a single Fx binding
+	var frameworks []string
+	for _, binding := range bindings {
+		if binding.Provider.Name == "NewServer" {
+			frameworks = append(frameworks, binding.Framework)
+		}
+	}
+	assert.Equal(t, []string{"fx"}, frameworks)
+}
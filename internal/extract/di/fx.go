@@ -0,0 +1,427 @@
+package di
+
+import (
+	"go/ast"
+	gotypes "go/types"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+	"golang.org/x/tools/go/packages"
+)
+
+// fxPath is the import path of Uber Fx
+const fxPath = "go.uber.org/fx"
+
+// fxProvider is a function passed to an Fx option, with its fx.Annotate
+// annotations
+type fxProvider struct {
+	expr       ast.Expr
+	sig        *gotypes.Signature
+	paramTags  []string
+	resultTags []string
+	as         [][]gotypes.Type // One list per fx.As, positional by result; nil entries are fx.Self()
+}
+
+// analyzeFxBindings models the Fx container from every fx.Provide,
+// fx.Invoke, fx.Decorate and fx.Supply in the module, wherever they are
+// nested in fx.Module and fx.Options. It follows fx.Annotate (ParamTags,
+// ResultTags, As), fx.In and fx.Out structs, named values, value groups, and
+// the lifecycle hooks each function registers.
+func (d *Detector) analyzeFxBindings(symbols []types.Symbol) []types.DIBinding {
+	var bindings []types.DIBinding
+	apps := d.containerApps()
+
+	for _, pkg := range d.pkgs {
+		for _, astFile := range pkg.Syntax {
+			literals := funcLiteralNames(astFile)
+
+			var stack []ast.Node
+			ast.Inspect(astFile, func(n ast.Node) bool {
+				if n == nil {
+					stack = stack[:len(stack)-1]
+					return true
+				}
+				stack = append(stack, n)
+
+				call, ok := n.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+
+				var kind string
+				switch fxFunc(pkg, call) {
+				case "Provide":
+					kind = "provide"
+				case "Invoke":
+					kind = "invoke"
+				case "Decorate":
+					kind = "decorate"
+				case "Supply":
+					module := fxModule(pkg, stack)
+					for _, arg := range call.Args {
+						binding := d.fxSupplyBinding(pkg, arg, module, symbols)
+						binding.Apps = apps.fx[call]
+						bindings = append(bindings, binding)
+					}
+					return true
+				default:
+					return true
+				}
+
+				module := fxModule(pkg, stack)
+				for _, arg := range call.Args {
+					provider := fxProviderOf(pkg, arg)
+					if provider == nil {
+						continue // fx.Private and other options
+					}
+					binding := d.fxBinding(pkg, provider, kind, module, literals, symbols)
+					binding.Apps = apps.fx[call]
+					bindings = append(bindings, binding)
+				}
+				return true
+			})
+		}
+	}
+
+	return bindings
+}
+
+// fxBinding builds the binding of a provided, invoked or decorating function
+func (d *Detector) fxBinding(pkg *packages.Package, p *fxProvider, kind, module string, literals map[*ast.FuncLit]string, symbols []types.Symbol) types.DIBinding {
+	binding := types.DIBinding{
+		Provider:     d.funcSymbol(pkg, p.expr, literals, symbols),
+		Dependencies: []types.Symbol{},
+		Framework:    "fx",
+		Scope:        "singleton",
+		Init:         "lazy", // Built when something first requires it
+		Kind:         kind,
+		Module:       module,
+	}
+	if kind == "invoke" {
+		binding.Init = "eager"
+	}
+
+	params := p.sig.Params()
+	for i := 0; i < params.Len(); i++ {
+		for _, value := range fxValues(params.At(i).Type(), tagAt(p.paramTags, i), "In") {
+			binding.Requires = append(binding.Requires, value.DIValue)
+			if sym := d.typeSymbol(value.typ, symbols); sym != nil && !containsSymbol(binding.Dependencies, *sym) {
+				binding.Dependencies = append(binding.Dependencies, *sym)
+			}
+		}
+	}
+
+	var hooks []string
+	if kind != "invoke" {
+		results := p.sig.Results()
+		for i := 0; i < results.Len(); i++ {
+			typ := results.At(i).Type()
+			if isError(typ) {
+				continue
+			}
+			hooks = append(hooks, closeHooks(typ)...)
+			for _, value := range fxValues(typ, tagAt(p.resultTags, i), "Out") {
+				for _, provided := range applyAs(value, p.as, i) {
+					binding.Provides = append(binding.Provides, provided.DIValue)
+					if binding.Product.Name == "" {
+						if sym := d.typeSymbol(provided.typ, symbols); sym != nil {
+							binding.Product = *sym
+						}
+					}
+				}
+			}
+		}
+	}
+
+	binding.Hooks = d.lifecycleHooks(pkg, p.expr)
+	addHooks(&binding, hooks...)
+	return binding
+}
+
+// fxSupplyBinding builds the binding of a value passed to fx.Supply
+func (d *Detector) fxSupplyBinding(pkg *packages.Package, arg ast.Expr, module string, symbols []types.Symbol) types.DIBinding {
+	pos := d.fset.Position(arg.Pos())
+	binding := types.DIBinding{
+		Provider: types.Symbol{
+			Package: pkg.PkgPath,
+			Name:    supplyName(arg),
+			Kind:    "value",
+			File:    pos.Filename,
+			Line:    pos.Line,
+		},
+		Dependencies: []types.Symbol{},
+		Framework:    "fx",
+		Scope:        "singleton",
+		Init:         "eager",
+		Kind:         "supply",
+		Module:       module,
+	}
+
+	if typ := pkg.TypesInfo.TypeOf(arg); typ != nil {
+		binding.Provides = []types.DIValue{{Type: gotypes.TypeString(typ, nil)}}
+		binding.Hooks = closeHooks(typ)
+		if sym := d.typeSymbol(typ, symbols); sym != nil {
+			binding.Product = *sym
+		}
+	}
+	return binding
+}
+
+// lifecycleHooks lists the hooks a function registers: fx.Hook literals with
+// OnStart or OnStop, and fx.StartHook, fx.StopHook or fx.StartStopHook calls
+func (d *Detector) lifecycleHooks(pkg *packages.Package, expr ast.Expr) []string {
+	body, bodyPkg := d.funcBody(pkg, expr)
+	if body == nil {
+		return nil
+	}
+
+	var hooks []string
+	add := func(hook string) {
+		for _, h := range hooks {
+			if h == hook {
+				return
+			}
+		}
+		hooks = append(hooks, hook)
+	}
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.CompositeLit:
+			if named, ok := bodyPkg.TypesInfo.TypeOf(node).(*gotypes.Named); ok && isFxObject(named.Obj(), "Hook") {
+				for _, elt := range node.Elts {
+					if kv, ok := elt.(*ast.KeyValueExpr); ok {
+						if key, ok := kv.Key.(*ast.Ident); ok {
+							add(key.Name)
+						}
+					}
+				}
+			}
+		case *ast.CallExpr:
+			switch fxFunc(bodyPkg, node) {
+			case "StartHook":
+				add("OnStart")
+			case "StopHook":
+				add("OnStop")
+			case "StartStopHook":
+				add("OnStart")
+				add("OnStop")
+			}
+		}
+		return true
+	})
+
+	return hooks
+}
+
+// fxProviderOf resolves an Fx option argument to the function it registers,
+// unwrapping fx.Annotate. Arguments that are not functions yield nil.
+func fxProviderOf(pkg *packages.Package, expr ast.Expr) *fxProvider {
+	if call, ok := expr.(*ast.CallExpr); ok && fxFunc(pkg, call) == "Annotate" && len(call.Args) > 0 {
+		provider := fxProviderOf(pkg, call.Args[0])
+		if provider == nil {
+			return nil
+		}
+
+		for _, arg := range call.Args[1:] {
+			ann, ok := arg.(*ast.CallExpr)
+			if !ok {
+				continue
+			}
+			switch fxFunc(pkg, ann) {
+			case "ParamTags":
+				provider.paramTags = stringArgs(ann)
+			case "ResultTags":
+				provider.resultTags = stringArgs(ann)
+			case "As":
+				var as []gotypes.Type
+				for _, target := range ann.Args {
+					var typ gotypes.Type
+					if ptr, ok := pkg.TypesInfo.TypeOf(target).(*gotypes.Pointer); ok {
+						typ = ptr.Elem() // new(Iface)
+					}
+					as = append(as, typ)
+				}
+				provider.as = append(provider.as, as)
+			}
+		}
+		return provider
+	}
+
+	sig, ok := pkg.TypesInfo.TypeOf(expr).(*gotypes.Signature)
+	if !ok {
+		return nil
+	}
+	return &fxProvider{expr: expr, sig: sig}
+}
+
+// fxValue is a container value with its Go type
+type fxValue struct {
+	types.DIValue
+	typ gotypes.Type
+}
+
+// fxValues expands a parameter or result into container values. Structs
+// embedding fx.In (for parameters) or fx.Out (for results) contribute one
+// value per field, tagged by the field; anything else is one value tagged
+// by the fx.ParamTags or fx.ResultTags entry.
+func fxValues(typ gotypes.Type, tag, marker string) []fxValue {
+	if st, ok := typ.Underlying().(*gotypes.Struct); ok && embedsFx(st, marker) {
+		var values []fxValue
+		for i := 0; i < st.NumFields(); i++ {
+			field := st.Field(i)
+			if field.Embedded() || !field.Exported() {
+				continue
+			}
+			values = append(values, taggedValue(field.Type(), st.Tag(i)))
+		}
+		return values
+	}
+	return []fxValue{taggedValue(typ, tag)}
+}
+
+// taggedValue builds a value from its type and struct-tag style annotations
+func taggedValue(typ gotypes.Type, tag string) fxValue {
+	structTag := reflect.StructTag(tag)
+	group, _, _ := strings.Cut(structTag.Get("group"), ",")
+
+	// Group members are consumed as a slice of the element type
+	if slice, ok := typ.(*gotypes.Slice); ok && group != "" {
+		typ = slice.Elem()
+	}
+
+	return fxValue{
+		DIValue: types.DIValue{
+			Type:     gotypes.TypeString(typ, nil),
+			Name:     structTag.Get("name"),
+			Group:    group,
+			Optional: structTag.Get("optional") == "true",
+		},
+		typ: typ,
+	}
+}
+
+// applyAs replaces a result's type with the interfaces fx.As provides it as
+func applyAs(value fxValue, as [][]gotypes.Type, result int) []fxValue {
+	var provided []fxValue
+	for _, targets := range as {
+		if result >= len(targets) {
+			continue
+		}
+		if targets[result] == nil {
+			provided = append(provided, value) // fx.Self()
+			continue
+		}
+		v := value
+		v.typ = targets[result]
+		v.Type = gotypes.TypeString(targets[result], nil)
+		provided = append(provided, v)
+	}
+	if len(provided) == 0 {
+		return []fxValue{value}
+	}
+	return provided
+}
+
+// fxModule returns the name of the innermost fx.Module enclosing the
+// current node, given the path of nodes leading to it
+func fxModule(pkg *packages.Package, stack []ast.Node) string {
+	for i := len(stack) - 1; i >= 0; i-- {
+		call, ok := stack[i].(*ast.CallExpr)
+		if !ok || fxFunc(pkg, call) != "Module" || len(call.Args) == 0 {
+			continue
+		}
+		if lit, ok := call.Args[0].(*ast.BasicLit); ok {
+			if name, err := strconv.Unquote(lit.Value); err == nil {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// fxFunc returns the name of the Fx function a call invokes, or ""
+func fxFunc(pkg *packages.Package, call *ast.CallExpr) string {
+	fun := call.Fun
+	switch f := fun.(type) {
+	case *ast.IndexExpr:
+		fun = f.X
+	case *ast.IndexListExpr:
+		fun = f.X
+	}
+
+	sel, ok := fun.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+	ident, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return ""
+	}
+
+	pkgName, ok := pkg.TypesInfo.Uses[ident].(*gotypes.PkgName)
+	if !ok || pkgName.Imported().Path() != fxPath {
+		return ""
+	}
+	return sel.Sel.Name
+}
+
+// embedsFx reports whether a struct embeds fx.In or fx.Out, or the dig.In
+// or dig.Out they alias
+func embedsFx(st *gotypes.Struct, marker string) bool {
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		if !field.Embedded() {
+			continue
+		}
+		if named, ok := field.Type().(*gotypes.Named); ok && (isFxObject(named.Obj(), marker) || isDigObject(named.Obj(), marker)) {
+			return true
+		}
+	}
+	return false
+}
+
+// isFxObject reports whether an object is the named Fx declaration
+func isFxObject(obj gotypes.Object, name string) bool {
+	return obj.Pkg() != nil && obj.Pkg().Path() == fxPath && obj.Name() == name
+}
+
+// supplyName names a supplied value: a variable's name, or the type of a
+// composite literal ("config.Config{…}")
+func supplyName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.CompositeLit:
+		return gotypes.ExprString(e.Type) + "{…}"
+	case *ast.UnaryExpr:
+		return e.Op.String() + supplyName(e.X)
+	}
+	return gotypes.ExprString(expr)
+}
+
+// stringArgs returns the string literal arguments of a call
+func stringArgs(call *ast.CallExpr) []string {
+	var values []string
+	for _, arg := range call.Args {
+		value := ""
+		if lit, ok := arg.(*ast.BasicLit); ok {
+			value, _ = strconv.Unquote(lit.Value)
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
+// tagAt returns the i-th tag, or "" past the end
+func tagAt(tags []string, i int) string {
+	if i < len(tags) {
+		return tags[i]
+	}
+	return ""
+}
+
+// isError reports whether a type is the built-in error interface
+func isError(typ gotypes.Type) bool {
+	return gotypes.Identical(typ, gotypes.Universe.Lookup("error").Type())
+}
@@ -0,0 +1,155 @@
+package di
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	gotypes "go/types"
+	"testing"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/packages"
+)
+
+// loadDetector loads an example module and creates a detector for it
+func loadDetector(t *testing.T, example string) *Detector {
+	t.Helper()
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedSyntax |
+			packages.NeedTypes | packages.NeedTypesInfo | packages.NeedImports,
+		Dir: "../../../examples/" + example,
+	}
+	pkgs, err := packages.Load(cfg, "./...")
+	require.NoError(t, err)
+	for _, pkg := range pkgs {
+		require.Empty(t, pkg.Errors, pkg.PkgPath)
+	}
+	return NewDetector(pkgs, pkgs[0].Fset)
+}
+
+// findBinding returns the binding of the named provider
+func findBinding(t *testing.T, bindings []types.DIBinding, kind, provider string) types.DIBinding {
+	t.Helper()
+	for _, binding := range bindings {
+		if binding.Kind == kind && binding.Provider.Name == provider {
+			return binding
+		}
+	}
+	require.Failf(t, "binding not found", "%s %s", kind, provider)
+	return types.DIBinding{}
+}
+
+// TestAnalyzeFxBindings tests modules, annotations, parameter and result structs, and hooks
+func TestAnalyzeFxBindings(t *testing.T) {
+	// Given: The Fx example application
+	detector := loadDetector(t, "ex4")
+	require.Equal(t, "fx", detector.DetectFramework())
+
+	// When: We analyze the container
+	bindings := detector.analyzeFxBindings(nil)
+
+	// Then: Constructors in fx.Module carry the module name and lifecycle hooks
+	conn := findBinding(t, bindings, "provide", "NewConn")
+	assert.Equal(t, "db", conn.Module)
+	assert.Equal(t, []types.DIValue{{Type: "*example.com/ex4/internal/db.Conn"}}, conn.Provides)
+	assert.Equal(t, []types.DIValue{
+		{Type: "example.com/ex4/internal/config.Config"},
+		{Type: "go.uber.org/fx.Lifecycle"},
+	}, conn.Requires)
+	assert.Equal(t, []string{"OnStart", "OnStop"}, conn.Hooks)
+	assert.Equal(t, []string{"app.main", "migrate.main"}, conn.Apps)
+	assert.Equal(t, "Conn", conn.Product.Name)
+	require.Len(t, conn.Dependencies, 1)
+	assert.Equal(t, "Config", conn.Dependencies[0].Name)
+
+	// fx.Annotate with fx.As and fx.ResultTags provides a named interface
+	cache := findBinding(t, bindings, "provide", "NewCache")
+	assert.Equal(t, []types.DIValue{{Type: "example.com/ex4/internal/users.Repository", Name: "cache"}}, cache.Provides)
+	assert.Equal(t, "Repository", cache.Product.Name)
+
+	// fx.In fields are dependencies with their tags
+	service := findBinding(t, bindings, "provide", "NewService")
+	assert.Equal(t, []types.DIValue{
+		{Type: "example.com/ex4/internal/users.Repository"},
+		{Type: "example.com/ex4/internal/users.Repository", Name: "cache", Optional: true},
+	}, service.Requires)
+
+	// fx.Out fields join value groups, which fx.ParamTags consumes
+	routes := findBinding(t, bindings, "provide", "NewRoutes")
+	assert.Equal(t, []types.DIValue{
+		{Type: "example.com/ex4/internal/users.Handler", Group: "routes"},
+		{Type: "example.com/ex4/internal/users.Handler", Group: "routes"},
+	}, routes.Provides)
+	server := findBinding(t, bindings, "provide", "NewServer")
+	assert.Equal(t, "", server.Module)
+	assert.Contains(t, server.Requires, types.DIValue{Type: "example.com/ex4/internal/users.Handler", Group: "routes"})
+
+	// Decorators, supplied values and invoked literals are modeled too
+	audit := findBinding(t, bindings, "decorate", "WithAudit")
+	assert.Equal(t, "users", audit.Module)
+	assert.Equal(t, audit.Requires, audit.Provides)
+
+	supplied := findBinding(t, bindings, "supply", "config.Config{…}")
+	assert.Equal(t, []types.DIValue{{Type: "example.com/ex4/internal/config.Config"}}, supplied.Provides)
+	assert.Equal(t, []string{"app.main"}, supplied.Apps)
+
+	invoke := findBinding(t, bindings, "invoke", "main.func1")
+	assert.Empty(t, invoke.Provides)
+	assert.Equal(t, []string{"OnStart"}, invoke.Hooks)
+	assert.Equal(t, "example.com/ex4/cmd/app.main.func1", invoke.Provider.ID())
+}
+
+// TestAnalyzeDIBindingsFxRelevant tests that extraction keeps only bindings touching its symbols
+func TestAnalyzeDIBindingsFxRelevant(t *testing.T) {
+	// Given: An extract containing only the user service
+	detector := loadDetector(t, "ex4")
+	symbols := []types.Symbol{{Name: "Service", Kind: "struct", Package: "example.com/ex4/internal/users"}}
+
+	// When: We analyze bindings for it
+	bindings := detector.AnalyzeDIBindings(symbols)
+
+	// Then: Its constructor, decorator and consumers are kept, unrelated ones are not
+	var providers []string
+	for _, binding := range bindings {
+		providers = append(providers, binding.Provider.Name)
+	}
+	assert.ElementsMatch(t, []string{"NewService", "WithAudit", "NewRoutes"}, providers)
+}
+
+// TestFxFuncRequiresImport tests that calls on a local value named fx are not Fx calls
+func TestFxFuncRequiresImport(t *testing.T) {
+	// Given: A package whose variable fx has a Provide method
+	src := `package p
+
+type registry struct{}
+
+func (registry) Provide(any) {}
+
+var fx registry
+
+func init() { fx.Provide(nil) }
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "p.go", src, 0)
+	require.NoError(t, err)
+	info := &gotypes.Info{Uses: make(map[*ast.Ident]gotypes.Object)}
+	_, err = (&gotypes.Config{}).Check("p", fset, []*ast.File{file}, info)
+	require.NoError(t, err)
+
+	var call *ast.CallExpr
+	ast.Inspect(file, func(n ast.Node) bool {
+		if c, ok := n.(*ast.CallExpr); ok {
+			call = c
+		}
+		return true
+	})
+	require.NotNil(t, call)
+
+	// When: We ask which Fx function the call invokes
+	name := fxFunc(&packages.Package{TypesInfo: info}, call)
+
+	// Then: It is none
+	assert.Empty(t, name)
+}
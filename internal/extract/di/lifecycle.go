@@ -0,0 +1,121 @@
+package di
+
+import (
+	"go/ast"
+	gotypes "go/types"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+	"golang.org/x/tools/go/packages"
+)
+
+// shutdownMethods are the methods a container or its owner calls to release
+// a value, in reporting order
+var shutdownMethods = []string{"Close", "Shutdown", "Stop"}
+
+// closeHooks lists the shutdown methods of a provided type: Close, Shutdown
+// or Stop taking nothing or a context, on the type or its pointer
+func closeHooks(typ gotypes.Type) []string {
+	if typ == nil {
+		return nil
+	}
+	methods := gotypes.NewMethodSet(typ)
+	if _, ok := typ.Underlying().(*gotypes.Interface); !ok {
+		if _, ok := typ.(*gotypes.Pointer); !ok {
+			methods = gotypes.NewMethodSet(gotypes.NewPointer(typ))
+		}
+	}
+
+	var hooks []string
+	for _, name := range shutdownMethods {
+		for i := 0; i < methods.Len(); i++ {
+			fn := methods.At(i).Obj()
+			if fn.Name() != name {
+				continue
+			}
+			params := fn.Type().(*gotypes.Signature).Params()
+			if params.Len() == 0 || params.Len() == 1 && isContext(params.At(0).Type()) {
+				hooks = append(hooks, name)
+			}
+		}
+	}
+	return hooks
+}
+
+// addHooks records lifecycle hooks on a binding once each
+func addHooks(binding *types.DIBinding, hooks ...string) {
+	for _, hook := range hooks {
+		if !containsString(binding.Hooks, hook) {
+			binding.Hooks = append(binding.Hooks, hook)
+		}
+	}
+}
+
+// requestScoped reports whether a constructor is called while serving an
+// HTTP request: inside a handler, handler method or middleware closure, that
+// is a function taking an *http.Request
+func (d *Detector) requestScoped(fn *gotypes.Func) bool {
+	if d.requestCalls == nil {
+		d.requestCalls = make(map[gotypes.Object]bool)
+		for _, pkg := range d.pkgs {
+			for _, astFile := range pkg.Syntax {
+				for _, decl := range astFile.Decls {
+					fn, ok := decl.(*ast.FuncDecl)
+					if !ok || fn.Body == nil {
+						continue
+					}
+					var sig gotypes.Type
+					if obj := pkg.TypesInfo.Defs[fn.Name]; obj != nil {
+						sig = obj.Type()
+					}
+					d.collectRequestCalls(pkg, fn.Body, handlesRequest(sig))
+				}
+			}
+		}
+	}
+	return d.requestCalls[fn.Origin()]
+}
+
+// collectRequestCalls records the functions a body calls when it serves a
+// request, descending into function literals, which serve one when they
+// take the request themselves or are written inside a body serving one
+func (d *Detector) collectRequestCalls(pkg *packages.Package, body ast.Node, serving bool) {
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			if node.Body != body {
+				d.collectRequestCalls(pkg, node.Body, serving || handlesRequest(pkg.TypesInfo.TypeOf(node)))
+				return false
+			}
+		case *ast.CallExpr:
+			if fn, ok := usedObject(pkg, node.Fun).(*gotypes.Func); ok && serving {
+				d.requestCalls[fn.Origin()] = true
+			}
+		}
+		return true
+	})
+}
+
+// handlesRequest reports whether a function type takes an *http.Request
+func handlesRequest(typ gotypes.Type) bool {
+	sig, ok := typ.(*gotypes.Signature)
+	if !ok {
+		return false
+	}
+	for i := 0; i < sig.Params().Len(); i++ {
+		if ptr, ok := sig.Params().At(i).Type().(*gotypes.Pointer); ok && isNamed(ptr.Elem(), "net/http", "Request") {
+			return true
+		}
+	}
+	return false
+}
+
+// isContext reports whether a type is context.Context
+func isContext(typ gotypes.Type) bool {
+	return isNamed(typ, "context", "Context")
+}
+
+// isNamed reports whether a type is the named type pkgPath.name
+func isNamed(typ gotypes.Type, pkgPath, name string) bool {
+	named, ok := typ.(*gotypes.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == pkgPath && named.Obj().Name() == name
+}
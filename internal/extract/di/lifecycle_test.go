@@ -0,0 +1,104 @@
+package di
+
+import (
+	gotypes "go/types"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/packages"
+)
+
+// TestRequestScope tests that constructors called by HTTP middleware are request-scoped
+func TestRequestScope(t *testing.T) {
+	// Given: The request log built in a middleware closure and the handler built in main
+	detector := loadDetector(t, "ex2")
+	symbols := []types.Symbol{
+		{Name: "NewRequestLog", Kind: "func", Package: "example.com/ex2/internal/adapters/rest"},
+		{Name: "NewHandler", Kind: "func", Package: "example.com/ex2/internal/adapters/rest"},
+	}
+
+	// When: We analyze the manual bindings
+	bindings := detector.analyzeManualBindings(symbols)
+
+	// Then: The request log lives for one request and is closed, the handler is shared
+	log := findBinding(t, bindings, "", "NewRequestLog")
+	assert.Equal(t, "request", log.Scope)
+	assert.Equal(t, []string{"Close"}, log.Hooks)
+
+	handler := findBinding(t, bindings, "", "NewHandler")
+	assert.Equal(t, "singleton", handler.Scope)
+	assert.Empty(t, handler.Hooks)
+}
+
+// TestRequestScopeClosure tests that constructors called in closures inside a handler are request-scoped
+func TestRequestScopeClosure(t *testing.T) {
+	// Given: A handler building its session in a sync.OnceValue closure
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/scoped\n\ngo 1.22\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "handler.go"), []byte(`package scoped
+
+import (
+	"net/http"
+	"sync"
+)
+
+type Session struct{}
+
+func NewSession() *Session { return &Session{} }
+
+func Handle(w http.ResponseWriter, r *http.Request) {
+	session := sync.OnceValue(func() *Session { return NewSession() })
+	_ = session()
+}
+`), 0o644))
+	pkgs, err := packages.Load(&packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesInfo,
+		Dir:  dir,
+	}, "./...")
+	require.NoError(t, err)
+	require.Len(t, pkgs, 1)
+	require.Empty(t, pkgs[0].Errors)
+	detector := NewDetector(pkgs, pkgs[0].Fset)
+
+	// When: We ask whether the session constructor runs per request
+	scoped := detector.requestScoped(pkgs[0].Types.Scope().Lookup("NewSession").(*gotypes.Func))
+
+	// Then: It does
+	assert.True(t, scoped)
+}
+
+// TestBindingInit tests when Fx, dig and samber/do build their values
+func TestBindingInit(t *testing.T) {
+	// Given: The Fx and dig/do examples
+	fx := loadDetector(t, "ex4").analyzeFxBindings(nil)
+	dig := loadDetector(t, "ex6").analyzeDigBindings(nil)
+	do := loadDetector(t, "ex6").analyzeDoBindings(nil)
+
+	// Then: Constructors are lazy, supplied values and invocations eager
+	assert.Equal(t, "lazy", findBinding(t, fx, "provide", "NewConn").Init)
+	assert.Equal(t, "eager", findBinding(t, fx, "supply", "config.Config{…}").Init)
+	assert.Equal(t, "eager", findBinding(t, fx, "invoke", "main.func1").Init)
+	assert.Equal(t, "lazy", findBinding(t, do, "provide", "NewConfig").Init)
+	assert.Equal(t, "eager", findBinding(t, do, "supply", `"eu-west-1"`).Init)
+
+	// And: dig constructors of a child scope are scoped to it
+	assert.Equal(t, "singleton", findBinding(t, dig, "provide", "NewService").Scope)
+	assert.Equal(t, "scoped", findBinding(t, dig, "provide", "NewAdmin").Scope)
+}
+
+// TestWireScopes tests that Wire values are built per injector call, with cleanup hooks
+func TestWireScopes(t *testing.T) {
+	// Given: The Wire example
+	bindings := loadDetector(t, "ex5").analyzeWireBindings(nil)
+
+	// Then: Providers are built once per injector call, supplied values are shared
+	store := findBinding(t, bindings, "provide", "NewSQLStore")
+	assert.Equal(t, "injector", store.Scope)
+	assert.Equal(t, "eager", store.Init)
+	assert.Equal(t, []string{"cleanup"}, store.Hooks)
+	assert.Equal(t, "singleton", findBinding(t, bindings, "supply", "Timeout(30 * time.Second)").Scope)
+}
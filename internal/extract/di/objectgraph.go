@@ -0,0 +1,126 @@
+package di
+
+import (
+	"fmt"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+)
+
+// ObjectGraphs builds the object graph of every invoke and Wire injector
+// isRoot accepts: the providers its container calls to satisfy it, each
+// parameter resolved to the provider of its value. Decorators sit between
+// a value's consumers and its provider; values nothing provides end in a
+// "missing" object. Object IDs are provider IDs, suffixed "#2", "#3" for
+// further registrations of the same function.
+func (d *Detector) ObjectGraphs(isRoot func(types.Symbol) bool, symbols []types.Symbol) []types.DIObjectGraph {
+	bindings := d.containerBindings(symbols)
+	graphs := []types.DIObjectGraph{}
+	for _, c := range splitContainers(bindings) {
+		for i, node := range c.nodes {
+			if node.root && isRoot(bindings[node.binding].Provider) {
+				graphs = append(graphs, c.objectGraph(bindings, i))
+			}
+		}
+	}
+	return graphs
+}
+
+// objectGraph walks the container breadth first from a root node
+func (c *diContainer) objectGraph(bindings []types.DIBinding, root int) types.DIObjectGraph {
+	providers := c.providers()
+	decorators := make(map[string]int)
+	for i, node := range c.nodes {
+		if bindings[node.binding].Kind == "decorate" {
+			for _, value := range node.requires {
+				if _, ok := decorators[diKey(value)]; !ok {
+					decorators[diKey(value)] = i
+				}
+			}
+		}
+	}
+
+	graph := types.DIObjectGraph{
+		Root:      bindings[c.nodes[root].binding].Provider,
+		Framework: c.framework,
+		Container: c.name,
+		Objects:   []types.DIObject{},
+		Edges:     []types.DIObjectEdge{},
+	}
+	ids := make(map[int]string)
+	depths := make(map[int]int)
+	taken := make(map[string]int)
+	missing := make(map[string]bool)
+
+	var queue []int
+	visit := func(i, depth int) string {
+		if id, ok := ids[i]; ok {
+			return id
+		}
+		node := c.nodes[i]
+		binding := bindings[node.binding]
+		object := types.DIObject{
+			ID:       binding.Provider.ID(),
+			Provider: binding.Provider,
+			Product:  binding.Product,
+			Kind:     binding.Kind,
+			Scope:    binding.Scope,
+			Module:   binding.Module,
+			Provides: node.provides,
+			Depth:    depth,
+		}
+		if binding.Kind == "inject" && !node.root {
+			object.ID += "#args"
+			object.Kind = "argument"
+			object.Product = types.Symbol{}
+		}
+		// A function registered twice, under different names, is two objects
+		if n := taken[object.ID]; n > 0 {
+			taken[object.ID]++
+			object.ID += fmt.Sprintf("#%d", n+1)
+		} else {
+			taken[object.ID] = 1
+		}
+		ids[i], depths[i] = object.ID, depth
+		graph.Objects = append(graph.Objects, object)
+		queue = append(queue, i)
+		return object.ID
+	}
+
+	visit(root, 0)
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+		from, depth := ids[i], depths[i]+1
+
+		for _, value := range c.nodes[i].requires {
+			key := diKey(value)
+			targets := providers[key]
+			if dec, ok := decorators[key]; ok && dec != i {
+				targets = []int{dec}
+			}
+
+			if len(targets) == 0 {
+				if value.Optional || value.Group != "" || c.builtin[value.Type] {
+					continue
+				}
+				id := "missing:" + value.Type
+				if !missing[id] {
+					missing[id] = true
+					graph.Objects = append(graph.Objects, types.DIObject{
+						ID:       id,
+						Kind:     "missing",
+						Provides: []types.DIValue{value},
+						Depth:    depth,
+					})
+				}
+				graph.Edges = append(graph.Edges, types.DIObjectEdge{From: from, To: id, Value: value})
+				continue
+			}
+
+			for _, j := range targets {
+				graph.Edges = append(graph.Edges, types.DIObjectEdge{From: from, To: visit(j, depth), Value: value})
+			}
+		}
+	}
+	return graph
+}
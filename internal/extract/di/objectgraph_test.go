@@ -0,0 +1,88 @@
+package di
+
+import (
+	"testing"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// edgesFrom returns the IDs of the objects an object's parameters resolve to
+func edgesFrom(graph types.DIObjectGraph, from string) []string {
+	var to []string
+	for _, edge := range graph.Edges {
+		if edge.From == from {
+			to = append(to, edge.To)
+		}
+	}
+	return to
+}
+
+// TestObjectGraphsWire tests walking a Wire injector down to its arguments
+func TestObjectGraphsWire(t *testing.T) {
+	// Given: The Wire example
+	detector := loadDetector(t, "ex5")
+
+	// When: We build the graph of the production injector
+	graphs := detector.ObjectGraphs(func(sym types.Symbol) bool {
+		return sym.Name == "InitializeServer"
+	}, nil)
+
+	// Then: The injector reaches the SQL store through its binding, and the
+	// config through the injector's argument
+	require.Len(t, graphs, 1)
+	graph := graphs[0]
+	assert.Equal(t, "wire", graph.Framework)
+	assert.Equal(t, "main.InitializeServer", graph.Container)
+	assert.Equal(t, "inject", graph.Objects[0].Kind)
+	assert.Equal(t, 0, graph.Objects[0].Depth)
+
+	assert.Equal(t, []string{"example.com/ex5/internal/store.NewSQLStore"}, edgesFrom(graph, "example.com/ex5/internal/store.SQLStore"))
+	assert.Equal(t, []string{"example.com/ex5/cmd/server.InitializeServer#args"}, edgesFrom(graph, "example.com/ex5/internal/config.Env"))
+	for _, object := range graph.Objects {
+		assert.NotContains(t, object.ID, "MemoryStore")
+	}
+}
+
+// TestObjectGraphsFx tests that decorators sit between a value's consumers and its provider
+func TestObjectGraphsFx(t *testing.T) {
+	// Given: The Fx example, decorating the user service
+	detector := loadDetector(t, "ex4")
+
+	// When: We build the graph of main's invoke
+	graphs := detector.ObjectGraphs(func(sym types.Symbol) bool {
+		return sym.Name == "main.func1"
+	}, nil)
+
+	// Then: Routes get the decorated service, and lifecycle is left to Fx
+	require.Len(t, graphs, 1)
+	graph := graphs[0]
+	assert.Equal(t, []string{"example.com/ex4/internal/users.WithAudit"}, edgesFrom(graph, "example.com/ex4/internal/users.NewRoutes"))
+	assert.Equal(t, []string{"example.com/ex4/internal/users.NewService"}, edgesFrom(graph, "example.com/ex4/internal/users.WithAudit"))
+	assert.Equal(t, []string{"example.com/ex4/internal/server.NewServer"}, edgesFrom(graph, "example.com/ex4/cmd/app.main.func1"))
+}
+
+// TestObjectGraphsMissing tests marking values nothing provides and naming repeated providers apart
+func TestObjectGraphsMissing(t *testing.T) {
+	// Given: An invoke needing a logger nothing provides, and a store
+	// constructor registered plain and by name
+	bindings := []types.DIBinding{
+		{Provider: fn("run"), Framework: "dig", Kind: "invoke", Requires: []types.DIValue{value("*Service")}},
+		{Provider: fn("NewService"), Framework: "dig", Kind: "provide", Requires: []types.DIValue{value("*Logger"), value("Store"), {Type: "Store", Name: "cache"}}, Provides: []types.DIValue{value("*Service")}},
+		{Provider: fn("NewStore"), Framework: "dig", Kind: "provide", Provides: []types.DIValue{value("Store")}},
+		{Provider: fn("NewStore"), Framework: "dig", Kind: "provide", Provides: []types.DIValue{{Type: "Store", Name: "cache"}}},
+	}
+	c := splitContainers(bindings)[0]
+
+	// When: We build the graph from the invoke
+	graph := c.objectGraph(bindings, 0)
+
+	// Then: The logger is a missing object, and the named store a second one
+	assert.Equal(t, []string{"missing:*Logger", "example.com/app.NewStore", "example.com/app.NewStore#2"}, edgesFrom(graph, "example.com/app.NewService"))
+	var kinds []string
+	for _, object := range graph.Objects {
+		kinds = append(kinds, object.Kind)
+	}
+	assert.Equal(t, []string{"invoke", "provide", "missing", "provide", "provide"}, kinds)
+}
@@ -0,0 +1,356 @@
+package di
+
+import (
+	"go/ast"
+	gotypes "go/types"
+	"strings"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+	"golang.org/x/tools/go/packages"
+)
+
+// configSuffixes name the struct parameters read as a constructor's config
+var configSuffixes = []string{"Config", "Options", "Opts", "Settings"}
+
+// ConstructorOptions describes how a constructor is configured: the option
+// functions of its variadic functional options, or the fields of its config
+// struct, with the defaults it sets and the options each call site passes.
+// It returns nil for functions taking neither.
+func (d *Detector) ConstructorOptions(constructor types.Symbol, symbols []types.Symbol) *types.ConstructorOptions {
+	fn := d.funcObject(constructor)
+	if fn == nil {
+		return nil
+	}
+	decl, pkg := d.declaration(fn)
+	if decl == nil || decl.Body == nil {
+		return nil
+	}
+
+	sig := fn.Type().(*gotypes.Signature)
+	params := sig.Params()
+	if params.Len() == 0 {
+		return nil
+	}
+
+	// Functional options: a variadic parameter of functions taking the configured value
+	if sig.Variadic() {
+		optionType := params.At(params.Len() - 1).Type().(*gotypes.Slice).Elem()
+		if target := optionTarget(optionType); target != nil {
+			options := d.newOptions(constructor, "functional", optionType, symbols)
+			options.Options = d.optionFuncs(optionType, symbols)
+			options.Defaults = literalDefaults(pkg, decl.Body, target)
+			options.CallSites = d.optionCalls(fn, func(call *ast.CallExpr) []string {
+				var passed []string
+				for _, arg := range call.Args[params.Len()-1:] {
+					passed = append(passed, gotypes.ExprString(arg))
+				}
+				if call.Ellipsis.IsValid() && len(passed) > 0 {
+					passed[len(passed)-1] += "..."
+				}
+				return passed
+			})
+			return options
+		}
+	}
+
+	// A config struct parameter
+	for i := 0; i < params.Len(); i++ {
+		param := params.At(i)
+		fields := configStruct(param.Type())
+		if fields == nil {
+			continue
+		}
+
+		options := d.newOptions(constructor, "config", param.Type(), symbols)
+		for j := 0; j < fields.NumFields(); j++ {
+			if field := fields.Field(j); field.Exported() {
+				options.Options = append(options.Options, types.ConstructorOption{
+					Name: field.Name(),
+					Type: gotypes.TypeString(field.Type(), nil),
+				})
+			}
+		}
+		options.Defaults = assignedDefaults(pkg, decl.Body, param)
+		options.CallSites = d.optionCalls(fn, func(call *ast.CallExpr) []string {
+			if i >= len(call.Args) {
+				return nil
+			}
+			arg := call.Args[i]
+			if unary, ok := arg.(*ast.UnaryExpr); ok {
+				arg = unary.X
+			}
+			lit, ok := arg.(*ast.CompositeLit)
+			if !ok {
+				return []string{gotypes.ExprString(call.Args[i])}
+			}
+			var set []string
+			for _, elt := range lit.Elts {
+				set = append(set, gotypes.ExprString(elt))
+			}
+			return set
+		})
+		return options
+	}
+	return nil
+}
+
+// newOptions starts the options of a constructor configured by typ
+func (d *Detector) newOptions(constructor types.Symbol, style string, typ gotypes.Type, symbols []types.Symbol) *types.ConstructorOptions {
+	// The constructor is listed elsewhere with its code
+	constructor.Code, constructor.Doc = "", ""
+	options := &types.ConstructorOptions{
+		Constructor: constructor,
+		Style:       style,
+		Type:        types.Symbol{Name: gotypes.TypeString(typ, nil), Kind: "type"},
+		Options:     []types.ConstructorOption{},
+	}
+	if sym := d.typeSymbol(typ, symbols); sym != nil {
+		options.Type = *sym
+	}
+	return options
+}
+
+// optionTarget returns the type a functional option configures: the single
+// parameter of a named function type ("type Option func(*Server)")
+func optionTarget(typ gotypes.Type) gotypes.Type {
+	if _, ok := typ.(*gotypes.Named); !ok {
+		return nil
+	}
+	sig, ok := typ.Underlying().(*gotypes.Signature)
+	if !ok || sig.Params().Len() != 1 {
+		return nil
+	}
+	return sig.Params().At(0).Type()
+}
+
+// configStruct returns the fields of a config struct parameter, a struct or
+// pointer to one named like Config or Options, or nil
+func configStruct(typ gotypes.Type) *gotypes.Struct {
+	if ptr, ok := typ.(*gotypes.Pointer); ok {
+		typ = ptr.Elem()
+	}
+	named, ok := typ.(*gotypes.Named)
+	if !ok {
+		return nil
+	}
+	fields, ok := named.Underlying().(*gotypes.Struct)
+	if !ok {
+		return nil
+	}
+	for _, suffix := range configSuffixes {
+		if strings.HasSuffix(named.Obj().Name(), suffix) {
+			return fields
+		}
+	}
+	return nil
+}
+
+// optionFuncs lists the module's functions returning an option type, with
+// the fields of the configured value the returned closure sets
+func (d *Detector) optionFuncs(optionType gotypes.Type, symbols []types.Symbol) []types.ConstructorOption {
+	options := []types.ConstructorOption{}
+	for _, pkg := range d.pkgs {
+		for _, astFile := range pkg.Syntax {
+			for _, decl := range astFile.Decls {
+				fd, ok := decl.(*ast.FuncDecl)
+				if !ok || fd.Recv != nil || fd.Body == nil {
+					continue
+				}
+				fn, ok := pkg.TypesInfo.Defs[fd.Name].(*gotypes.Func)
+				if !ok {
+					continue
+				}
+				results := fn.Type().(*gotypes.Signature).Results()
+				if results.Len() != 1 || !gotypes.Identical(results.At(0).Type(), optionType) {
+					continue
+				}
+
+				sym := d.objectSymbol(fn, symbols)
+				options = append(options, types.ConstructorOption{
+					Name:   fn.Name(),
+					Symbol: &sym,
+					Fields: closureFields(pkg, fd.Body),
+				})
+			}
+		}
+	}
+	return options
+}
+
+// closureFields lists the fields the function literals of a body set on
+// their first parameter ("s.timeout = d", "s.tags[k] = v")
+func closureFields(pkg *packages.Package, body *ast.BlockStmt) []string {
+	var fields []string
+	ast.Inspect(body, func(n ast.Node) bool {
+		lit, ok := n.(*ast.FuncLit)
+		if !ok || len(lit.Type.Params.List) == 0 || len(lit.Type.Params.List[0].Names) == 0 {
+			return true
+		}
+		param := pkg.TypesInfo.Defs[lit.Type.Params.List[0].Names[0]]
+		for _, field := range setFields(pkg, lit.Body, param) {
+			if !containsString(fields, field) {
+				fields = append(fields, field)
+			}
+		}
+		return false
+	})
+	return fields
+}
+
+// setFields lists the fields of a variable a body assigns, in order
+func setFields(pkg *packages.Package, body ast.Node, v gotypes.Object) []string {
+	var fields []string
+	add := func(expr ast.Expr) {
+		if field := fieldOf(pkg, expr, v); field != "" && !containsString(fields, field) {
+			fields = append(fields, field)
+		}
+	}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range node.Lhs {
+				add(lhs)
+			}
+		case *ast.IncDecStmt:
+			add(node.X)
+		}
+		return true
+	})
+	return fields
+}
+
+// fieldOf returns the field of v an assigned expression sets: "timeout"
+// for "v.timeout" or "v.timeout[k]"
+func fieldOf(pkg *packages.Package, expr ast.Expr, v gotypes.Object) string {
+	for {
+		switch e := ast.Unparen(expr).(type) {
+		case *ast.IndexExpr:
+			expr = e.X
+			continue
+		case *ast.SelectorExpr:
+			if ident, ok := ast.Unparen(e.X).(*ast.Ident); ok && v != nil && pkg.TypesInfo.Uses[ident] == v {
+				return e.Sel.Name
+			}
+		}
+		return ""
+	}
+}
+
+// literalDefaults returns the fields a constructor sets in composite
+// literals of the configured type, outside function literals
+func literalDefaults(pkg *packages.Package, body *ast.BlockStmt, target gotypes.Type) []types.OptionDefault {
+	if ptr, ok := target.(*gotypes.Pointer); ok {
+		target = ptr.Elem()
+	}
+
+	var defaults []types.OptionDefault
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.CompositeLit:
+			if typ := pkg.TypesInfo.TypeOf(node); typ == nil || !gotypes.Identical(typ, target) {
+				return true
+			}
+			for _, elt := range node.Elts {
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+					defaults = append(defaults, types.OptionDefault{
+						Field: gotypes.ExprString(kv.Key),
+						Value: gotypes.ExprString(kv.Value),
+					})
+				}
+			}
+		}
+		return true
+	})
+	return defaults
+}
+
+// assignedDefaults returns the fields of a config parameter a constructor
+// assigns itself, typically when left at their zero value
+func assignedDefaults(pkg *packages.Package, body *ast.BlockStmt, param *gotypes.Var) []types.OptionDefault {
+	var defaults []types.OptionDefault
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.AssignStmt:
+			if len(node.Lhs) != len(node.Rhs) {
+				return true
+			}
+			for i, lhs := range node.Lhs {
+				if field := fieldOf(pkg, lhs, param); field != "" {
+					defaults = append(defaults, types.OptionDefault{Field: field, Value: gotypes.ExprString(node.Rhs[i])})
+				}
+			}
+		}
+		return true
+	})
+	return defaults
+}
+
+// optionCalls lists the module's calls of a constructor with the options
+// each passes
+func (d *Detector) optionCalls(fn *gotypes.Func, passed func(call *ast.CallExpr) []string) []types.OptionCall {
+	var calls []types.OptionCall
+	for _, pkg := range d.pkgs {
+		for _, astFile := range pkg.Syntax {
+			for _, decl := range astFile.Decls {
+				fd, ok := decl.(*ast.FuncDecl)
+				if !ok || fd.Body == nil {
+					continue
+				}
+				ast.Inspect(fd.Body, func(n ast.Node) bool {
+					call, ok := n.(*ast.CallExpr)
+					if !ok {
+						return true
+					}
+					if callee, ok := usedObject(pkg, call.Fun).(*gotypes.Func); !ok || callee.Origin() != fn {
+						return true
+					}
+					pos := d.position(call.Pos())
+					calls = append(calls, types.OptionCall{
+						Function: funcDeclName(fd),
+						File:     pos.Filename,
+						Line:     pos.Line,
+						Options:  passed(call),
+					})
+					return true
+				})
+			}
+		}
+	}
+	return calls
+}
+
+// funcDeclName names a function declaration, "Recv.Name" for methods
+func funcDeclName(fd *ast.FuncDecl) string {
+	if fd.Recv == nil || len(fd.Recv.List) == 0 {
+		return fd.Name.Name
+	}
+	recv := fd.Recv.List[0].Type
+	if star, ok := recv.(*ast.StarExpr); ok {
+		recv = star.X
+	}
+	switch r := recv.(type) {
+	case *ast.IndexExpr:
+		recv = r.X
+	case *ast.IndexListExpr:
+		recv = r.X
+	}
+	return gotypes.ExprString(recv) + "." + fd.Name.Name
+}
+
+// funcObject returns the package-level function a symbol names, or nil
+func (d *Detector) funcObject(sym types.Symbol) *gotypes.Func {
+	if sym.Kind != "func" {
+		return nil
+	}
+	for _, pkg := range d.pkgs {
+		if pkg.PkgPath == sym.Package {
+			fn, _ := pkg.Types.Scope().Lookup(sym.Name).(*gotypes.Func)
+			return fn
+		}
+	}
+	return nil
+}
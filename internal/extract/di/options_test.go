@@ -0,0 +1,64 @@
+package di
+
+import (
+	"testing"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestConstructorOptionsFunctional tests listing functional options, their fields, defaults and call sites
+func TestConstructorOptionsFunctional(t *testing.T) {
+	// Given: memory.Open, taking ...Option
+	detector := loadDetector(t, "ex3")
+	open := types.Symbol{Name: "Open", Kind: "func", Package: "example.com/ex3/pkg/memory"}
+
+	// When: We analyze its options
+	options := detector.ConstructorOptions(open, nil)
+
+	// Then: The option functions, the fields they set and the defaults are listed
+	require.NotNil(t, options)
+	assert.Equal(t, "functional", options.Style)
+	assert.Equal(t, "example.com/ex3/pkg/memory.Option", options.Type.ID())
+	var names []string
+	for _, option := range options.Options {
+		names = append(names, option.Name)
+		assert.Equal(t, []string{"values"}, option.Fields)
+	}
+	assert.Equal(t, []string{"WithValues", "WithValue"}, names)
+	assert.Equal(t, []types.OptionDefault{{Field: "values", Value: "make(map[string]string)"}}, options.Defaults)
+
+	// And: main's call passes one option
+	require.Len(t, options.CallSites, 1)
+	assert.Equal(t, "openStore", options.CallSites[0].Function)
+	assert.Equal(t, []string{`memory.WithValue("greeting", "hello")`}, options.CallSites[0].Options)
+}
+
+// TestConstructorOptionsConfig tests listing a config struct's fields and the defaults a provider sets
+func TestConstructorOptionsConfig(t *testing.T) {
+	// Given: The Fx example, whose NewConn takes config.Config
+	detector := loadDetector(t, "ex4")
+	symbols := []types.Symbol{{Name: "Conn", Kind: "struct", Package: "example.com/ex4/internal/db"}}
+
+	// When: We analyze the bindings
+	bindings := detector.AnalyzeDIBindings(symbols)
+
+	// Then: NewConn's binding lists the config fields and its DSN default
+	options := findBinding(t, bindings, "provide", "NewConn").Options
+	require.NotNil(t, options)
+	assert.Equal(t, "config", options.Style)
+	assert.Equal(t, "example.com/ex4/internal/config.Config", options.Type.ID())
+	assert.Equal(t, []types.ConstructorOption{{Name: "Addr", Type: "string"}, {Name: "DSN", Type: "string"}}, options.Options)
+	assert.Equal(t, []types.OptionDefault{{Field: "DSN", Value: `"postgres://localhost/dev"`}}, options.Defaults)
+	assert.Empty(t, options.Constructor.Code)
+}
+
+// TestConstructorOptionsNone tests that plain constructors have no options
+func TestConstructorOptionsNone(t *testing.T) {
+	// Given: memory.NewMap, taking nothing
+	detector := loadDetector(t, "ex3")
+
+	// Then: It has no options
+	assert.Nil(t, detector.ConstructorOptions(types.Symbol{Name: "NewMap", Kind: "func", Package: "example.com/ex3/pkg/memory"}, nil))
+}
@@ -0,0 +1,167 @@
+package di
+
+import (
+	"go/ast"
+	gotypes "go/types"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+	"golang.org/x/tools/go/packages"
+)
+
+// Registry is a home-grown DI container: a function or method registering
+// factories, optionally by name, such as registry.Register(name, factory).
+// Each call is a binding of the factory, named after the name argument when
+// it is a constant.
+type Registry struct {
+	framework string
+	call      string
+	args      []string
+}
+
+// NewRegistry creates a framework matching calls to a function,
+// "example.com/app/registry.Register", or method,
+// "example.com/app/registry.Registry.Register". args gives the role of each
+// argument, "name", "factory" or "" to ignore; it defaults to
+// [name, factory], and framework defaults to "registry".
+func NewRegistry(framework, call string, args []string) *Registry {
+	if framework == "" {
+		framework = "registry"
+	}
+	if len(args) == 0 {
+		args = []string{"name", "factory"}
+	}
+	return &Registry{framework: framework, call: call, args: args}
+}
+
+// Name returns the name the registry is reported as
+func (r *Registry) Name() string {
+	return r.framework
+}
+
+// Detect reports whether the package calls the registration function
+func (r *Registry) Detect(d *Detector, pkg *packages.Package) bool {
+	found := false
+	r.inspect([]*packages.Package{pkg}, func(*packages.Package, *ast.File, *ast.CallExpr) {
+		found = true
+	})
+	return found
+}
+
+// Analyze returns the bindings of the registration calls involving the
+// symbols
+func (r *Registry) Analyze(d *Detector, symbols []types.Symbol) []types.DIBinding {
+	var bindings []types.DIBinding
+	literals := make(map[*ast.File]map[*ast.FuncLit]string)
+
+	r.inspect(d.pkgs, func(pkg *packages.Package, astFile *ast.File, call *ast.CallExpr) {
+		var name string
+		var factory ast.Expr
+		for i, role := range r.args {
+			if i >= len(call.Args) {
+				break
+			}
+			switch role {
+			case "name":
+				name = constantString(pkg, call.Args[i])
+			case "factory":
+				factory = call.Args[i]
+			}
+		}
+		if factory == nil {
+			return
+		}
+
+		binding := types.DIBinding{
+			Dependencies: []types.Symbol{},
+			Framework:    r.framework,
+			Scope:        "transient", // Factories build a value per lookup
+			Kind:         "provide",
+		}
+		provide := func(typ gotypes.Type) {
+			binding.Provides = append(binding.Provides, types.DIValue{Type: gotypes.TypeString(typ, nil), Name: name})
+			if sym := d.typeSymbol(typ, symbols); sym != nil && binding.Product.Name == "" {
+				binding.Product = *sym
+			}
+			addHooks(&binding, closeHooks(typ)...)
+		}
+
+		sig, ok := pkg.TypesInfo.TypeOf(factory).(*gotypes.Signature)
+		if !ok {
+			// A registered instance rather than a factory
+			pos := d.position(factory.Pos())
+			binding.Kind = "supply"
+			binding.Scope = "singleton"
+			binding.Provider = types.Symbol{
+				Package: pkg.PkgPath,
+				Name:    supplyName(factory),
+				Kind:    "value",
+				File:    pos.Filename,
+				Line:    pos.Line,
+			}
+			if typ := pkg.TypesInfo.TypeOf(factory); typ != nil {
+				provide(typ)
+			}
+			bindings = append(bindings, binding)
+			return
+		}
+
+		if literals[astFile] == nil {
+			literals[astFile] = funcLiteralNames(astFile)
+		}
+		binding.Provider = d.funcSymbol(pkg, factory, literals[astFile], symbols)
+		for i := 0; i < sig.Params().Len(); i++ {
+			typ := sig.Params().At(i).Type()
+			binding.Requires = append(binding.Requires, types.DIValue{Type: gotypes.TypeString(typ, nil)})
+			if sym := d.typeSymbol(typ, symbols); sym != nil && !containsSymbol(binding.Dependencies, *sym) {
+				binding.Dependencies = append(binding.Dependencies, *sym)
+			}
+		}
+		for i := 0; i < sig.Results().Len(); i++ {
+			if typ := sig.Results().At(i).Type(); !isError(typ) {
+				provide(typ)
+			}
+		}
+		bindings = append(bindings, binding)
+	})
+
+	return relevantBindings(bindings, symbols)
+}
+
+// inspect calls fn for every call to the registration function in the
+// packages
+func (r *Registry) inspect(pkgs []*packages.Package, fn func(*packages.Package, *ast.File, *ast.CallExpr)) {
+	for _, pkg := range pkgs {
+		for _, astFile := range pkg.Syntax {
+			ast.Inspect(astFile, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				if callee, ok := usedObject(pkg, call.Fun).(*gotypes.Func); ok && funcID(callee) == r.call {
+					fn(pkg, astFile, call)
+				}
+				return true
+			})
+		}
+	}
+}
+
+// funcID names a function by import path, "example.com/app/registry.Register",
+// and a method by import path and receiver type,
+// "example.com/app/registry.Registry.Register"
+func funcID(fn *gotypes.Func) string {
+	fn = fn.Origin()
+	if fn.Pkg() == nil {
+		return fn.Name()
+	}
+	if recv := fn.Type().(*gotypes.Signature).Recv(); recv != nil {
+		typ := recv.Type()
+		if ptr, ok := typ.(*gotypes.Pointer); ok {
+			typ = ptr.Elem()
+		}
+		if named, ok := typ.(*gotypes.Named); ok {
+			return fn.Pkg().Path() + "." + named.Obj().Name() + "." + fn.Name()
+		}
+	}
+	return fn.Pkg().Path() + "." + fn.Name()
+}
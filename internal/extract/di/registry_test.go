@@ -0,0 +1,74 @@
+package di
+
+import (
+	"testing"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/packages"
+)
+
+// TestRegistry tests matching a home-grown registration function
+func TestRegistry(t *testing.T) {
+	// Given: Plugins registering factories by name
+	detector := loadDetector(t, "ex6")
+	registry := NewRegistry("plugins", "example.com/ex6/internal/registry.Register", nil)
+	symbols := []types.Symbol{{Name: "Plugin", Kind: "interface", Package: "example.com/ex6/internal/registry"}}
+
+	// When: We analyze the registrations
+	detector.Register(registry)
+	bindings := registry.Analyze(detector, symbols)
+
+	// Then: The registering package uses the registry
+	assert.Contains(t, detector.PackageFrameworks(), types.DIPackage{Package: "example.com/ex6/internal/plugins", Frameworks: []string{"plugins"}})
+
+	// Each factory provides the plugin under its constant name
+	upper := findBinding(t, bindings, "provide", "NewUpper")
+	assert.Equal(t, "plugins", upper.Framework)
+	assert.Equal(t, []types.DIValue{{Type: "example.com/ex6/internal/registry.Plugin", Name: "upper"}}, upper.Provides)
+	assert.Equal(t, "Plugin", upper.Product.Name)
+
+	echo := findBinding(t, bindings, "provide", "init.func1")
+	assert.Equal(t, []types.DIValue{{Type: "example.com/ex6/internal/registry.Plugin", Name: "echo"}}, echo.Provides)
+}
+
+// TestRegistryDefaults tests the default name and argument roles, and calls that never happen
+func TestRegistryDefaults(t *testing.T) {
+	// Given: A registry with no name or roles, and one whose call is not used
+	detector := loadDetector(t, "ex6")
+	registry := NewRegistry("", "example.com/ex6/internal/registry.Register", nil)
+	unused := NewRegistry("unused", "example.com/ex6/internal/registry.Names.Register", nil)
+
+	// When/Then: Defaults apply and only used calls are detected
+	assert.Equal(t, "registry", registry.Name())
+	assert.Equal(t, []string{"name", "factory"}, registry.args)
+	detector.Register(registry)
+	detector.Register(unused)
+	assert.Equal(t, []string{"dig", "do", "registry"}, detector.DetectFrameworks())
+}
+
+// TestDetectorRegister tests detecting and analyzing with a registered framework
+func TestDetectorRegister(t *testing.T) {
+	// Given: A module without a DI container
+	detector := loadDetector(t, "ex1")
+	require.Equal(t, "none", detector.DetectFramework())
+
+	// When: A framework matching the module is registered
+	detector.Register(stubFramework{})
+
+	// Then: It is detected and analyzes the bindings
+	assert.Equal(t, "stub", detector.DetectFramework())
+	assert.Equal(t, []types.DIBinding{{Framework: "stub"}}, detector.AnalyzeDIBindings(nil))
+}
+
+// stubFramework detects every module
+type stubFramework struct{}
+
+func (stubFramework) Name() string { return "stub" }
+
+func (stubFramework) Detect(d *Detector, pkg *packages.Package) bool { return true }
+
+func (stubFramework) Analyze(d *Detector, symbols []types.Symbol) []types.DIBinding {
+	return []types.DIBinding{{Framework: "stub"}}
+}
@@ -0,0 +1,351 @@
+package di
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+)
+
+// containers are the frameworks resolving dependencies by type, whose
+// bindings form a graph that can be checked. Manual constructors are checked
+// by the compiler, and registries look factories up by name at run time.
+var containers = []struct {
+	name    string
+	analyze func(d *Detector, symbols []types.Symbol) []types.DIBinding
+}{
+	{"wire", (*Detector).analyzeWireBindings},
+	{"fx", (*Detector).analyzeFxBindings},
+	{"dig", (*Detector).analyzeDigBindings},
+	{"do", (*Detector).analyzeDoBindings},
+}
+
+// builtinValues are provided by a container itself
+var builtinValues = map[string][]string{
+	"fx": {fxPath + ".Lifecycle", fxPath + ".Shutdowner", fxPath + ".DotGraph"},
+}
+
+// CheckBindings validates the graph of every container the module uses,
+// reporting values required but never provided, values provided twice,
+// providers nothing requires, and dependency cycles. Every binding of the
+// module is checked, not only those involving the symbols, which are used to
+// resolve types. Each Wire injector and each Fx, dig or do app is its own
+// graph.
+func (d *Detector) CheckBindings(symbols []types.Symbol) []types.DIIssue {
+	return validateBindings(d.containerBindings(symbols))
+}
+
+// containerBindings returns every binding of the containers the module uses
+func (d *Detector) containerBindings(symbols []types.Symbol) []types.DIBinding {
+	var bindings []types.DIBinding
+	for _, container := range containers {
+		if d.uses(container.name) {
+			bindings = append(bindings, container.analyze(d, symbols)...)
+		}
+	}
+	return bindings
+}
+
+// diNode is a binding as a node of a container graph
+type diNode struct {
+	binding  int // Index into the validated bindings
+	provides []types.DIValue
+	requires []types.DIValue
+	root     bool // Invoked or injected: its dependencies are always built
+	provider bool // Counted as a provider, unlike decorators and injector arguments
+}
+
+// diEdge leads from a node requiring a value to a node providing it
+type diEdge struct {
+	to    int
+	value types.DIValue
+}
+
+// diContainer is one graph of bindings
+type diContainer struct {
+	framework string
+	name      string
+	nodes     []diNode
+	builtin   map[string]bool
+}
+
+// validateBindings checks each container graph in the bindings. A provider
+// is reported unused only when it is unused in every graph it belongs to.
+func validateBindings(bindings []types.DIBinding) []types.DIIssue {
+	issues := []types.DIIssue{}
+	used := make(map[int]bool)
+
+	for _, c := range splitContainers(bindings) {
+		issues = append(issues, c.validate(bindings, used)...)
+	}
+
+	for i, binding := range bindings {
+		if used[i] || len(binding.Provides) == 0 {
+			continue
+		}
+		switch binding.Kind {
+		case "invoke", "decorate", "inject":
+			continue
+		}
+		issues = append(issues, types.DIIssue{
+			Kind:      "unused",
+			Framework: binding.Framework,
+			Value:     binding.Provides[0],
+			Providers: []types.Symbol{binding.Provider},
+			Chain:     []types.DIStep{},
+		})
+	}
+	return issues
+}
+
+// splitContainers groups bindings into graphs: one per Wire injector, and
+// one per Fx, dig or do app. Bindings no injector or app builds belong to no
+// graph, unless no binding of their framework is placed in an app, as in a
+// library: then the framework's bindings form a single graph.
+func splitContainers(bindings []types.DIBinding) []*diContainer {
+	var result []*diContainer
+	byKey := make(map[string]*diContainer)
+	get := func(framework, name string) *diContainer {
+		key := framework + "\x00" + name
+		if c, ok := byKey[key]; ok {
+			return c
+		}
+		c := &diContainer{framework: framework, name: name, builtin: make(map[string]bool)}
+		for _, typ := range builtinValues[framework] {
+			c.builtin[typ] = true
+		}
+		byKey[key] = c
+		result = append(result, c)
+		return c
+	}
+
+	placed := make(map[string]bool)
+	for _, binding := range bindings {
+		if len(binding.Apps) > 0 {
+			placed[binding.Framework] = true
+		}
+	}
+
+	for i, binding := range bindings {
+		switch {
+		case binding.Framework == "wire":
+			for _, injector := range binding.Injectors {
+				get(binding.Framework, injector).add(i, binding)
+			}
+		case placed[binding.Framework]:
+			for _, app := range binding.Apps {
+				get(binding.Framework, app).add(i, binding)
+			}
+		default:
+			get(binding.Framework, "").add(i, binding)
+		}
+	}
+	return result
+}
+
+// add adds a binding's nodes to the graph
+func (c *diContainer) add(i int, binding types.DIBinding) {
+	switch binding.Kind {
+	case "invoke":
+		c.nodes = append(c.nodes, diNode{binding: i, requires: binding.Requires, root: true})
+	case "decorate":
+		// A decorator needs the value it decorates without providing it anew
+		c.nodes = append(c.nodes, diNode{binding: i, requires: binding.Requires})
+	case "inject":
+		// An injector builds its results from its arguments
+		c.nodes = append(c.nodes,
+			diNode{binding: i, requires: binding.Provides, root: true},
+			diNode{binding: i, provides: binding.Requires})
+	default:
+		c.nodes = append(c.nodes, diNode{binding: i, provides: binding.Provides, requires: binding.Requires, provider: true})
+	}
+}
+
+// validate reports the missing, duplicate and cyclic values of the graph,
+// and marks the bindings whose values it requires
+func (c *diContainer) validate(bindings []types.DIBinding, used map[int]bool) []types.DIIssue {
+	var issues []types.DIIssue
+	issue := func(kind string, value types.DIValue, providers []types.Symbol, chain []types.DIStep) {
+		if chain == nil {
+			chain = []types.DIStep{}
+		}
+		issues = append(issues, types.DIIssue{
+			Kind:      kind,
+			Framework: c.framework,
+			Container: c.name,
+			Value:     value,
+			Providers: providers,
+			Chain:     chain,
+		})
+	}
+
+	providers := c.providers()
+	edges := make([][]diEdge, len(c.nodes))
+	for i, node := range c.nodes {
+		for _, value := range node.requires {
+			for _, j := range providers[diKey(value)] {
+				edges[i] = append(edges[i], diEdge{to: j, value: value})
+				if c.nodes[j].provider && c.nodes[j].binding != node.binding {
+					used[c.nodes[j].binding] = true
+				}
+			}
+		}
+	}
+	chains := c.rootChains(bindings, edges)
+
+	// Missing values, with the chain from a root to the binding requiring them
+	for i, node := range c.nodes {
+		for _, value := range node.requires {
+			if value.Optional || value.Group != "" || c.builtin[value.Type] || len(providers[diKey(value)]) > 0 {
+				continue
+			}
+			provider := bindings[node.binding].Provider
+			chain := append(append([]types.DIStep{}, chains[i]...), types.DIStep{Provider: provider, Requires: value})
+			issue("missing", value, []types.Symbol{provider}, chain)
+		}
+	}
+
+	// Values provided more than once outside a group
+	reported := make(map[string]bool)
+	for _, node := range c.nodes {
+		for _, value := range node.provides {
+			key := diKey(value)
+			if value.Group != "" || reported[key] {
+				continue
+			}
+			reported[key] = true
+			if len(providers[key]) < 2 {
+				continue
+			}
+			var syms []types.Symbol
+			for _, j := range providers[key] {
+				syms = append(syms, bindings[c.nodes[j].binding].Provider)
+			}
+			issue("duplicate", value, syms, c.consumerChain(bindings, edges, chains, key))
+		}
+	}
+
+	for _, cycle := range c.cycles(bindings, edges) {
+		var syms []types.Symbol
+		for _, step := range cycle {
+			syms = append(syms, step.Provider)
+		}
+		issue("cycle", cycle[0].Requires, syms, cycle)
+	}
+	return issues
+}
+
+// providers indexes the nodes providing each value by key
+func (c *diContainer) providers() map[string][]int {
+	providers := make(map[string][]int)
+	for i, node := range c.nodes {
+		for _, value := range node.provides {
+			key := diKey(value)
+			if len(providers[key]) == 0 || providers[key][len(providers[key])-1] != i {
+				providers[key] = append(providers[key], i)
+			}
+		}
+	}
+	return providers
+}
+
+// rootChains returns, for every node reachable from a root, the shortest
+// chain of steps from a root to it
+func (c *diContainer) rootChains(bindings []types.DIBinding, edges [][]diEdge) [][]types.DIStep {
+	chains := make([][]types.DIStep, len(c.nodes))
+	visited := make([]bool, len(c.nodes))
+	var queue []int
+	for i, node := range c.nodes {
+		if node.root {
+			visited[i] = true
+			queue = append(queue, i)
+		}
+	}
+
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+		for _, edge := range edges[i] {
+			if visited[edge.to] {
+				continue
+			}
+			visited[edge.to] = true
+			step := types.DIStep{Provider: bindings[c.nodes[i].binding].Provider, Requires: edge.value}
+			chains[edge.to] = append(append([]types.DIStep{}, chains[i]...), step)
+			queue = append(queue, edge.to)
+		}
+	}
+	return chains
+}
+
+// consumerChain returns the chain to the first node requiring a value
+func (c *diContainer) consumerChain(bindings []types.DIBinding, edges [][]diEdge, chains [][]types.DIStep, key string) []types.DIStep {
+	for i, node := range c.nodes {
+		for _, value := range node.requires {
+			if diKey(value) == key {
+				step := types.DIStep{Provider: bindings[node.binding].Provider, Requires: value}
+				return append(append([]types.DIStep{}, chains[i]...), step)
+			}
+		}
+	}
+	return nil
+}
+
+// cycles returns each dependency cycle once, as the steps around it
+func (c *diContainer) cycles(bindings []types.DIBinding, edges [][]diEdge) [][]types.DIStep {
+	var cycles [][]types.DIStep
+	seen := make(map[string]bool)
+	state := make([]int, len(c.nodes)) // 0 unvisited, 1 on the stack, 2 done
+	var stack []diEdge                 // Edges taken to reach each node on the stack
+	var path []int
+
+	var visit func(i int)
+	visit = func(i int) {
+		state[i] = 1
+		path = append(path, i)
+		for _, edge := range edges[i] {
+			switch state[edge.to] {
+			case 0:
+				stack = append(stack, edge)
+				visit(edge.to)
+				stack = stack[:len(stack)-1]
+			case 1:
+				// Back edge: the cycle runs from edge.to along the path to i
+				start := len(path) - 1
+				for path[start] != edge.to {
+					start--
+				}
+				var cycle []types.DIStep
+				var ids []string
+				for k := start; k < len(path); k++ {
+					value := edge.value
+					if k < len(path)-1 {
+						value = stack[k].value
+					}
+					provider := bindings[c.nodes[path[k]].binding].Provider
+					cycle = append(cycle, types.DIStep{Provider: provider, Requires: value})
+					ids = append(ids, provider.ID())
+				}
+				sort.Strings(ids)
+				if key := strings.Join(ids, "\x00"); !seen[key] {
+					seen[key] = true
+					cycles = append(cycles, cycle)
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[i] = 2
+	}
+
+	for i := range c.nodes {
+		if state[i] == 0 {
+			visit(i)
+		}
+	}
+	return cycles
+}
+
+// diKey identifies a container value by type, name and group
+func diKey(value types.DIValue) string {
+	return value.Type + "\x00" + value.Name + "\x00" + value.Group
+}
@@ -0,0 +1,140 @@
+package di
+
+import (
+	"testing"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fn returns a provider symbol in a test package
+func fn(name string) types.Symbol {
+	return types.Symbol{Name: name, Kind: "func", Package: "example.com/app"}
+}
+
+// value returns an unnamed container value
+func value(typ string) types.DIValue {
+	return types.DIValue{Type: typ}
+}
+
+// TestValidateBindings tests reporting missing, duplicate, unused and cyclic providers
+func TestValidateBindings(t *testing.T) {
+	// Given: An Fx app whose server needs a missing logger, two config
+	// providers, an unused metrics provider and a cache/store cycle
+	bindings := []types.DIBinding{
+		{Provider: fn("main.func1"), Framework: "fx", Kind: "invoke", Requires: []types.DIValue{value("*Server"), value(fxPath + ".Lifecycle")}},
+		{Provider: fn("NewServer"), Framework: "fx", Kind: "provide", Requires: []types.DIValue{value("Config"), value("*Logger"), value("*Store")}, Provides: []types.DIValue{value("*Server")}},
+		{Provider: fn("LoadConfig"), Framework: "fx", Kind: "provide", Provides: []types.DIValue{value("Config")}},
+		{Provider: fn("DefaultConfig"), Framework: "fx", Kind: "provide", Provides: []types.DIValue{value("Config")}},
+		{Provider: fn("NewMetrics"), Framework: "fx", Kind: "provide", Provides: []types.DIValue{value("*Metrics")}},
+		{Provider: fn("NewStore"), Framework: "fx", Kind: "provide", Requires: []types.DIValue{value("*Cache")}, Provides: []types.DIValue{value("*Store")}},
+		{Provider: fn("NewCache"), Framework: "fx", Kind: "provide", Requires: []types.DIValue{value("*Store")}, Provides: []types.DIValue{value("*Cache")}},
+		{Provider: fn("WithTracing"), Framework: "fx", Kind: "decorate", Requires: []types.DIValue{value("*Store")}, Provides: []types.DIValue{value("*Store")}},
+		{Provider: fn("NewDebug"), Framework: "fx", Kind: "provide", Requires: []types.DIValue{{Type: "*Tracer", Optional: true}, {Type: "Handler", Group: "routes"}}},
+	}
+
+	// When: We validate them
+	issues := validateBindings(bindings)
+
+	// Then: Each problem is reported once, with the chain leading to it
+	byKind := make(map[string][]types.DIIssue)
+	for _, issue := range issues {
+		byKind[issue.Kind] = append(byKind[issue.Kind], issue)
+	}
+
+	require.Len(t, byKind["missing"], 1)
+	missing := byKind["missing"][0]
+	assert.Equal(t, value("*Logger"), missing.Value)
+	assert.Equal(t, []types.Symbol{fn("NewServer")}, missing.Providers)
+	assert.Equal(t, []types.DIStep{
+		{Provider: fn("main.func1"), Requires: value("*Server")},
+		{Provider: fn("NewServer"), Requires: value("*Logger")},
+	}, missing.Chain)
+
+	require.Len(t, byKind["duplicate"], 1)
+	assert.Equal(t, value("Config"), byKind["duplicate"][0].Value)
+	assert.Equal(t, []types.Symbol{fn("LoadConfig"), fn("DefaultConfig")}, byKind["duplicate"][0].Providers)
+
+	require.Len(t, byKind["cycle"], 1)
+	assert.ElementsMatch(t, []types.Symbol{fn("NewStore"), fn("NewCache")}, byKind["cycle"][0].Providers)
+	assert.Len(t, byKind["cycle"][0].Chain, 2)
+
+	require.Len(t, byKind["unused"], 1)
+	assert.Equal(t, []types.Symbol{fn("NewMetrics")}, byKind["unused"][0].Providers)
+}
+
+// TestValidateBindingsWireInjectors tests that each Wire injector is validated on its own
+func TestValidateBindingsWireInjectors(t *testing.T) {
+	// Given: Two injectors binding the repository differently, one of them
+	// without a clock, and a provider no injector builds
+	bindings := []types.DIBinding{
+		{Provider: fn("InitializeServer"), Framework: "wire", Kind: "inject", Requires: []types.DIValue{value("Config")}, Provides: []types.DIValue{value("*Service")}, Injectors: []string{"main.InitializeServer"}},
+		{Provider: fn("InitializeTestServer"), Framework: "wire", Kind: "inject", Provides: []types.DIValue{value("*Service")}, Injectors: []string{"main.InitializeTestServer"}},
+		{Provider: fn("NewService"), Framework: "wire", Kind: "provide", Requires: []types.DIValue{value("Repository"), value("Clock")}, Provides: []types.DIValue{value("*Service")}, Injectors: []string{"main.InitializeServer", "main.InitializeTestServer"}},
+		{Provider: fn("NewSQLStore"), Framework: "wire", Kind: "provide", Requires: []types.DIValue{value("Config")}, Provides: []types.DIValue{value("Repository")}, Injectors: []string{"main.InitializeServer"}},
+		{Provider: fn("NewMemoryStore"), Framework: "wire", Kind: "provide", Provides: []types.DIValue{value("Repository")}, Injectors: []string{"main.InitializeTestServer"}},
+		{Provider: fn("NewClock"), Framework: "wire", Kind: "provide", Provides: []types.DIValue{value("Clock")}, Injectors: []string{"main.InitializeServer"}},
+		{Provider: fn("NewLegacy"), Framework: "wire", Kind: "provide", Provides: []types.DIValue{value("*Legacy")}},
+	}
+
+	// When: We validate them
+	issues := validateBindings(bindings)
+
+	// Then: Only the test injector misses the clock, injector arguments count
+	// as provided, and the set no injector uses is unused
+	require.Len(t, issues, 2)
+	assert.Equal(t, "missing", issues[0].Kind)
+	assert.Equal(t, "main.InitializeTestServer", issues[0].Container)
+	assert.Equal(t, value("Clock"), issues[0].Value)
+	assert.Equal(t, []types.DIStep{
+		{Provider: fn("InitializeTestServer"), Requires: value("*Service")},
+		{Provider: fn("NewService"), Requires: value("Clock")},
+	}, issues[0].Chain)
+	assert.Equal(t, "unused", issues[1].Kind)
+	assert.Equal(t, []types.Symbol{fn("NewLegacy")}, issues[1].Providers)
+}
+
+// TestValidateBindingsApps tests that each Fx app is validated on its own
+func TestValidateBindingsApps(t *testing.T) {
+	// Given: Two apps sharing the store module, each supplying its own config,
+	// and only the server app providing the logger its invoke needs
+	bindings := []types.DIBinding{
+		{Provider: fn("main.func1"), Framework: "fx", Kind: "invoke", Requires: []types.DIValue{value("*Store"), value("*Logger")}, Apps: []string{"app.main"}},
+		{Provider: fn("migrate"), Framework: "fx", Kind: "invoke", Requires: []types.DIValue{value("*Store"), value("*Logger")}, Apps: []string{"migrate.main"}},
+		{Provider: fn("NewStore"), Framework: "fx", Kind: "provide", Requires: []types.DIValue{value("Config")}, Provides: []types.DIValue{value("*Store")}, Apps: []string{"app.main", "migrate.main"}},
+		{Provider: fn("config.Config{…}"), Framework: "fx", Kind: "supply", Provides: []types.DIValue{value("Config")}, Apps: []string{"app.main"}},
+		{Provider: fn("config.Config{…}"), Framework: "fx", Kind: "supply", Provides: []types.DIValue{value("Config")}, Apps: []string{"migrate.main"}},
+		{Provider: fn("NewLogger"), Framework: "fx", Kind: "provide", Provides: []types.DIValue{value("*Logger")}, Apps: []string{"app.main"}},
+		{Provider: fn("NewLegacy"), Framework: "fx", Kind: "provide", Provides: []types.DIValue{value("*Legacy")}},
+	}
+
+	// When: We validate them
+	issues := validateBindings(bindings)
+
+	// Then: The configs are not duplicates, only the migration misses the
+	// logger, and the provider no app registers is unused
+	require.Len(t, issues, 2)
+	assert.Equal(t, "missing", issues[0].Kind)
+	assert.Equal(t, "migrate.main", issues[0].Container)
+	assert.Equal(t, value("*Logger"), issues[0].Value)
+	assert.Equal(t, "unused", issues[1].Kind)
+	assert.Equal(t, []types.Symbol{fn("NewLegacy")}, issues[1].Providers)
+}
+
+// TestCheckBindings tests that the example containers are wired correctly,
+// each Fx app of ex4 supplying its own config
+func TestCheckBindings(t *testing.T) {
+	for _, example := range []string{"ex4", "ex5", "ex6"} {
+		t.Run(example, func(t *testing.T) {
+			// Given: A working example module
+			detector := loadDetector(t, example)
+
+			// When: We check its containers
+			issues := detector.CheckBindings(nil)
+
+			// Then: Nothing is reported
+			assert.Empty(t, issues)
+		})
+	}
+}
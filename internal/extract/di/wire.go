@@ -0,0 +1,462 @@
+package di
+
+import (
+	"go/ast"
+	"go/build/constraint"
+	"go/parser"
+	"go/token"
+	gotypes "go/types"
+	"path/filepath"
+	"reflect"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+	"golang.org/x/tools/go/packages"
+)
+
+// wirePath is the import path of Google Wire
+const wirePath = "github.com/google/wire"
+
+// wireSet is a provider set: the bindings it declares and the sets it
+// includes by variable
+type wireSet struct {
+	bindings []int    // Indexes into wireAnalysis.bindings
+	includes []string // Keys of nested sets, "example.com/app/store.SQLSet"
+}
+
+// wireAnalysis collects the bindings of every provider set and injector
+type wireAnalysis struct {
+	d        *Detector
+	symbols  []types.Symbol
+	bindings []types.DIBinding
+	sets     map[string]*wireSet
+}
+
+// analyzeWireBindings models the Wire graph: each provider set declared with
+// wire.NewSet, and each injector function calling wire.Build in a
+// wireinject file. Providers, wire.Bind, wire.Struct, wire.FieldsOf,
+// wire.Value and wire.InterfaceValue become bindings, nested sets are
+// followed by variable, and every binding lists the injectors it is built
+// into. Injectors are themselves bindings whose provider is the function
+// generated in wire_gen.go.
+func (d *Detector) analyzeWireBindings(symbols []types.Symbol) []types.DIBinding {
+	a := &wireAnalysis{d: d, symbols: symbols, sets: make(map[string]*wireSet)}
+
+	for _, pkg := range d.pkgs {
+		for _, astFile := range pkg.Syntax {
+			for _, decl := range astFile.Decls {
+				genDecl, ok := decl.(*ast.GenDecl)
+				if !ok || genDecl.Tok != token.VAR {
+					continue
+				}
+				for _, spec := range genDecl.Specs {
+					valueSpec := spec.(*ast.ValueSpec)
+					for i, name := range valueSpec.Names {
+						if i >= len(valueSpec.Values) {
+							break
+						}
+						call, ok := valueSpec.Values[i].(*ast.CallExpr)
+						if !ok || wireFunc(pkg, call) != "NewSet" {
+							continue
+						}
+						set := &wireSet{}
+						a.addElements(pkg, call.Args, pkg.Name+"."+name.Name, set)
+						a.sets[pkg.PkgPath+"."+name.Name] = set
+					}
+				}
+			}
+		}
+	}
+
+	for _, pkg := range d.pkgs {
+		injectorPkg, files := d.wireInjectorPackage(pkg)
+		if injectorPkg == nil {
+			continue
+		}
+		for _, astFile := range injectorPkg.Syntax {
+			if !files[injectorPkg.Fset.File(astFile.Pos()).Name()] {
+				continue
+			}
+			for _, decl := range astFile.Decls {
+				if fd, ok := decl.(*ast.FuncDecl); ok && fd.Body != nil {
+					a.addInjector(pkg, injectorPkg, fd)
+				}
+			}
+		}
+	}
+
+	return a.bindings
+}
+
+// addInjector adds an injector function calling wire.Build, and marks every
+// binding its providers reach with its name
+func (a *wireAnalysis) addInjector(pkg, injectorPkg *packages.Package, fd *ast.FuncDecl) {
+	var build *ast.CallExpr
+	ast.Inspect(fd.Body, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok && build == nil && wireFunc(injectorPkg, call) == "Build" {
+			build = call
+		}
+		return build == nil
+	})
+	if build == nil {
+		return
+	}
+
+	name := pkg.Name + "." + fd.Name.Name
+	root := &wireSet{}
+	a.addElements(injectorPkg, build.Args, "", root)
+
+	visited := make(map[*wireSet]bool)
+	var mark func(set *wireSet)
+	mark = func(set *wireSet) {
+		if set == nil || visited[set] {
+			return
+		}
+		visited[set] = true
+		for _, i := range set.bindings {
+			if !containsString(a.bindings[i].Injectors, name) {
+				a.bindings[i].Injectors = append(a.bindings[i].Injectors, name)
+			}
+		}
+		for _, key := range set.includes {
+			mark(a.sets[key])
+		}
+	}
+	mark(root)
+
+	// The generated function in wire_gen.go replaces the declaration
+	obj := pkg.Types.Scope().Lookup(fd.Name.Name)
+	if obj == nil {
+		obj = injectorPkg.TypesInfo.Defs[fd.Name]
+	}
+	binding := a.newBinding(a.d.objectSymbol(obj, a.symbols), "inject", "")
+	binding.Injectors = []string{name}
+	sig := obj.Type().(*gotypes.Signature)
+	for i := 0; i < sig.Params().Len(); i++ {
+		a.require(&binding, sig.Params().At(i).Type())
+	}
+	a.provideResults(&binding, sig)
+	a.bindings = append(a.bindings, binding)
+}
+
+// addElements adds the arguments of wire.NewSet or wire.Build to a set
+func (a *wireAnalysis) addElements(pkg *packages.Package, args []ast.Expr, module string, set *wireSet) {
+	for _, arg := range args {
+		if call, ok := arg.(*ast.CallExpr); ok {
+			if fn := wireFunc(pkg, call); fn != "" {
+				if fn == "NewSet" {
+					a.addElements(pkg, call.Args, module, set)
+					continue
+				}
+				if binding, ok := a.wireCallBinding(pkg, call, fn, module); ok {
+					set.bindings = append(set.bindings, len(a.bindings))
+					a.bindings = append(a.bindings, binding)
+				}
+				continue
+			}
+		}
+
+		obj := usedObject(pkg, arg)
+		if v, ok := obj.(*gotypes.Var); ok && v.Pkg() != nil {
+			if named, ok := v.Type().(*gotypes.Named); ok && isWireObject(named.Obj(), "ProviderSet") {
+				set.includes = append(set.includes, v.Pkg().Path()+"."+v.Name())
+				continue
+			}
+		}
+
+		sig, ok := pkg.TypesInfo.TypeOf(arg).(*gotypes.Signature)
+		if !ok || obj == nil {
+			continue
+		}
+		binding := a.newBinding(a.d.objectSymbol(obj, a.symbols), "provide", module)
+		for i := 0; i < sig.Params().Len(); i++ {
+			a.require(&binding, sig.Params().At(i).Type())
+		}
+		a.provideResults(&binding, sig)
+		set.bindings = append(set.bindings, len(a.bindings))
+		a.bindings = append(a.bindings, binding)
+	}
+}
+
+// wireCallBinding builds the binding of a wire.Bind, wire.Struct,
+// wire.FieldsOf, wire.Value or wire.InterfaceValue call
+func (a *wireAnalysis) wireCallBinding(pkg *packages.Package, call *ast.CallExpr, fn, module string) (types.DIBinding, bool) {
+	switch fn {
+	case "Bind":
+		if len(call.Args) != 2 {
+			return types.DIBinding{}, false
+		}
+		iface, concrete := newType(pkg, call.Args[0]), newType(pkg, call.Args[1])
+		if iface == nil || concrete == nil {
+			return types.DIBinding{}, false
+		}
+		binding := a.newBinding(a.typeOrNameSymbol(concrete), "bind", module)
+		a.require(&binding, concrete)
+		a.provide(&binding, iface)
+		return binding, true
+
+	case "Struct", "FieldsOf":
+		if len(call.Args) == 0 {
+			return types.DIBinding{}, false
+		}
+		typ := newType(pkg, call.Args[0])
+		if typ == nil {
+			return types.DIBinding{}, false
+		}
+		st, ok := derefType(typ).Underlying().(*gotypes.Struct)
+		if !ok {
+			return types.DIBinding{}, false
+		}
+		fields := structFields(st, stringArgs(&ast.CallExpr{Args: call.Args[1:]}))
+
+		if fn == "FieldsOf" {
+			binding := a.newBinding(a.typeOrNameSymbol(typ), "fields", module)
+			a.require(&binding, typ)
+			for _, field := range fields {
+				a.provide(&binding, field.Type())
+			}
+			return binding, true
+		}
+
+		binding := a.newBinding(a.typeOrNameSymbol(typ), "struct", module)
+		for _, field := range fields {
+			a.require(&binding, field.Type())
+		}
+		// new(S) provides S and *S, new(*S) only *S
+		if _, ok := typ.(*gotypes.Pointer); !ok {
+			a.provide(&binding, typ)
+		}
+		a.provide(&binding, gotypes.NewPointer(derefType(typ)))
+		return binding, true
+
+	case "Value", "InterfaceValue":
+		if len(call.Args) == 0 {
+			return types.DIBinding{}, false
+		}
+		value := call.Args[len(call.Args)-1]
+		typ := pkg.TypesInfo.TypeOf(value)
+		if fn == "InterfaceValue" {
+			typ = newType(pkg, call.Args[0])
+		}
+		if typ == nil {
+			return types.DIBinding{}, false
+		}
+		pos := a.d.position(value.Pos())
+		binding := a.newBinding(types.Symbol{
+			Package: pkg.PkgPath,
+			Name:    supplyName(value),
+			Kind:    "value",
+			File:    pos.Filename,
+			Line:    pos.Line,
+		}, "supply", module)
+		binding.Scope = "singleton" // The same value in every injector call
+		a.provide(&binding, typ)
+		return binding, true
+	}
+	return types.DIBinding{}, false
+}
+
+// newBinding starts a Wire binding. Generated injectors build each value
+// once per call, before returning.
+func (a *wireAnalysis) newBinding(provider types.Symbol, kind, module string) types.DIBinding {
+	return types.DIBinding{
+		Provider:     provider,
+		Dependencies: []types.Symbol{},
+		Framework:    "wire",
+		Scope:        "injector",
+		Init:         "eager",
+		Kind:         kind,
+		Module:       module,
+	}
+}
+
+// require records a value a binding takes from the graph
+func (a *wireAnalysis) require(binding *types.DIBinding, typ gotypes.Type) {
+	binding.Requires = append(binding.Requires, types.DIValue{Type: gotypes.TypeString(typ, nil)})
+	if sym := a.d.typeSymbol(typ, a.symbols); sym != nil && !containsSymbol(binding.Dependencies, *sym) {
+		binding.Dependencies = append(binding.Dependencies, *sym)
+	}
+}
+
+// provide records a value a binding puts in the graph
+func (a *wireAnalysis) provide(binding *types.DIBinding, typ gotypes.Type) {
+	binding.Provides = append(binding.Provides, types.DIValue{Type: gotypes.TypeString(typ, nil)})
+	if binding.Product.Name == "" {
+		if sym := a.d.typeSymbol(typ, a.symbols); sym != nil {
+			binding.Product = *sym
+		}
+	}
+	addHooks(binding, closeHooks(typ)...)
+}
+
+// provideResults records a function's results, skipping the cleanup
+// function, recorded as a hook, and error Wire providers may return
+func (a *wireAnalysis) provideResults(binding *types.DIBinding, sig *gotypes.Signature) {
+	for i := 0; i < sig.Results().Len(); i++ {
+		typ := sig.Results().At(i).Type()
+		if isCleanup(typ) {
+			addHooks(binding, "cleanup")
+		}
+		if isError(typ) || isCleanup(typ) {
+			continue
+		}
+		a.provide(binding, typ)
+	}
+}
+
+// typeOrNameSymbol returns the module symbol of a type, or a symbol named
+// after an external type
+func (a *wireAnalysis) typeOrNameSymbol(typ gotypes.Type) types.Symbol {
+	if sym := a.d.typeSymbol(typ, a.symbols); sym != nil {
+		return *sym
+	}
+	return types.Symbol{Name: gotypes.TypeString(typ, nil), Kind: "type"}
+}
+
+// wireInjector is a package loaded with the wireinject build tag and the
+// files only built with that tag
+type wireInjector struct {
+	pkg   *packages.Package
+	files map[string]bool
+}
+
+// wireInjectorPackage loads a package with the wireinject build tag when it
+// has files only built with that tag, which the regular load ignores. It
+// returns the package and the names of those files. Loads are cached, so
+// repeated analyses of the same packages run go list once.
+func (d *Detector) wireInjectorPackage(pkg *packages.Package) (*packages.Package, map[string]bool) {
+	injector, ok := d.injectors[pkg]
+	if !ok {
+		injector.pkg, injector.files = d.loadWireInjector(pkg)
+		d.injectors[pkg] = injector
+	}
+	return injector.pkg, injector.files
+}
+
+// loadWireInjector loads a package with the wireinject build tag, see
+// wireInjectorPackage
+func (d *Detector) loadWireInjector(pkg *packages.Package) (*packages.Package, map[string]bool) {
+	files := make(map[string]bool)
+	for _, file := range pkg.IgnoredFiles {
+		if isWireinjectFile(file) {
+			files[file] = true
+		}
+	}
+	if len(files) == 0 {
+		return nil, nil
+	}
+
+	var dir string
+	for file := range files {
+		dir = filepath.Dir(file)
+	}
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedSyntax |
+			packages.NeedTypes | packages.NeedTypesInfo,
+		Dir:        dir,
+		BuildFlags: []string{"-tags=wireinject"},
+		Fset:       d.fset,
+	}
+	loaded, err := packages.Load(cfg, ".")
+	if err != nil || len(loaded) != 1 || loaded[0].TypesInfo == nil {
+		return nil, nil
+	}
+	return loaded[0], files
+}
+
+// isWireinjectFile reports whether a file's build constraint includes it
+// only when the wireinject tag is set
+func isWireinjectFile(path string) bool {
+	file, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.PackageClauseOnly|parser.ParseComments)
+	if err != nil {
+		return false
+	}
+	for _, group := range file.Comments {
+		if group.Pos() > file.Package {
+			break
+		}
+		for _, c := range group.List {
+			if !constraint.IsGoBuild(c.Text) {
+				continue
+			}
+			expr, err := constraint.Parse(c.Text)
+			if err != nil {
+				return false
+			}
+			with := expr.Eval(func(tag string) bool { return true })
+			without := expr.Eval(func(tag string) bool { return tag != "wireinject" })
+			return with && !without
+		}
+	}
+	return false
+}
+
+// structFields returns the named fields of a struct; "*" selects every
+// field not tagged `wire:"-"`
+func structFields(st *gotypes.Struct, names []string) []*gotypes.Var {
+	var fields []*gotypes.Var
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		for _, name := range names {
+			if name == field.Name() || name == "*" && reflect.StructTag(st.Tag(i)).Get("wire") != "-" {
+				fields = append(fields, field)
+				break
+			}
+		}
+	}
+	return fields
+}
+
+// newType returns T for a new(T) argument
+func newType(pkg *packages.Package, expr ast.Expr) gotypes.Type {
+	if ptr, ok := pkg.TypesInfo.TypeOf(expr).(*gotypes.Pointer); ok {
+		return ptr.Elem()
+	}
+	return nil
+}
+
+// derefType looks through a pointer
+func derefType(typ gotypes.Type) gotypes.Type {
+	if ptr, ok := typ.(*gotypes.Pointer); ok {
+		return ptr.Elem()
+	}
+	return typ
+}
+
+// isCleanup reports whether a type is a Wire cleanup function, func()
+func isCleanup(typ gotypes.Type) bool {
+	sig, ok := typ.(*gotypes.Signature)
+	return ok && sig.Params().Len() == 0 && sig.Results().Len() == 0
+}
+
+// wireFunc returns the name of the Wire function a call invokes, or ""
+func wireFunc(pkg *packages.Package, call *ast.CallExpr) string {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+	ident, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return ""
+	}
+
+	pkgName, ok := pkg.TypesInfo.Uses[ident].(*gotypes.PkgName)
+	if !ok || pkgName.Imported().Path() != wirePath {
+		return ""
+	}
+	return sel.Sel.Name
+}
+
+// isWireObject reports whether an object is the named Wire declaration
+func isWireObject(obj gotypes.Object, name string) bool {
+	return obj.Pkg() != nil && obj.Pkg().Path() == wirePath && obj.Name() == name
+}
+
+// containsString reports whether a string is in the list
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,148 @@
+package di
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	gotypes "go/types"
+	"path/filepath"
+	"testing"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/packages"
+)
+
+// TestAnalyzeWireBindings tests provider sets, Wire helpers and injectors
+func TestAnalyzeWireBindings(t *testing.T) {
+	// Given: The Wire example application
+	detector := loadDetector(t, "ex5")
+	require.Equal(t, "wire", detector.DetectFramework())
+
+	// When: We analyze the graph
+	bindings := detector.analyzeWireBindings(nil)
+
+	// Then: Each injector binds Repository to its own store
+	sqlBind := findBinding(t, bindings, "bind", "SQLStore")
+	assert.Equal(t, "store.SQLSet", sqlBind.Module)
+	assert.Equal(t, []types.DIValue{{Type: "example.com/ex5/internal/store.Repository"}}, sqlBind.Provides)
+	assert.Equal(t, []types.DIValue{{Type: "*example.com/ex5/internal/store.SQLStore"}}, sqlBind.Requires)
+	assert.Equal(t, "Repository", sqlBind.Product.Name)
+	assert.Equal(t, []string{"main.InitializeServer"}, sqlBind.Injectors)
+
+	memoryBind := findBinding(t, bindings, "bind", "MemoryStore")
+	assert.Equal(t, []string{"main.InitializeTestServer"}, memoryBind.Injectors)
+
+	// Providers skip cleanup functions and errors
+	sqlStore := findBinding(t, bindings, "provide", "NewSQLStore")
+	assert.Equal(t, []types.DIValue{{Type: "*example.com/ex5/internal/store.SQLStore"}}, sqlStore.Provides)
+
+	// Nested sets reach both injectors
+	server := findBinding(t, bindings, "provide", "NewServer")
+	assert.Equal(t, "app.ProviderSet", server.Module)
+	assert.Equal(t, []string{"main.InitializeServer", "main.InitializeTestServer"}, server.Injectors)
+
+	service := findBinding(t, bindings, "struct", "Service")
+	assert.Equal(t, "service.Set", service.Module)
+	assert.Equal(t, []types.DIValue{
+		{Type: "example.com/ex5/internal/store.Repository"},
+		{Type: "example.com/ex5/internal/service.Clock"},
+	}, service.Requires)
+	assert.Equal(t, []types.DIValue{
+		{Type: "example.com/ex5/internal/service.Service"},
+		{Type: "*example.com/ex5/internal/service.Service"},
+	}, service.Provides)
+	assert.Len(t, service.Injectors, 2)
+
+	// Values and fields are provided as their types
+	clock := findBinding(t, bindings, "supply", "SystemClock{…}")
+	assert.Equal(t, []types.DIValue{{Type: "example.com/ex5/internal/service.Clock"}}, clock.Provides)
+	timeout := findBinding(t, bindings, "supply", "Timeout(30 * time.Second)")
+	assert.Equal(t, []types.DIValue{{Type: "example.com/ex5/internal/app.Timeout"}}, timeout.Provides)
+
+	fields := findBinding(t, bindings, "fields", "Env")
+	assert.Equal(t, "", fields.Module)
+	assert.Equal(t, []types.DIValue{{Type: "example.com/ex5/internal/config.Config"}}, fields.Provides)
+	assert.Equal(t, []string{"main.InitializeServer"}, fields.Injectors)
+
+	// Injectors point at the generated code
+	injector := findBinding(t, bindings, "inject", "InitializeServer")
+	assert.Equal(t, "wire_gen.go", filepath.Base(injector.Provider.File))
+	assert.Equal(t, []types.DIValue{{Type: "example.com/ex5/internal/config.Env"}}, injector.Requires)
+	assert.Equal(t, []types.DIValue{{Type: "*example.com/ex5/internal/app.Server"}}, injector.Provides)
+	assert.Equal(t, "Server", injector.Product.Name)
+}
+
+// TestIsWireinjectFile tests recognizing files built only with the wireinject tag
+func TestIsWireinjectFile(t *testing.T) {
+	// Given: The injector declarations and the generated code
+	dir := "../../../examples/ex5/cmd/server"
+
+	// When/Then: Only the declarations are wireinject files
+	assert.True(t, isWireinjectFile(filepath.Join(dir, "wire.go")))
+	assert.False(t, isWireinjectFile(filepath.Join(dir, "wire_gen.go")))
+	assert.False(t, isWireinjectFile(filepath.Join(dir, "main.go")))
+}
+
+// TestWireInjectorPackageCached tests that the wireinject load runs once per detector
+func TestWireInjectorPackageCached(t *testing.T) {
+	// Given: The Wire example, analyzed once
+	detector := loadDetector(t, "ex5")
+	detector.AnalyzeDIBindings(nil)
+	require.Len(t, detector.injectors, len(detector.pkgs))
+	loaded := make(map[string]any)
+	for pkg, injector := range detector.injectors {
+		if injector.pkg != nil {
+			loaded[pkg.PkgPath] = injector.pkg
+		}
+	}
+	require.Len(t, loaded, 1)
+
+	// When: We analyze and check it again
+	detector.AnalyzeDIBindings(nil)
+	detector.CheckBindings(nil)
+
+	// Then: The package loaded the first time is reused
+	for pkg, injector := range detector.injectors {
+		if injector.pkg != nil {
+			assert.Same(t, loaded[pkg.PkgPath], injector.pkg)
+		}
+	}
+}
+
+// TestWireFuncRequiresImport tests that calls on a local value named wire are not Wire calls
+func TestWireFuncRequiresImport(t *testing.T) {
+	// Given: A package whose variable wire has a Build method
+	src := `package p
+
+type builder struct{}
+
+func (builder) Build(...any) {}
+
+var wire builder
+
+func init() { wire.Build(nil) }
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "p.go", src, 0)
+	require.NoError(t, err)
+	info := &gotypes.Info{Uses: make(map[*ast.Ident]gotypes.Object)}
+	_, err = (&gotypes.Config{}).Check("p", fset, []*ast.File{file}, info)
+	require.NoError(t, err)
+
+	var call *ast.CallExpr
+	ast.Inspect(file, func(n ast.Node) bool {
+		if c, ok := n.(*ast.CallExpr); ok {
+			call = c
+		}
+		return true
+	})
+	require.NotNil(t, call)
+
+	// When: We ask which Wire function the call invokes
+	name := wireFunc(&packages.Package{TypesInfo: info}, call)
+
+	// Then: It is none
+	assert.Empty(t, name)
+}
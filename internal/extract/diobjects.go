@@ -0,0 +1,88 @@
+package extract
+
+import (
+	"strings"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+)
+
+// DIObjectGraphs builds the object graph of every DI invoke and Wire injector
+// the named function is, declares as a function literal (an fx.Invoke in
+// main), or calls directly (a Wire injector called from main). Interface
+// parameters are resolved to the concrete type their provider builds.
+func (w *Workspace) DIObjectGraphs(query string) ([]types.DIObjectGraph, error) {
+	entry, err := w.resolve(query)
+	if err != nil {
+		return nil, err
+	}
+
+	id := entry.symbol.ID()
+	roots := map[string]bool{id: true}
+	for _, ref := range w.directReferences(entry.symbol, make(map[string][]types.Reference)) {
+		roots[ref.Symbol.ID()] = true
+	}
+	isRoot := func(sym types.Symbol) bool {
+		return roots[sym.ID()] || strings.HasPrefix(sym.ID(), id+".func")
+	}
+
+	detector, _ := w.locator.diDetector()
+	graphs := detector.ObjectGraphs(isRoot, w.Symbols())
+	for i := range graphs {
+		w.resolveImplementations(&graphs[i])
+	}
+	return graphs, nil
+}
+
+// resolveImplementations sets the concrete type behind each interface
+// parameter of an object graph
+func (w *Workspace) resolveImplementations(graph *types.DIObjectGraph) {
+	objects := make(map[string]types.DIObject, len(graph.Objects))
+	for _, object := range graph.Objects {
+		objects[object.ID] = object
+	}
+
+	interfaces := make(map[string]types.Symbol)
+	for _, entry := range w.symbols {
+		if entry.symbol.Kind == "interface" {
+			interfaces[entry.symbol.ID()] = entry.symbol
+		}
+	}
+
+	analyzer := NewInterfaceAnalyzer(w.locator.pkgs, w.locator.fset)
+	analyzer.SetIndex(w.locator.implementationIndex())
+
+	for i, edge := range graph.Edges {
+		if iface, ok := interfaces[strings.TrimPrefix(edge.Value.Type, "*")]; ok {
+			graph.Edges[i].Implementation = w.implementationOf(analyzer, iface, objects[edge.To])
+		}
+	}
+}
+
+// implementationOf returns the implementation of an interface an object
+// provides: the type a Wire binding binds, or the concrete type its
+// constructor returns
+func (w *Workspace) implementationOf(analyzer *InterfaceAnalyzer, iface types.Symbol, object types.DIObject) *types.Symbol {
+	impls := w.locator.implementationIndex().Implementations(iface)
+	find := func(id string) *types.Symbol {
+		for _, impl := range impls {
+			if impl.Type.ID() == id {
+				sym := w.indexed(impl.Type)
+				return &sym
+			}
+		}
+		return nil
+	}
+
+	if object.Kind == "bind" {
+		return find(object.Provider.ID())
+	}
+	if object.Provider.Kind != "func" && object.Provider.Kind != "method" {
+		return nil
+	}
+
+	typeName := analyzer.constructorType(object.Provider)
+	if typeName == nil || typeName.Pkg() == nil {
+		return nil
+	}
+	return find(typeName.Pkg().Path() + "." + typeName.Name())
+}
@@ -0,0 +1,199 @@
+package extract
+
+import (
+	"go/ast"
+	"go/token"
+	gotypes "go/types"
+	"strings"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+)
+
+// effectKinds are the side effects in the order they are reported
+var effectKinds = []string{"reads-globals", "writes-globals", "io", "logs", "panics"}
+
+// ioPackages are the packages whose functions and methods perform I/O.
+// Subpackages count too, except the pure ones in pureIOPackages.
+var ioPackages = []string{"net", "os", "database/sql", "io/ioutil", "syscall"}
+
+// pureIOPackages are subpackages of ioPackages that only compute
+var pureIOPackages = map[string]bool{"net/url": true, "net/netip": true, "net/mail": true, "net/textproto": true}
+
+// logPackages are the logging packages
+var logPackages = map[string]bool{"log": true, "log/slog": true}
+
+// classifyEffects sets the side effects of the extract's functions and
+// methods: reading or writing package-level variables, I/O through the net,
+// os and database/sql packages or fmt's printing, logging, and panicking or
+// exiting. A function has the effects of the module functions it calls,
+// including the implementations of interface methods; a module function
+// with none is "pure". Functions outside the module only get the effects
+// known for their package, and none otherwise. Sentinel errors are not
+// counted as globals.
+func classifyEffects(locator *Locator, ext *types.Extract) {
+	a := newFlowAnalysis(locator, 0)
+
+	effects := make(map[*gotypes.Func]map[string]bool)
+	var queue []*gotypes.Func
+	for obj, fn := range a.funcs {
+		effects[obj] = directEffects(fn, a.funcs)
+		if len(effects[obj]) > 0 {
+			queue = append(queue, obj)
+		}
+	}
+
+	for len(queue) > 0 {
+		callee := queue[0]
+		queue = queue[1:]
+		for _, call := range a.calls[callee] {
+			caller := effects[call.fn.obj]
+			changed := false
+			for effect := range effects[callee] {
+				if !caller[effect] {
+					caller[effect] = true
+					changed = true
+				}
+			}
+			if changed {
+				queue = append(queue, call.fn.obj)
+			}
+		}
+	}
+
+	byID := make(map[string][]string)
+	for obj, found := range effects {
+		list := []string{}
+		for _, effect := range effectKinds {
+			if found[effect] {
+				list = append(list, effect)
+			}
+		}
+		if len(list) == 0 {
+			list = append(list, "pure")
+		}
+		byID[objectID(obj)] = list
+	}
+
+	setEffects := func(sym *types.Symbol) {
+		if sym.Kind != "func" && sym.Kind != "method" {
+			return
+		}
+		if found, ok := byID[sym.ID()]; ok {
+			sym.Effects = found
+		} else {
+			sym.Effects = callEffects(sym.Package, sym.Name)
+		}
+	}
+	setEffects(&ext.Target)
+	for i := range ext.References {
+		setEffects(&ext.References[i].Symbol)
+	}
+}
+
+// directEffects returns the effects of a function's own statements and of
+// its calls outside the module
+func directEffects(fn *flowFunc, module map[*gotypes.Func]*flowFunc) map[string]bool {
+	effects := make(map[string]bool)
+	info := fn.pkg.TypesInfo
+
+	ast.Inspect(fn.decl.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.Ident:
+			v, ok := info.Uses[node].(*gotypes.Var)
+			if !ok || v.Pkg() == nil || v.Parent() != v.Pkg().Scope() || isError(v.Type()) {
+				return true
+			}
+			if writesGlobal(fn, node) {
+				effects["writes-globals"] = true
+			} else {
+				effects["reads-globals"] = true
+			}
+		case *ast.CallExpr:
+			switch callee := calleeObject(fn.pkg, node.Fun).(type) {
+			case *gotypes.Builtin:
+				if callee.Name() == "panic" {
+					effects["panics"] = true
+				}
+			case *gotypes.Func:
+				if module[callee] == nil && callee.Pkg() != nil {
+					for _, effect := range callEffects(callee.Pkg().Path(), callee.Name()) {
+						effects[effect] = true
+					}
+				}
+			}
+		}
+		return true
+	})
+	return effects
+}
+
+// writesGlobal reports whether a use of a package-level variable is the
+// target of an assignment or increment, directly or through its fields,
+// elements or pointer
+func writesGlobal(fn *flowFunc, ident *ast.Ident) bool {
+	var node ast.Node = ident
+	for {
+		switch parent := fn.parent(node).(type) {
+		case *ast.SelectorExpr:
+			if parent.X != node && parent.Sel != node {
+				return false
+			}
+			node = parent
+		case *ast.IndexExpr:
+			if parent.X != node {
+				return false
+			}
+			node = parent
+		case *ast.StarExpr, *ast.ParenExpr:
+			node = parent
+		case *ast.AssignStmt:
+			if parent.Tok == token.DEFINE {
+				return false
+			}
+			for _, lhs := range parent.Lhs {
+				if lhs == node {
+					return true
+				}
+			}
+			return false
+		case *ast.IncDecStmt:
+			return true
+		default:
+			return false
+		}
+	}
+}
+
+// callEffects returns the known effects of calling a function or method of
+// another module or the standard library, by package path and name
+func callEffects(path, name string) []string {
+	switch {
+	case logPackages[path]:
+		if strings.HasPrefix(name, "Fatal") || strings.HasPrefix(name, "Panic") {
+			return []string{"logs", "panics"}
+		}
+		for _, prefix := range []string{"Print", "Debug", "Info", "Warn", "Error", "Log", "Output"} {
+			if strings.HasPrefix(name, prefix) {
+				return []string{"logs"}
+			}
+		}
+		return nil
+	case path == "fmt":
+		if strings.HasPrefix(name, "Print") || strings.HasPrefix(name, "Fprint") || strings.HasPrefix(name, "Scan") || strings.HasPrefix(name, "Fscan") {
+			return []string{"io"}
+		}
+		return nil
+	case path == "os" && name == "Exit":
+		return []string{"panics"}
+	case path == "os" && strings.HasPrefix(name, "Is"):
+		return nil
+	case pureIOPackages[path]:
+		return nil
+	}
+	for _, pkg := range ioPackages {
+		if path == pkg || strings.HasPrefix(path, pkg+"/") {
+			return []string{"io"}
+		}
+	}
+	return nil
+}
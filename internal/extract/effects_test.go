@@ -0,0 +1,94 @@
+package extract
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// symbolEffects returns the effects of an extract's target and references by ID
+func symbolEffects(extract *types.Extract) map[string][]string {
+	effects := map[string][]string{extract.Target.ID(): extract.Target.Effects}
+	for _, ref := range extract.References {
+		effects[ref.Symbol.ID()] = ref.Symbol.Effects
+	}
+	return effects
+}
+
+// TestEffectsTransitive tests that callers take the effects of the functions they call
+func TestEffectsTransitive(t *testing.T) {
+	// Given: The security example's server, which reads files and serves HTTP through its handlers
+	ws := loadExample(t, "ex7")
+
+	// When: We extract main
+	effects := symbolEffects(extractAt(t, ws, types.Options{Depth: 2}, 14, 6, "cmd", "server", "main.go"))
+
+	// Then: main performs I/O, logs and exits, and each helper has its own effects
+	assert.Equal(t, []string{"io", "logs", "panics"}, effects["example.com/ex7/cmd/server.main"])
+	assert.Equal(t, []string{"pure"}, effects["example.com/ex7/internal/store.New"])
+	assert.Equal(t, []string{"io"}, effects["example.com/ex7/internal/store.Store.Seed"])
+	assert.Equal(t, []string{"reads-globals", "io"}, effects["example.com/ex7/internal/server.Handler.Profile"])
+
+	// And: Functions outside the module get the effects known for their package
+	assert.Equal(t, []string{"logs", "panics"}, effects["log.Fatal"])
+	assert.Equal(t, []string{"io"}, effects["os.ReadFile"])
+}
+
+// TestEffectsGlobals tests telling reads of package-level variables from writes
+func TestEffectsGlobals(t *testing.T) {
+	// Given: The plugin registry, a package-level map
+	ws := loadExample(t, "ex6")
+
+	// When/Then: Register writes it and Names reads it
+	effects := symbolEffects(extractAt(t, ws, types.Options{Depth: 2}, 16, 6, "internal", "registry", "registry.go"))
+	assert.Equal(t, []string{"writes-globals"}, effects["example.com/ex6/internal/registry.Register"])
+	effects = symbolEffects(extractAt(t, ws, types.Options{Depth: 2}, 21, 6, "internal", "registry", "registry.go"))
+	assert.Equal(t, []string{"reads-globals"}, effects["example.com/ex6/internal/registry.Names"])
+
+	// And: Types have no effects
+	assert.Empty(t, effects["example.com/ex6/internal/registry.Factory"])
+}
+
+// TestEffectsPure tests helpers without effects, and callers of printing helpers
+func TestEffectsPure(t *testing.T) {
+	// Given: The math example, whose Add prints on invalid input
+	ws := loadExample(t, "ex1")
+
+	// When: We extract Add
+	effects := symbolEffects(extractAt(t, ws, types.Options{Depth: 2}, 7, 6, "pkg", "math", "add.go"))
+
+	// Then: Add performs I/O, its validation helper is pure
+	assert.Equal(t, []string{"io"}, effects["example.com/ex1/pkg/math.Add"])
+	assert.Equal(t, []string{"pure"}, effects["example.com/ex1/pkg/math.validateInputs"])
+
+	// And: Sentinel errors are not globals
+	ws = loadExample(t, "ex2")
+	effects = symbolEffects(extractAt(t, ws, types.Options{Depth: 2}, 16, 21, "internal", "adapters", "memory", "repo.go"))
+	assert.Equal(t, []string{"pure"}, effects["example.com/ex2/internal/adapters/memory.Repository.Get"])
+}
+
+// TestFlowIndexShared tests that analyses share one flow index per load and keep their own state
+func TestFlowIndexShared(t *testing.T) {
+	// Given: The hexagonal example and a parameter to slice
+	ws := loadExample(t, "ex2")
+	target := types.Target{File: filepath.Join(ws.Root(), "internal", "app", "service.go"), Line: 17, Column: 34}
+
+	// When: We slice it twice and start another analysis
+	first, err := ws.DataFlow(target, "backward", 1)
+	require.NoError(t, err)
+	second, err := ws.DataFlow(target, "backward", 1)
+	require.NoError(t, err)
+	a, b := newFlowAnalysis(ws.locator, 0), newFlowAnalysis(ws.locator, 0)
+
+	// Then: The index is built once and the second slice is not cut short by the first
+	assert.Same(t, a.flowIndex, b.flowIndex)
+	assert.Empty(t, b.visited)
+	assert.Equal(t, flowSteps(first), flowSteps(second))
+
+	// And: Reloading the module rebuilds it
+	require.NoError(t, ws.locator.loadModule(ws.Root()))
+	assert.NotSame(t, a.flowIndex, ws.locator.flowIndex())
+}
@@ -0,0 +1,509 @@
+package extract
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	gotypes "go/types"
+	"strings"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+)
+
+// errorType is the built-in error interface
+var errorType = gotypes.Universe.Lookup("error").Type()
+
+// errorWalk traces the errors a function returns back to their origins,
+// following calls into the functions the extract references
+type errorWalk struct {
+	a        *flowAnalysis
+	target   *flowFunc
+	refs     map[string]bool // IDs of the extract's symbols
+	paths    []types.ErrorPath
+	seen     map[string]bool         // Paths already found
+	visited  map[gotypes.Object]bool // Functions and variables being traced
+	deferred map[*gotypes.Var]bool   // Variables whose deferred assignments are being traced
+}
+
+// errorPaths enumerates the errors the target function can return: where
+// each is created, the fmt.Errorf calls wrapping it, and the callers of the
+// target matching it with errors.Is, errors.As or ==. Calls are followed
+// into the functions among the extract's references, including the
+// implementations of interface methods. It returns nil for targets that are
+// not functions or return no error.
+func errorPaths(locator *Locator, target types.Symbol, references []types.Reference) []types.ErrorPath {
+	if target.Kind != "func" && target.Kind != "method" {
+		return nil
+	}
+	a := newFlowAnalysis(locator, 0)
+
+	var fn *flowFunc
+	for obj, candidate := range a.funcs {
+		if objectID(obj) == target.ID() {
+			fn = candidate
+		}
+	}
+	if fn == nil || errorResult(fn.obj) < 0 {
+		return nil
+	}
+
+	w := &errorWalk{
+		a:        a,
+		target:   fn,
+		refs:     map[string]bool{target.ID(): true},
+		seen:     make(map[string]bool),
+		visited:  make(map[gotypes.Object]bool),
+		deferred: make(map[*gotypes.Var]bool),
+	}
+	for _, ref := range references {
+		w.refs[ref.Symbol.ID()] = true
+	}
+
+	w.returns(fn, nil, nil, 0)
+	w.checks()
+	return w.paths
+}
+
+// errorResult returns the index of a function's error result, or -1
+func errorResult(fn *gotypes.Func) int {
+	results := fn.Type().(*gotypes.Signature).Results()
+	for i := results.Len() - 1; i >= 0; i-- {
+		if isError(results.At(i).Type()) {
+			return i
+		}
+	}
+	return -1
+}
+
+// isError reports whether a type is the error interface
+func isError(typ gotypes.Type) bool {
+	return typ != nil && gotypes.Identical(typ, errorType)
+}
+
+// returns traces the error result of every return statement of a function
+func (w *errorWalk) returns(fn *flowFunc, via, wraps []string, ret int) {
+	if w.visited[fn.obj] {
+		return
+	}
+	w.visited[fn.obj] = true
+	defer delete(w.visited, fn.obj)
+
+	index := errorResult(fn.obj)
+	named := fn.obj.Type().(*gotypes.Signature).Results().At(index)
+	ast.Inspect(fn.decl.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			line := ret
+			if fn == w.target {
+				line = w.a.locator.fset.Position(node.Pos()).Line
+			}
+			switch {
+			case len(node.Results) == 0 && named.Name() != "" && named.Name() != "_":
+				// A bare return: the error is the named result's value
+				w.assignments(fn, nil, named, via, wraps, line)
+			case len(node.Results) == 1 && index > 0:
+				// return f(), with f returning the same results
+				w.origins(fn, node.Results[0], via, wraps, line)
+			case index < len(node.Results):
+				w.origins(fn, node.Results[index], via, wraps, line)
+			}
+		}
+		return true
+	})
+}
+
+// origins traces an error expression to where the error is created
+func (w *errorWalk) origins(fn *flowFunc, expr ast.Expr, via, wraps []string, ret int) {
+	info := fn.pkg.TypesInfo
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		if e.Name == "nil" && info.Uses[e] == gotypes.Universe.Lookup("nil") {
+			return
+		}
+		v, ok := info.Uses[e].(*gotypes.Var)
+		if !ok {
+			w.add(fn, e, "expr", via, wraps, ret)
+			return
+		}
+		if w.a.owners[v] == nil {
+			w.sentinel(fn, e, v, via, wraps, ret)
+			return
+		}
+		w.assignments(fn, e, v, via, wraps, ret)
+
+	case *ast.SelectorExpr:
+		if v, ok := info.Uses[e.Sel].(*gotypes.Var); ok && !v.IsField() {
+			w.sentinel(fn, e, v, via, wraps, ret)
+			return
+		}
+		w.add(fn, e, "expr", via, wraps, ret)
+
+	case *ast.CallExpr:
+		w.call(fn, e, via, wraps, ret)
+
+	case *ast.CompositeLit, *ast.UnaryExpr:
+		path := w.newPath(fn, e, "type", via, wraps, ret)
+		if typ := errorValueType(info.TypeOf(e)); typ != nil {
+			path.Sentinel = objectID(typ.Obj())
+		}
+		w.addPath(path)
+
+	default:
+		w.add(fn, e, "expr", via, wraps, ret)
+	}
+}
+
+// assignments traces a local variable through the values assigned to it.
+// Assignments in deferred function literals run after the return, so they
+// replace the value returned; their own uses of the variable see the value
+// assigned before.
+func (w *errorWalk) assignments(fn *flowFunc, use *ast.Ident, v *gotypes.Var, via, wraps []string, ret int) {
+	if index, ok := paramIndex(fn.obj, v); ok && index >= 0 {
+		w.add(fn, use, "param", via, wraps, ret)
+		return
+	}
+
+	var deferred, sites []flowSite
+	for _, site := range w.a.sites[v] {
+		if site.fn != fn {
+			continue
+		}
+		if assigned(fn, site.ident) && isDeferred(fn, site.ident) {
+			deferred = append(deferred, site)
+		} else {
+			sites = append(sites, site)
+		}
+	}
+	if len(deferred) > 0 && !w.deferred[v] {
+		w.deferred[v] = true
+		defer delete(w.deferred, v)
+		for _, site := range deferred {
+			w.assignment(fn, site.ident, via, wraps, ret)
+		}
+		return
+	}
+
+	if w.visited[v] {
+		return
+	}
+	w.visited[v] = true
+	defer delete(w.visited, v)
+
+	for _, site := range sites {
+		w.assignment(fn, site.ident, via, wraps, ret)
+	}
+}
+
+// assignment traces the value assigned to a variable at one of its sites
+func (w *errorWalk) assignment(fn *flowFunc, ident *ast.Ident, via, wraps []string, ret int) {
+	switch p := fn.parent(ident).(type) {
+	case *ast.AssignStmt:
+		for i, lhs := range p.Lhs {
+			if lhs != ident {
+				continue
+			}
+			if len(p.Lhs) == len(p.Rhs) {
+				w.origins(fn, p.Rhs[i], via, wraps, ret)
+			} else {
+				w.origins(fn, p.Rhs[0], via, wraps, ret)
+			}
+		}
+	case *ast.ValueSpec:
+		for i, name := range p.Names {
+			if name != ident || len(p.Values) == 0 {
+				continue
+			}
+			if len(p.Names) == len(p.Values) {
+				w.origins(fn, p.Values[i], via, wraps, ret)
+			} else {
+				w.origins(fn, p.Values[0], via, wraps, ret)
+			}
+		}
+	}
+}
+
+// assigned reports whether an identifier is assigned to
+func assigned(fn *flowFunc, ident *ast.Ident) bool {
+	if p, ok := fn.parent(ident).(*ast.AssignStmt); ok {
+		for _, lhs := range p.Lhs {
+			if lhs == ident {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// call traces the error of a call: created by errors.New or fmt.Errorf,
+// wrapped with %w, or returned by a callee
+func (w *errorWalk) call(fn *flowFunc, call *ast.CallExpr, via, wraps []string, ret int) {
+	info := fn.pkg.TypesInfo
+	if tv, ok := info.Types[call.Fun]; ok && tv.IsType() {
+		path := w.newPath(fn, call, "type", via, wraps, ret)
+		if typ := errorValueType(tv.Type); typ != nil {
+			path.Sentinel = objectID(typ.Obj())
+		}
+		w.addPath(path)
+		return
+	}
+
+	callee, ok := calleeObject(fn.pkg, call.Fun).(*gotypes.Func)
+	if !ok {
+		w.add(fn, call, "callee", via, wraps, ret)
+		return
+	}
+
+	switch objectID(callee) {
+	case "errors.New":
+		path := w.newPath(fn, call, "new", via, wraps, ret)
+		path.Message = constantString(info, call.Args)
+		w.addPath(path)
+		return
+
+	case "fmt.Errorf":
+		format := constantString(info, call.Args)
+		if !strings.Contains(format, "%w") {
+			path := w.newPath(fn, call, "errorf", via, wraps, ret)
+			path.Message = format
+			w.addPath(path)
+			return
+		}
+		wrapped := append(append([]string{}, wraps...), format)
+		for _, arg := range call.Args[1:] {
+			if isError(info.TypeOf(arg)) {
+				w.origins(fn, arg, via, wrapped, ret)
+			}
+		}
+		return
+
+	case "errors.Join":
+		for _, arg := range call.Args {
+			w.origins(fn, arg, via, wraps, ret)
+		}
+		return
+	}
+
+	followed := false
+	for _, target := range w.a.targets(callee) {
+		callee := w.a.funcs[target]
+		if !w.followed(target) || errorResult(target) < 0 {
+			continue
+		}
+		followed = true
+		w.returns(callee, append(append([]string{}, via...), objectID(target)), wraps, ret)
+	}
+	if !followed {
+		path := w.newPath(fn, call, "callee", via, wraps, ret)
+		path.Value = gotypes.ExprString(call.Fun)
+		w.addPath(path)
+	}
+}
+
+// followed reports whether the extract references a function, or the type
+// of a method
+func (w *errorWalk) followed(fn *gotypes.Func) bool {
+	if w.refs[objectID(fn)] {
+		return true
+	}
+	recv := fn.Type().(*gotypes.Signature).Recv()
+	if recv == nil {
+		return false
+	}
+	typ := recv.Type()
+	if ptr, ok := typ.(*gotypes.Pointer); ok {
+		typ = ptr.Elem()
+	}
+	named, ok := typ.(*gotypes.Named)
+	return ok && w.refs[objectID(named.Obj())]
+}
+
+// sentinel records a package-level error variable, with the message it is
+// created with
+func (w *errorWalk) sentinel(fn *flowFunc, expr ast.Expr, v *gotypes.Var, via, wraps []string, ret int) {
+	path := w.newPath(fn, expr, "sentinel", via, wraps, ret)
+	path.Sentinel = objectID(v)
+	path.Message = w.sentinelMessage(v)
+	w.addPath(path)
+}
+
+// sentinelMessage returns the text a sentinel is created with by errors.New
+func (w *errorWalk) sentinelMessage(v *gotypes.Var) string {
+	for _, pkg := range w.a.locator.pkgs {
+		if pkg.Types != v.Pkg() {
+			continue
+		}
+		for _, file := range pkg.Syntax {
+			for _, decl := range file.Decls {
+				gd, ok := decl.(*ast.GenDecl)
+				if !ok || gd.Tok != token.VAR {
+					continue
+				}
+				for _, spec := range gd.Specs {
+					vs := spec.(*ast.ValueSpec)
+					for i, name := range vs.Names {
+						if pkg.TypesInfo.Defs[name] != v || i >= len(vs.Values) {
+							continue
+						}
+						if call, ok := vs.Values[i].(*ast.CallExpr); ok {
+							return constantString(pkg.TypesInfo, call.Args)
+						}
+					}
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// newPath starts the path of an error created at a node
+func (w *errorWalk) newPath(fn *flowFunc, node ast.Expr, origin string, via, wraps []string, ret int) types.ErrorPath {
+	pos := w.a.locator.fset.Position(node.Pos())
+	return types.ErrorPath{
+		Origin:   origin,
+		Value:    gotypes.ExprString(node),
+		Function: objectID(fn.obj),
+		File:     pos.Filename,
+		Line:     pos.Line,
+		Return:   ret,
+		Wraps:    wraps,
+		Via:      via,
+	}
+}
+
+// add records an error created at a node
+func (w *errorWalk) add(fn *flowFunc, node ast.Expr, origin string, via, wraps []string, ret int) {
+	w.addPath(w.newPath(fn, node, origin, via, wraps, ret))
+}
+
+// addPath records a path once per origin, wrapping and return
+func (w *errorWalk) addPath(path types.ErrorPath) {
+	key := fmt.Sprintf("%s:%d:%s>%d>%s", path.File, path.Line, path.Value, path.Return, strings.Join(path.Wraps, "|"))
+	if w.seen[key] {
+		return
+	}
+	w.seen[key] = true
+	w.paths = append(w.paths, path)
+}
+
+// checks finds the callers of the target matching each sentinel or error
+// type with errors.Is, errors.As or ==
+func (w *errorWalk) checks() {
+	callers := make(map[*flowFunc]bool)
+	var order []*flowFunc
+	for _, call := range w.a.calls[w.target.obj] {
+		if !callers[call.fn] {
+			callers[call.fn] = true
+			order = append(order, call.fn)
+		}
+	}
+
+	for _, caller := range order {
+		info := caller.pkg.TypesInfo
+		ast.Inspect(caller.decl.Body, func(n ast.Node) bool {
+			var check string
+			var against ast.Expr
+			switch node := n.(type) {
+			case *ast.CallExpr:
+				callee := calleeObject(caller.pkg, node.Fun)
+				if callee == nil || len(node.Args) != 2 {
+					return true
+				}
+				switch objectID(callee) {
+				case "errors.Is":
+					check, against = "errors.Is", node.Args[1]
+				case "errors.As":
+					check, against = "errors.As", node.Args[1]
+				}
+			case *ast.BinaryExpr:
+				if (node.Op == token.EQL || node.Op == token.NEQ) && isError(info.TypeOf(node.X)) {
+					w.check(caller, node, "==", node.X)
+					check, against = "==", node.Y
+				}
+			case *ast.CaseClause:
+				if sw, ok := caller.parent(node).(*ast.BlockStmt); ok {
+					if stmt, ok := caller.parent(sw).(*ast.SwitchStmt); ok && stmt.Tag != nil && isError(info.TypeOf(stmt.Tag)) {
+						for _, expr := range node.List {
+							w.check(caller, expr, "==", expr)
+						}
+					}
+				}
+			}
+			if against != nil {
+				w.check(caller, n, check, against)
+			}
+			return true
+		})
+	}
+}
+
+// check attaches a caller's match against an expression to the paths of
+// the sentinel or error type it names
+func (w *errorWalk) check(caller *flowFunc, node ast.Node, check string, against ast.Expr) {
+	info := caller.pkg.TypesInfo
+	var id string
+	switch check {
+	case "errors.As":
+		if typ := errorValueType(info.TypeOf(against)); typ != nil {
+			id = objectID(typ.Obj())
+		}
+	default:
+		var ident *ast.Ident
+		switch e := ast.Unparen(against).(type) {
+		case *ast.Ident:
+			ident = e
+		case *ast.SelectorExpr:
+			ident = e.Sel
+		}
+		if ident != nil {
+			if v, ok := info.Uses[ident].(*gotypes.Var); ok {
+				id = objectID(v)
+			}
+		}
+	}
+	if id == "" {
+		return
+	}
+
+	pos := w.a.locator.fset.Position(node.Pos())
+	for i := range w.paths {
+		if w.paths[i].Sentinel == id {
+			w.paths[i].CheckedBy = append(w.paths[i].CheckedBy, types.ErrorCheck{
+				Function: objectID(caller.obj),
+				Check:    check,
+				File:     pos.Filename,
+				Line:     pos.Line,
+			})
+		}
+	}
+}
+
+// errorValueType returns the named type of an error value, looking through
+// pointers ("*NotFoundError" for &NotFoundError{} or errors.As targets)
+func errorValueType(typ gotypes.Type) *gotypes.Named {
+	for {
+		ptr, ok := typ.(*gotypes.Pointer)
+		if !ok {
+			break
+		}
+		typ = ptr.Elem()
+	}
+	named, ok := typ.(*gotypes.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return nil
+	}
+	return named
+}
+
+// constantString returns the first argument when it is a constant string
+func constantString(info *gotypes.Info, args []ast.Expr) string {
+	if len(args) == 0 {
+		return ""
+	}
+	if tv, ok := info.Types[args[0]]; ok && tv.Value != nil && tv.Value.Kind() == constant.String {
+		return constant.StringVal(tv.Value)
+	}
+	return ""
+}
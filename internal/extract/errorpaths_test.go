@@ -0,0 +1,97 @@
+package extract
+
+import (
+	"testing"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestErrorPathsAcrossLayers tests tracing errors through ports into adapters
+func TestErrorPathsAcrossLayers(t *testing.T) {
+	// Given: The cancel use case, whose repository wraps a domain sentinel
+	ws := loadExample(t, "ex9")
+
+	// When: We extract it with its direct dependencies
+	paths := extractAt(t, ws, types.Options{Depth: 1}, 22, 0, "internal", "app", "service.go").ErrorPaths
+
+	// Then: The sentinel is found behind the repository port, wrapped and checked by the handler
+	require.Len(t, paths, 3)
+	notFound := paths[0]
+	assert.Equal(t, "sentinel", notFound.Origin)
+	assert.Equal(t, "domain.ErrNotFound", notFound.Value)
+	assert.Equal(t, "order not found", notFound.Message)
+	assert.Equal(t, "example.com/ex9/internal/domain.ErrNotFound", notFound.Sentinel)
+	assert.Equal(t, 25, notFound.Return)
+	assert.Equal(t, []string{"get order %s: %w"}, notFound.Wraps)
+	assert.Equal(t, []string{"example.com/ex9/internal/adapters/memory.Repository.Get"}, notFound.Via)
+	require.Len(t, notFound.CheckedBy, 1)
+	assert.Equal(t, "example.com/ex9/internal/adapters/rest.Handler.ServeHTTP", notFound.CheckedBy[0].Function)
+	assert.Equal(t, "errors.Is", notFound.CheckedBy[0].Check)
+
+	// And: The status error is matched by type
+	status := paths[1]
+	assert.Equal(t, "type", status.Origin)
+	assert.Equal(t, "example.com/ex9/internal/domain.StatusError", status.Sentinel)
+	assert.Equal(t, 28, status.Return)
+	require.Len(t, status.CheckedBy, 1)
+	assert.Equal(t, "errors.As", status.CheckedBy[0].Check)
+
+	// And: The notifier's error has no sentinel to match
+	assert.Equal(t, "errorf", paths[2].Origin)
+	assert.Equal(t, "notify %s: no customer to address", paths[2].Message)
+	assert.Equal(t, 36, paths[2].Return)
+	assert.Empty(t, paths[2].Sentinel)
+}
+
+// TestErrorPathsCallees tests that calls outside the extract are reported, not followed
+func TestErrorPathsCallees(t *testing.T) {
+	// Given: The security example's store, calling database/sql
+	ws := loadExample(t, "ex7")
+
+	// When: We extract Find
+	paths := extractAt(t, ws, types.Options{Depth: 1}, 16, 0, "internal", "store", "store.go").ErrorPaths
+
+	// Then: Each error comes from a database call
+	var values []string
+	for _, path := range paths {
+		assert.Equal(t, "callee", path.Origin)
+		values = append(values, path.Value)
+	}
+	assert.Equal(t, []string{"s.db.Query", "rows.Scan", "rows.Err"}, values)
+}
+
+// TestErrorPathsNone tests targets that return no error
+func TestErrorPathsNone(t *testing.T) {
+	// Given: A constructor, and a method returning only nil
+	ws := loadExample(t, "ex9")
+
+	// When/Then: Neither has error paths
+	assert.Empty(t, extractAt(t, ws, types.Options{Depth: 1}, 17, 0, "internal", "app", "service.go").ErrorPaths)
+	assert.Empty(t, extractAt(t, ws, types.Options{Depth: 1}, 29, 0, "internal", "adapters", "memory", "repo.go").ErrorPaths)
+}
+
+// TestErrorPathsNamedResult tests bare returns of a named error wrapped by a deferred function
+func TestErrorPathsNamedResult(t *testing.T) {
+	// Given: The refund use case, returning its named error bare and wrapping it when deferred
+	ws := loadExample(t, "ex9")
+
+	// When: We extract it with its direct dependencies
+	paths := extractAt(t, ws, types.Options{Depth: 1}, 10, 0, "internal", "app", "refund.go").ErrorPaths
+
+	// Then: Both errors are found at the bare return, wrapped by the deferred function
+	require.Len(t, paths, 2)
+	notFound := paths[0]
+	assert.Equal(t, "sentinel", notFound.Origin)
+	assert.Equal(t, "example.com/ex9/internal/domain.ErrNotFound", notFound.Sentinel)
+	assert.Equal(t, 21, notFound.Return)
+	assert.Equal(t, []string{"refund %s: %w", "get order %s: %w"}, notFound.Wraps)
+	assert.Equal(t, []string{"example.com/ex9/internal/adapters/memory.Repository.Get"}, notFound.Via)
+
+	status := paths[1]
+	assert.Equal(t, "type", status.Origin)
+	assert.Equal(t, "example.com/ex9/internal/domain.StatusError", status.Sentinel)
+	assert.Equal(t, 21, status.Return)
+	assert.Equal(t, []string{"refund %s: %w"}, status.Wraps)
+}
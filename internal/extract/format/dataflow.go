@@ -0,0 +1,76 @@
+package format
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+)
+
+// DataFlowMarkdown renders a data-flow slice like an extract: the steps of
+// each direction grouped by function, then the code of those functions
+func DataFlowMarkdown(flow *types.DataFlow, root string) string {
+	var b strings.Builder
+	v := flow.Variable
+	b.WriteString(fmt.Sprintf("# Data Flow: `%s` (%s)\n\n", v.Name, flow.Direction))
+
+	b.WriteString(fmt.Sprintf("%s `%s %s`", v.Kind, v.Name, v.Type))
+	if v.Function != "" {
+		b.WriteString(fmt.Sprintf(" of `%s`", path.Base(v.Function)))
+	}
+	b.WriteString(fmt.Sprintf(" — %s, depth %d\n\n", relativePos(root, v.File, v.Line), flow.Depth))
+
+	for _, direction := range []string{"backward", "forward"} {
+		if direction != flow.Direction && flow.Direction != "both" {
+			continue
+		}
+		var steps []types.FlowStep
+		for _, step := range flow.Steps {
+			if step.Direction == direction {
+				steps = append(steps, step)
+			}
+		}
+
+		b.WriteString(fmt.Sprintf("## %s\n\n", strings.ToUpper(direction[:1])+direction[1:]))
+		if len(steps) == 0 {
+			b.WriteString("No steps found.\n\n")
+			continue
+		}
+
+		// Group steps by function, in order of first step
+		var functions []string
+		byFunction := make(map[string][]types.FlowStep)
+		for _, step := range steps {
+			if _, ok := byFunction[step.Function]; !ok {
+				functions = append(functions, step.Function)
+			}
+			byFunction[step.Function] = append(byFunction[step.Function], step)
+		}
+		for _, fn := range functions {
+			b.WriteString(fmt.Sprintf("**%s**\n\n", path.Base(fn)))
+			for _, step := range byFunction[fn] {
+				b.WriteString(fmt.Sprintf("- `%s` %s `%s`", relativePos(root, step.File, step.Line), step.Kind, step.Value))
+				if step.Code != "" {
+					b.WriteString(fmt.Sprintf(": `%s`", step.Code))
+				}
+				b.WriteString("\n")
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	if len(flow.Functions) > 0 {
+		b.WriteString("---\n\n## Functions\n\n")
+		for _, fn := range flow.Functions {
+			b.WriteString(fmt.Sprintf("### %s\n\n", path.Base(fn.ID())))
+			b.WriteString("```go\n")
+			b.WriteString(fn.Code)
+			if !strings.HasSuffix(fn.Code, "\n") {
+				b.WriteString("\n")
+			}
+			b.WriteString("```\n\n")
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,39 @@
+package format
+
+import (
+	"testing"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDataFlowMarkdown tests grouping slice steps by function and listing their code
+func TestDataFlowMarkdown(t *testing.T) {
+	// Given: A forward slice of a handler parameter into a use case
+	flow := &types.DataFlow{
+		Variable: types.FlowVariable{
+			Name: "r", Type: "*net/http.Request", Kind: "param",
+			Function: "example.com/app/rest.Handler.ServeHTTP", File: "/src/app/rest/handler.go", Line: 20,
+		},
+		Direction: "forward",
+		Depth:     2,
+		Steps: []types.FlowStep{
+			{Direction: "forward", Kind: "assign", Value: "query", Function: "example.com/app/rest.Handler.ServeHTTP", File: "/src/app/rest/handler.go", Line: 21, Code: "query := r.URL.Query()"},
+			{Direction: "forward", Kind: "field-store", Value: "u.Name", Function: "example.com/app/app.UserService.Rename", File: "/src/app/app/service.go", Line: 23, Code: "u.Name = name", Depth: 1},
+		},
+		Functions: []types.Symbol{
+			{Name: "Rename", Receiver: "*UserService", Package: "example.com/app/app", Code: "func (s *UserService) Rename(id, name string) error {}"},
+		},
+	}
+
+	// When: We render it
+	md := DataFlowMarkdown(flow, "/src/app")
+
+	// Then: Steps are grouped under their functions, followed by the code
+	assert.Contains(t, md, "# Data Flow: `r` (forward)\n\n")
+	assert.Contains(t, md, "param `r *net/http.Request` of `rest.Handler.ServeHTTP` — rest/handler.go:20, depth 2\n")
+	assert.Contains(t, md, "## Forward\n\n**rest.Handler.ServeHTTP**\n\n- `rest/handler.go:21` assign `query`: `query := r.URL.Query()`\n")
+	assert.Contains(t, md, "**app.UserService.Rename**\n\n- `app/service.go:23` field-store `u.Name`: `u.Name = name`\n")
+	assert.Contains(t, md, "```go\nfunc (s *UserService) Rename(id, name string) error {}\n```\n")
+	assert.NotContains(t, md, "## Backward")
+}
@@ -0,0 +1,61 @@
+package format
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+)
+
+// DIIssuesText renders DI container issues compiler style ("file:line: ..."),
+// at the provider at fault, with the dependency chain indented below
+func DIIssuesText(issues []types.DIIssue, root string) string {
+	var b strings.Builder
+
+	for _, issue := range issues {
+		container := issue.Framework
+		if issue.Container != "" {
+			container += " (" + issue.Container + ")"
+		}
+		at := issue.Providers[0]
+
+		var message string
+		switch issue.Kind {
+		case "missing":
+			message = fmt.Sprintf("nothing provides %s, required by %s", diValueString(issue.Value), at.ID())
+		case "duplicate":
+			at = issue.Providers[1]
+			var ids []string
+			for _, provider := range issue.Providers {
+				ids = append(ids, provider.ID())
+			}
+			message = fmt.Sprintf("%s is provided more than once: %s", diValueString(issue.Value), strings.Join(ids, ", "))
+		case "unused":
+			message = fmt.Sprintf("nothing requires %s from %s", diValueString(issue.Value), at.ID())
+		case "cycle":
+			var ids []string
+			for _, provider := range issue.Providers {
+				ids = append(ids, provider.ID())
+			}
+			message = fmt.Sprintf("dependency cycle: %s → %s", strings.Join(ids, " → "), ids[0])
+		default:
+			message = issue.Kind
+		}
+
+		b.WriteString(fmt.Sprintf("%s: %s: %s\n", relativePos(root, at.File, at.Line), container, message))
+		for _, step := range issue.Chain {
+			b.WriteString(fmt.Sprintf("    %s needs %s (%s)\n",
+				step.Provider.ID(), diValueString(step.Requires), relativePos(root, step.Provider.File, step.Provider.Line)))
+		}
+	}
+
+	switch len(issues) {
+	case 0:
+		b.WriteString("No DI issues.\n")
+	case 1:
+		b.WriteString("1 DI issue\n")
+	default:
+		b.WriteString(fmt.Sprintf("%d DI issues\n", len(issues)))
+	}
+	return b.String()
+}
@@ -0,0 +1,47 @@
+package format
+
+import (
+	"testing"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDIIssuesText tests compiler-style DI issue output with dependency chains
+func TestDIIssuesText(t *testing.T) {
+	// Given: A missing logger reached from an invoke, and a duplicated config
+	invoke := types.Symbol{Name: "main.func1", Package: "example.com/app/cmd", File: "/src/app/cmd/main.go", Line: 12}
+	server := types.Symbol{Name: "NewServer", Package: "example.com/app/server", File: "/src/app/server/server.go", Line: 8}
+	load := types.Symbol{Name: "LoadConfig", Package: "example.com/app/config", File: "/src/app/config/load.go", Line: 3}
+	defaults := types.Symbol{Name: "DefaultConfig", Package: "example.com/app/config", File: "/src/app/config/default.go", Line: 5}
+	issues := []types.DIIssue{
+		{
+			Kind:      "missing",
+			Framework: "fx",
+			Value:     types.DIValue{Type: "*example.com/app/log.Logger"},
+			Providers: []types.Symbol{server},
+			Chain: []types.DIStep{
+				{Provider: invoke, Requires: types.DIValue{Type: "*example.com/app/server.Server"}},
+				{Provider: server, Requires: types.DIValue{Type: "*example.com/app/log.Logger"}},
+			},
+		},
+		{
+			Kind:      "duplicate",
+			Framework: "wire",
+			Container: "main.InitializeServer",
+			Value:     types.DIValue{Type: "example.com/app/config.Config"},
+			Providers: []types.Symbol{load, defaults},
+		},
+	}
+
+	// When: We render them
+	result := DIIssuesText(issues, "/src/app")
+
+	// Then: Each issue is reported at the provider at fault, followed by its chain
+	assert.Contains(t, result, "server/server.go:8: fx: nothing provides `*example.com/app/log.Logger`, required by example.com/app/server.NewServer\n")
+	assert.Contains(t, result, "    example.com/app/cmd.main.func1 needs `*example.com/app/server.Server` (cmd/main.go:12)\n")
+	assert.Contains(t, result, "config/default.go:5: wire (main.InitializeServer): `example.com/app/config.Config` is provided more than once: example.com/app/config.LoadConfig, example.com/app/config.DefaultConfig\n")
+	assert.Contains(t, result, "2 DI issues\n")
+
+	assert.Equal(t, "No DI issues.\n", DIIssuesText(nil, ""))
+}
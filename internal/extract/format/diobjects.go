@@ -0,0 +1,152 @@
+package format
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+)
+
+// importPath matches the import path qualifying a type name
+var importPath = regexp.MustCompile(`(?:[\w.-]+/)+`)
+
+// DIObjectGraphsMermaid renders object graphs as a Mermaid flowchart, one
+// subgraph per root, each object labeled with its provider, main product and
+// scope, and each edge with the value passed and its implementation
+func DIObjectGraphsMermaid(graphs []types.DIObjectGraph) string {
+	var b strings.Builder
+	b.WriteString("graph TD\n")
+	b.WriteString("  classDef missing stroke:#c00,stroke-dasharray:4\n")
+	b.WriteString("  classDef argument stroke-dasharray:4\n")
+
+	for g, graph := range graphs {
+		ids := diNodeIDs(g, graph)
+		b.WriteString(fmt.Sprintf("  subgraph g%d[%s]\n", g, mermaidLabel(diGraphTitle(graph))))
+		for _, object := range graph.Objects {
+			b.WriteString(fmt.Sprintf("    %s[%s]\n", ids[object.ID], mermaidLabel(diObjectLabel(object)...)))
+		}
+		b.WriteString("  end\n")
+		for _, edge := range graph.Edges {
+			b.WriteString(fmt.Sprintf("  %s -->|%s| %s\n", ids[edge.From], mermaidLabel(diEdgeLabel(edge)), ids[edge.To]))
+		}
+		for _, object := range graph.Objects {
+			if object.Kind == "missing" || object.Kind == "argument" {
+				b.WriteString(fmt.Sprintf("  class %s %s\n", ids[object.ID], object.Kind))
+			}
+		}
+	}
+	return b.String()
+}
+
+// DIObjectGraphsDOT renders object graphs as a Graphviz digraph, one cluster
+// per root
+func DIObjectGraphsDOT(graphs []types.DIObjectGraph) string {
+	var b strings.Builder
+	b.WriteString("digraph di {\n")
+	b.WriteString("  rankdir=TB;\n")
+	b.WriteString("  node [shape=box];\n")
+
+	for g, graph := range graphs {
+		ids := diNodeIDs(g, graph)
+		b.WriteString(fmt.Sprintf("  subgraph cluster_%d {\n", g))
+		b.WriteString(fmt.Sprintf("    label=%s;\n", dotString(diGraphTitle(graph))))
+		for _, object := range graph.Objects {
+			attrs := "label=" + dotString(strings.Join(diObjectLabel(object), "\n"))
+			switch object.Kind {
+			case "missing":
+				attrs += ", color=red, style=dashed"
+			case "argument":
+				attrs += ", style=dashed"
+			}
+			b.WriteString(fmt.Sprintf("    %s [%s];\n", ids[object.ID], attrs))
+		}
+		b.WriteString("  }\n")
+		for _, edge := range graph.Edges {
+			b.WriteString(fmt.Sprintf("  %s -> %s [label=%s];\n", ids[edge.From], ids[edge.To], dotString(diEdgeLabel(edge))))
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// diNodeIDs assigns each object of the g-th graph an identifier valid in
+// Mermaid and DOT, "g0n3"
+func diNodeIDs(g int, graph types.DIObjectGraph) map[string]string {
+	ids := make(map[string]string, len(graph.Objects))
+	for i, object := range graph.Objects {
+		ids[object.ID] = fmt.Sprintf("g%dn%d", g, i)
+	}
+	return ids
+}
+
+// diGraphTitle names a graph after its root and container,
+// "main.InitializeServer (wire)"
+func diGraphTitle(graph types.DIObjectGraph) string {
+	if graph.Container != "" {
+		return fmt.Sprintf("%s (%s)", graph.Container, graph.Framework)
+	}
+	return fmt.Sprintf("%s (%s)", graph.Root.Name, graph.Framework)
+}
+
+// diObjectLabel returns the lines labeling an object: provider, main
+// product and scope, or kind when it is not a provider
+func diObjectLabel(object types.DIObject) []string {
+	if object.Kind == "missing" {
+		return []string{"missing", shortDIValue(object.Provides[0])}
+	}
+
+	lines := []string{object.Provider.Name}
+	if len(object.Provides) > 0 {
+		lines = append(lines, shortDIValue(object.Provides[0]))
+	}
+	switch object.Kind {
+	case "provide", "struct", "fields", "bind", "supply":
+		if object.Scope != "" {
+			lines = append(lines, object.Scope)
+		}
+	default:
+		lines = append(lines, object.Kind)
+	}
+	return lines
+}
+
+// diEdgeLabel labels a parameter with its value and, for interfaces, the
+// implementation passed, "users.Repository = users.Store"
+func diEdgeLabel(edge types.DIObjectEdge) string {
+	label := shortDIValue(edge.Value)
+	if edge.Implementation != nil {
+		label += " = " + shortType(edge.Implementation.ID())
+	}
+	return label
+}
+
+// shortDIValue formats a value with its package names only and its tags
+func shortDIValue(value types.DIValue) string {
+	s := shortType(value.Type)
+	if value.Name != "" {
+		s += fmt.Sprintf(" name:%q", value.Name)
+	}
+	if value.Group != "" {
+		s += fmt.Sprintf(" group:%q", value.Group)
+	}
+	return s
+}
+
+// shortType drops import paths from a type, keeping package names:
+// "*example.com/app/users.Service" becomes "*users.Service"
+func shortType(typ string) string {
+	return importPath.ReplaceAllString(typ, "")
+}
+
+// mermaidLabel quotes label lines for Mermaid
+func mermaidLabel(lines ...string) string {
+	return `"` + strings.ReplaceAll(strings.Join(lines, "<br/>"), `"`, "#quot;") + `"`
+}
+
+// dotString quotes a DOT string
+func dotString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + strings.ReplaceAll(s, "\n", `\n`) + `"`
+}
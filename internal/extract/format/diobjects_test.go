@@ -0,0 +1,66 @@
+package format
+
+import (
+	"testing"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// diObjectGraph returns an Fx graph: an invoke needing a server, whose
+// repository is a SQL store, and a logger nothing provides
+func diObjectGraph() []types.DIObjectGraph {
+	return []types.DIObjectGraph{{
+		Root:      types.Symbol{Name: "main.func1", Package: "example.com/app/cmd"},
+		Framework: "fx",
+		Objects: []types.DIObject{
+			{ID: "example.com/app/cmd.main.func1", Provider: types.Symbol{Name: "main.func1"}, Kind: "invoke", Scope: "singleton"},
+			{ID: "example.com/app/server.NewServer", Provider: types.Symbol{Name: "NewServer"}, Kind: "provide", Scope: "singleton",
+				Provides: []types.DIValue{{Type: "*example.com/app/server.Server"}}, Depth: 1},
+			{ID: "example.com/app/store.NewSQL", Provider: types.Symbol{Name: "NewSQL"}, Kind: "provide", Scope: "singleton",
+				Provides: []types.DIValue{{Type: "example.com/app/store.Repository", Name: "rw"}}, Depth: 2},
+			{ID: "missing:*example.com/app/log.Logger", Kind: "missing",
+				Provides: []types.DIValue{{Type: "*example.com/app/log.Logger"}}, Depth: 2},
+		},
+		Edges: []types.DIObjectEdge{
+			{From: "example.com/app/cmd.main.func1", To: "example.com/app/server.NewServer", Value: types.DIValue{Type: "*example.com/app/server.Server"}},
+			{From: "example.com/app/server.NewServer", To: "example.com/app/store.NewSQL", Value: types.DIValue{Type: "example.com/app/store.Repository", Name: "rw"},
+				Implementation: &types.Symbol{Name: "SQLStore", Package: "example.com/app/store"}},
+			{From: "example.com/app/server.NewServer", To: "missing:*example.com/app/log.Logger", Value: types.DIValue{Type: "*example.com/app/log.Logger"}},
+		},
+	}}
+}
+
+// TestDIObjectGraphsMermaid tests rendering object graphs as a Mermaid flowchart
+func TestDIObjectGraphsMermaid(t *testing.T) {
+	// Given: An Fx object graph
+	graphs := diObjectGraph()
+
+	// When: We render it
+	result := DIObjectGraphsMermaid(graphs)
+
+	// Then: Objects carry provider, product and scope; edges the value and implementation
+	assert.Contains(t, result, "graph TD\n")
+	assert.Contains(t, result, "  subgraph g0[\"main.func1 (fx)\"]\n")
+	assert.Contains(t, result, "    g0n1[\"NewServer<br/>*server.Server<br/>singleton\"]\n")
+	assert.Contains(t, result, "    g0n0[\"main.func1<br/>invoke\"]\n")
+	assert.Contains(t, result, "  g0n1 -->|\"store.Repository name:#quot;rw#quot; = store.SQLStore\"| g0n2\n")
+	assert.Contains(t, result, "    g0n3[\"missing<br/>*log.Logger\"]\n")
+	assert.Contains(t, result, "  class g0n3 missing\n")
+}
+
+// TestDIObjectGraphsDOT tests rendering object graphs as a Graphviz digraph
+func TestDIObjectGraphsDOT(t *testing.T) {
+	// Given: An Fx object graph
+	graphs := diObjectGraph()
+
+	// When: We render it
+	result := DIObjectGraphsDOT(graphs)
+
+	// Then: Each root is a cluster, with escaped labels
+	assert.Contains(t, result, "digraph di {\n")
+	assert.Contains(t, result, "  subgraph cluster_0 {\n    label=\"main.func1 (fx)\";\n")
+	assert.Contains(t, result, "    g0n1 [label=\"NewServer\\n*server.Server\\nsingleton\"];\n")
+	assert.Contains(t, result, "  g0n1 -> g0n2 [label=\"store.Repository name:\\\"rw\\\" = store.SQLStore\"];\n")
+	assert.Contains(t, result, "    g0n3 [label=\"missing\\n*log.Logger\", color=red, style=dashed];\n")
+}
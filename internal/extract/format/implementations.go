@@ -0,0 +1,53 @@
+package format
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+)
+
+// ImplementationsText renders an implementation index query compiler style.
+// For an interface it lists the implementing types, starred when only the
+// pointer type implements it; for a concrete type, the interfaces it satisfies.
+func ImplementationsText(sym types.Symbol, impls []types.Implementation, root string) string {
+	var b strings.Builder
+
+	isInterface := sym.Kind == "interface"
+	if isInterface {
+		b.WriteString(fmt.Sprintf("%s is implemented by:\n", sym.ID()))
+	} else {
+		b.WriteString(fmt.Sprintf("%s implements:\n", sym.ID()))
+	}
+
+	for _, impl := range impls {
+		if isInterface {
+			star := ""
+			if impl.Pointer {
+				star = "*"
+			}
+			b.WriteString(fmt.Sprintf("%s: %s%s\n", relativePos(root, impl.Type.File, impl.Type.Line), star, impl.Type.ID()))
+			continue
+		}
+
+		b.WriteString(fmt.Sprintf("%s: %s", relativePos(root, impl.Interface.File, impl.Interface.Line), impl.Interface.ID()))
+		if impl.Pointer {
+			b.WriteString(fmt.Sprintf(" (via *%s)", impl.Type.Name))
+		}
+		b.WriteString("\n")
+	}
+
+	noun := "implementation"
+	if !isInterface {
+		noun = "interface"
+	}
+	switch len(impls) {
+	case 0:
+		b.WriteString(fmt.Sprintf("No %ss.\n", noun))
+	case 1:
+		b.WriteString(fmt.Sprintf("1 %s\n", noun))
+	default:
+		b.WriteString(fmt.Sprintf("%d %ss\n", len(impls), noun))
+	}
+	return b.String()
+}
@@ -0,0 +1,35 @@
+package format
+
+import (
+	"testing"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestImplementationsText tests both directions of an implementation query
+func TestImplementationsText(t *testing.T) {
+	// Given: An interface and two implementations, one through pointer receivers
+	reader := types.Symbol{Name: "Reader", Kind: "interface", Package: "example.com/kv/store", File: "/src/kv/store/store.go", Line: 4}
+	mapType := types.Symbol{Name: "Map", Kind: "struct", Package: "example.com/kv/memory", File: "/src/kv/memory/memory.go", Line: 6}
+	static := types.Symbol{Name: "Static", Kind: "type", Package: "example.com/kv/memory", File: "/src/kv/memory/memory.go", Line: 27}
+	impls := []types.Implementation{
+		{Interface: reader, Type: mapType, Pointer: true},
+		{Interface: reader, Type: static},
+	}
+
+	// When: We render the interface's implementations and a type's interfaces
+	byInterface := ImplementationsText(reader, impls, "/src/kv")
+	byType := ImplementationsText(mapType, impls[:1], "/src/kv")
+
+	// Then: Each line points at the other side of the relation
+	assert.Equal(t, "example.com/kv/store.Reader is implemented by:\n"+
+		"memory/memory.go:6: *example.com/kv/memory.Map\n"+
+		"memory/memory.go:27: example.com/kv/memory.Static\n"+
+		"2 implementations\n", byInterface)
+	assert.Equal(t, "example.com/kv/memory.Map implements:\n"+
+		"store/store.go:4: example.com/kv/store.Reader (via *Map)\n"+
+		"1 interface\n", byType)
+
+	assert.Contains(t, ImplementationsText(static, nil, ""), "No interfaces.\n")
+}
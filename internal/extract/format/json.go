@@ -8,6 +8,16 @@ import (
 
 // ToJSON converts an Extract to JSON format for the web visualizer
 func ToJSON(ext types.Extract, opts types.Options) (string, error) {
+	return renderJSON(ext, nil, opts)
+}
+
+// ToJSONWithMetadata converts an Extract to visualizer JSON, including extraction metadata
+func ToJSONWithMetadata(ext types.Extract, meta types.Metadata, opts types.Options) (string, error) {
+	return renderJSON(ext, &meta, opts)
+}
+
+// renderJSON builds the visualizer JSON; meta is optional
+func renderJSON(ext types.Extract, meta *types.Metadata, opts types.Options) (string, error) {
 	// Create a visualization-friendly structure
 	viz := VisualizationData{
 		Target:      convertSymbolToNode(ext.Target, 0, true),
@@ -16,6 +26,7 @@ func ToJSON(ext types.Extract, opts types.Options) (string, error) {
 		External:    ext.External,
 		Options:     opts,
 		TotalLayers: calculateMaxDepth(ext.References),
+		Metadata:    meta,
 	}
 
 	// Add target node
@@ -31,18 +42,22 @@ func ToJSON(ext types.Extract, opts types.Options) (string, error) {
 			node := convertSymbolToNode(ref.Symbol, ref.Depth, false)
 			node.External = ref.External
 			node.Stub = ref.Stub
+			node.Signature = ref.Signature
+			node.Render = ref.Render
+			applyNodeRender(&node, ref)
 			viz.Nodes = append(viz.Nodes, node)
 			nodeMap[nodeID] = true
 		}
 
-		// Add edge from referenced-by to this symbol
-		if ref.ReferencedBy != "" {
+		// Add edge from referenced-by to this symbol, keyed by node ID so
+		// same-named symbols in different packages stay distinct
+		if from := edgeSource(ref); from != "" {
 			edge := Edge{
-				From:   ref.ReferencedBy,
-				To:     ref.Symbol.Name,
-				Type:   ref.Reason,
-				Depth:  ref.Depth,
-				Label:  ref.Reason,
+				From:  from,
+				To:    nodeID,
+				Type:  ref.Reason,
+				Depth: ref.Depth,
+				Label: ref.Reason,
 			}
 			viz.Edges = append(viz.Edges, edge)
 		}
@@ -83,6 +98,15 @@ func ToJSON(ext types.Extract, opts types.Options) (string, error) {
 		}
 	}
 
+	// Add interface gaps
+	for _, gap := range ext.InterfaceGaps {
+		viz.InterfaceGaps = append(viz.InterfaceGaps, InterfaceGapData{
+			Interface: convertSymbolToNode(gap.Interface, 0, false),
+			Type:      convertSymbolToNode(gap.Type, 0, false),
+			Missing:   gap.Missing,
+		})
+	}
+
 	// Add DI bindings
 	if len(ext.DIBindings) > 0 {
 		for _, binding := range ext.DIBindings {
@@ -92,6 +116,15 @@ func ToJSON(ext types.Extract, opts types.Options) (string, error) {
 				Dependencies: []Node{},
 				Framework:    binding.Framework,
 				Scope:        binding.Scope,
+				Init:         binding.Init,
+				Kind:         binding.Kind,
+				Module:       binding.Module,
+				Provides:     binding.Provides,
+				Requires:     binding.Requires,
+				Hooks:        binding.Hooks,
+				Injectors:    binding.Injectors,
+				Apps:         binding.Apps,
+				Options:      binding.Options,
 			}
 
 			for _, dep := range binding.Dependencies {
@@ -105,6 +138,12 @@ func ToJSON(ext types.Extract, opts types.Options) (string, error) {
 
 	// Add detected DI framework
 	viz.DetectedDIFramework = ext.DetectedDIFramework
+	viz.DIFrameworks = ext.DIFrameworks
+	viz.DIPackages = ext.DIPackages
+	viz.Budget = ext.Budget
+	viz.Taints = ext.Taints
+	viz.ErrorPaths = ext.ErrorPaths
+	viz.Concurrency = ext.Concurrency
 
 	// Marshal to JSON with indentation
 	data, err := json.MarshalIndent(viz, "", "  ")
@@ -117,37 +156,49 @@ func ToJSON(ext types.Extract, opts types.Options) (string, error) {
 
 // VisualizationData is the JSON structure for the web visualizer
 type VisualizationData struct {
-	Target              Node                    `json:"target"`
-	Nodes               []Node                  `json:"nodes"`
-	Edges               []Edge                  `json:"edges"`
-	External            []string                `json:"external,omitempty"`
-	Metrics             *MetricsData            `json:"metrics,omitempty"`
-	Options             types.Options           `json:"options"`
-	TotalLayers         int                     `json:"totalLayers"`
-	InterfaceMappings   []InterfaceMappingData  `json:"interfaceMappings,omitempty"`
-	DIBindings          []DIBindingData         `json:"diBindings,omitempty"`
-	DetectedDIFramework string                  `json:"detectedDIFramework,omitempty"`
+	Target              Node                   `json:"target"`
+	Nodes               []Node                 `json:"nodes"`
+	Edges               []Edge                 `json:"edges"`
+	External            []string               `json:"external,omitempty"`
+	Metrics             *MetricsData           `json:"metrics,omitempty"`
+	Options             types.Options          `json:"options"`
+	TotalLayers         int                    `json:"totalLayers"`
+	InterfaceMappings   []InterfaceMappingData `json:"interfaceMappings,omitempty"`
+	InterfaceGaps       []InterfaceGapData     `json:"interfaceGaps,omitempty"`
+	DIBindings          []DIBindingData        `json:"diBindings,omitempty"`
+	DetectedDIFramework string                 `json:"detectedDIFramework,omitempty"`
+	DIFrameworks        []string               `json:"diFrameworks,omitempty"`
+	DIPackages          []types.DIPackage      `json:"diPackages,omitempty"`
+	Metadata            *types.Metadata        `json:"metadata,omitempty"`
+	Budget              *types.BudgetReport    `json:"budget,omitempty"`
+	Taints              []types.TaintPath      `json:"taints,omitempty"`
+	ErrorPaths          []types.ErrorPath      `json:"errorPaths,omitempty"`
+	Concurrency         *types.Concurrency     `json:"concurrency,omitempty"`
 }
 
 // Node represents a symbol node in the visualization
 type Node struct {
-	ID       string `json:"id"`
-	Name     string `json:"name"`
-	Kind     string `json:"kind"`
-	Package  string `json:"package"`
-	File     string `json:"file"`
-	Line     int    `json:"line"`
-	EndLine  int    `json:"endLine"`
-	Code     string `json:"code"`
-	Doc      string `json:"doc,omitempty"`
-	Exported bool   `json:"exported"`
-	Depth    int    `json:"depth"`
-	IsTarget bool   `json:"isTarget"`
-	External bool   `json:"external"`
-	Stub     bool   `json:"stub"`
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	Kind      string   `json:"kind"`
+	Package   string   `json:"package"`
+	File      string   `json:"file"`
+	Line      int      `json:"line"`
+	EndLine   int      `json:"endLine"`
+	Code      string   `json:"code"`
+	Doc       string   `json:"doc,omitempty"`
+	Exported  bool     `json:"exported"`
+	Depth     int      `json:"depth"`
+	IsTarget  bool     `json:"isTarget"`
+	External  bool     `json:"external"`
+	Stub      bool     `json:"stub"`
+	Signature string   `json:"signature,omitempty"`
+	Render    string   `json:"render,omitempty"`
+	Role      string   `json:"role,omitempty"`    // Hexagonal role, see types.Symbol.Role
+	Effects   []string `json:"effects,omitempty"` // Side effects, see types.Symbol.Effects
 }
 
-// Edge represents a dependency relationship
+// Edge represents a dependency relationship between two node IDs
 type Edge struct {
 	From  string `json:"from"`
 	To    string `json:"to"`
@@ -168,19 +219,35 @@ type MetricsData struct {
 
 // InterfaceMappingData holds interface-to-implementation mapping
 type InterfaceMappingData struct {
-	Interface       Node    `json:"interface"`
-	Implementations []Node  `json:"implementations"`
-	Constructor     *Node   `json:"constructor,omitempty"`
-	DIFramework     string  `json:"diFramework,omitempty"`
+	Interface       Node   `json:"interface"`
+	Implementations []Node `json:"implementations"`
+	Constructor     *Node  `json:"constructor,omitempty"`
+	DIFramework     string `json:"diFramework,omitempty"`
+}
+
+// InterfaceGapData holds a type that nearly implements an interface
+type InterfaceGapData struct {
+	Interface Node              `json:"interface"`
+	Type      Node              `json:"type"`
+	Missing   []types.MethodGap `json:"missing"`
 }
 
 // DIBindingData holds dependency injection binding information
 type DIBindingData struct {
-	Provider     Node     `json:"provider"`
-	Product      Node     `json:"product"`
-	Dependencies []Node   `json:"dependencies"`
-	Framework    string   `json:"framework"`
-	Scope        string   `json:"scope"`
+	Provider     Node                      `json:"provider"`
+	Product      Node                      `json:"product"`
+	Dependencies []Node                    `json:"dependencies"`
+	Framework    string                    `json:"framework"`
+	Scope        string                    `json:"scope"`
+	Init         string                    `json:"init,omitempty"`
+	Kind         string                    `json:"kind,omitempty"`
+	Module       string                    `json:"module,omitempty"`
+	Provides     []types.DIValue           `json:"provides,omitempty"`
+	Requires     []types.DIValue           `json:"requires,omitempty"`
+	Hooks        []string                  `json:"hooks,omitempty"`
+	Injectors    []string                  `json:"injectors,omitempty"`
+	Apps         []string                  `json:"apps,omitempty"`
+	Options      *types.ConstructorOptions `json:"options,omitempty"`
 }
 
 // convertSymbolToNode converts a Symbol to a visualization Node
@@ -198,15 +265,35 @@ func convertSymbolToNode(sym types.Symbol, depth int, isTarget bool) Node {
 		Exported: sym.Exported,
 		Depth:    depth,
 		IsTarget: isTarget,
+		Role:     sym.Role,
+		Effects:  sym.Effects,
+	}
+}
+
+// applyNodeRender drops the parts of a node its reference's rendering mode excludes
+func applyNodeRender(node *Node, ref types.Reference) {
+	switch ref.Render {
+	case "signature":
+		node.Code = ""
+	case "name":
+		node.Code = ""
+		node.Doc = ""
+		node.Signature = ""
 	}
 }
 
 // makeNodeID creates a unique ID for a symbol
 func makeNodeID(sym types.Symbol) string {
-	if sym.Package != "" {
-		return sym.Package + "." + sym.Name
+	return sym.ID()
+}
+
+// edgeSource returns the node ID of the symbol that made a reference.
+// References built without an ID (hand-written or older extracts) fall back to the name.
+func edgeSource(ref types.Reference) string {
+	if ref.ReferencedByID != "" {
+		return ref.ReferencedByID
 	}
-	return sym.Name
+	return ref.ReferencedBy
 }
 
 // calculateMaxDepth finds the maximum depth in references
@@ -170,6 +170,58 @@ func TestJSONEdges(t *testing.T) {
 	assert.Equal(t, "type-reference", viz.Edges[1].Type)
 }
 
+// TestJSONEdgesUseNodeIDs tests that same-named symbols in different packages stay distinct
+func TestJSONEdgesUseNodeIDs(t *testing.T) {
+	// Given: Two constructors named New in different packages
+	ext := types.Extract{
+		Target: types.Symbol{Name: "Run", Package: "example.com/app"},
+		References: []types.Reference{
+			{
+				Symbol:         types.Symbol{Name: "New", Package: "example.com/app/store"},
+				ReferencedBy:   "Run",
+				ReferencedByID: "example.com/app.Run",
+				Depth:          1,
+			},
+			{
+				Symbol:         types.Symbol{Name: "New", Package: "example.com/app/cache"},
+				ReferencedBy:   "Run",
+				ReferencedByID: "example.com/app.Run",
+				Depth:          1,
+			},
+			{
+				Symbol:         types.Symbol{Name: "Get", Package: "example.com/app/cache", Receiver: "*Cache"},
+				ReferencedBy:   "New",
+				ReferencedByID: "example.com/app/cache.New",
+				Depth:          2,
+			},
+		},
+	}
+
+	// When: We convert to JSON
+	result, err := ToJSON(ext, types.Options{})
+	require.NoError(t, err)
+
+	var viz VisualizationData
+	require.NoError(t, json.Unmarshal([]byte(result), &viz))
+
+	// Then: Every edge endpoint should be an existing node ID
+	ids := map[string]bool{viz.Target.ID: true}
+	for _, node := range viz.Nodes {
+		ids[node.ID] = true
+	}
+	require.Len(t, viz.Nodes, 3)
+	require.Len(t, viz.Edges, 3)
+	for _, edge := range viz.Edges {
+		assert.True(t, ids[edge.From], "unknown edge source %s", edge.From)
+		assert.True(t, ids[edge.To], "unknown edge target %s", edge.To)
+	}
+
+	assert.Equal(t, "example.com/app/store.New", viz.Edges[0].To)
+	assert.Equal(t, "example.com/app/cache.New", viz.Edges[1].To)
+	assert.Equal(t, "example.com/app/cache.New", viz.Edges[2].From)
+	assert.Equal(t, "example.com/app/cache.Cache.Get", viz.Edges[2].To)
+}
+
 // TestJSONExternalSymbols tests external symbol handling
 func TestJSONExternalSymbols(t *testing.T) {
 	// Given: Extract with external dependencies
@@ -199,3 +251,67 @@ func TestJSONExternalSymbols(t *testing.T) {
 	assert.True(t, viz.Nodes[0].Stub)
 	assert.Equal(t, 2, len(viz.External))
 }
+
+// TestJSONRenderModes tests that nodes carry only what their mode allows
+func TestJSONRenderModes(t *testing.T) {
+	// Given: A signature-mode and a name-mode reference
+	ext := types.Extract{
+		Target: types.Symbol{Name: "Run"},
+		References: []types.Reference{
+			{
+				Symbol:    types.Symbol{Name: "Sig", Doc: "contract", Code: "func Sig() {}"},
+				Signature: "func Sig()",
+				Depth:     2,
+				Render:    "signature",
+			},
+			{
+				Symbol:    types.Symbol{Name: "Named", Doc: "hidden", Code: "func Named() {}"},
+				Signature: "func Named()",
+				Depth:     3,
+				Render:    "name",
+			},
+		},
+	}
+
+	// When: We convert to JSON
+	result, err := ToJSON(ext, types.Options{})
+	require.NoError(t, err)
+
+	var viz VisualizationData
+	require.NoError(t, json.Unmarshal([]byte(result), &viz))
+
+	// Then: Code is dropped, and name-only nodes lose doc and signature too
+	require.Len(t, viz.Nodes, 2)
+	assert.Equal(t, "signature", viz.Nodes[0].Render)
+	assert.Empty(t, viz.Nodes[0].Code)
+	assert.Equal(t, "contract", viz.Nodes[0].Doc)
+	assert.Equal(t, "func Sig()", viz.Nodes[0].Signature)
+
+	assert.Equal(t, "name", viz.Nodes[1].Render)
+	assert.Empty(t, viz.Nodes[1].Code)
+	assert.Empty(t, viz.Nodes[1].Doc)
+	assert.Empty(t, viz.Nodes[1].Signature)
+}
+
+// TestJSONRoles tests that nodes carry hexagonal roles
+func TestJSONRoles(t *testing.T) {
+	// Given: A port and its adapter
+	ext := types.Extract{
+		Target: types.Symbol{Name: "UserRepository", Package: "example.com/domain", Kind: "interface", Role: "driven-port"},
+		References: []types.Reference{
+			{Symbol: types.Symbol{Name: "Repository", Package: "example.com/memory", Kind: "struct", Role: "secondary-adapter"}, Depth: 1},
+		},
+	}
+
+	// When: We convert to JSON
+	result, err := ToJSON(ext, types.Options{})
+	require.NoError(t, err)
+
+	var viz VisualizationData
+	require.NoError(t, json.Unmarshal([]byte(result), &viz))
+
+	// Then: The target and its reference have their roles
+	assert.Equal(t, "driven-port", viz.Target.Role)
+	require.Len(t, viz.Nodes, 1)
+	assert.Equal(t, "secondary-adapter", viz.Nodes[0].Role)
+}
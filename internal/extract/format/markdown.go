@@ -2,6 +2,7 @@ package format
 
 import (
 	"fmt"
+	"path"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -12,6 +13,17 @@ import (
 
 // ToMarkdown formats an Extract as markdown
 func ToMarkdown(ext types.Extract, opts types.Options) (string, error) {
+	return renderMarkdown(ext, nil, opts)
+}
+
+// ToMarkdownWithMetadata formats an Extract as markdown, including how and
+// from what source it was extracted
+func ToMarkdownWithMetadata(ext types.Extract, meta types.Metadata, opts types.Options) (string, error) {
+	return renderMarkdown(ext, &meta, opts)
+}
+
+// renderMarkdown formats an Extract as markdown; meta is optional
+func renderMarkdown(ext types.Extract, meta *types.Metadata, opts types.Options) (string, error) {
 	var b strings.Builder
 
 	// Header
@@ -30,7 +42,17 @@ func ToMarkdown(ext types.Extract, opts types.Options) (string, error) {
 	if ext.Target.Receiver != "" {
 		b.WriteString(fmt.Sprintf("**Receiver**: %s\n", ext.Target.Receiver))
 	}
-	b.WriteString(fmt.Sprintf("**Extracted**: %s\n", time.Now().Format("2006-01-02 15:04:05")))
+	if ext.Target.Role != "" {
+		b.WriteString(fmt.Sprintf("**Role**: %s\n", ext.Target.Role))
+	}
+	if len(ext.Target.Effects) > 0 {
+		b.WriteString(fmt.Sprintf("**Effects**: %s\n", effectBadges(ext.Target.Effects)))
+	}
+	extractedAt := time.Now()
+	if meta != nil && !meta.ExtractedAt.IsZero() {
+		extractedAt = meta.ExtractedAt
+	}
+	b.WriteString(fmt.Sprintf("**Extracted**: %s\n", extractedAt.Format("2006-01-02 15:04:05")))
 
 	b.WriteString("\n---\n\n")
 
@@ -123,6 +145,65 @@ func ToMarkdown(ext types.Extract, opts types.Options) (string, error) {
 		b.WriteString("\n")
 	}
 
+	// Interface gaps
+	if len(ext.InterfaceGaps) > 0 {
+		b.WriteString("---\n\n")
+		b.WriteString("## Interface Gaps\n\n")
+
+		for _, gap := range ext.InterfaceGaps {
+			b.WriteString(fmt.Sprintf("**%s** does not implement **%s**:\n\n", qualifiedName(gap.Type), qualifiedName(gap.Interface)))
+			for _, method := range gap.Missing {
+				if method.Reason == "wrong-signature" {
+					b.WriteString(fmt.Sprintf("- has `%s`, want `%s`\n", method.Found, method.Method))
+				} else {
+					b.WriteString(fmt.Sprintf("- missing `%s`\n", method.Method))
+				}
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	// Constructor options (if the target takes any)
+	if ext.Options != nil {
+		b.WriteString("---\n\n")
+		b.WriteString("## Constructor Options\n\n")
+		for _, line := range optionLines(*ext.Options) {
+			b.WriteString("- " + line + "\n")
+		}
+		b.WriteString("\n")
+	}
+
+	// Error paths (if the target returns errors)
+	if len(ext.ErrorPaths) > 0 {
+		b.WriteString("---\n\n")
+		b.WriteString("## Error Paths\n\n")
+		for _, errPath := range ext.ErrorPaths {
+			b.WriteString(formatErrorPath(errPath, ext.Target.File))
+		}
+		b.WriteString("\n")
+	}
+
+	// Concurrency (if the extract's functions do anything concurrent)
+	if ext.Concurrency != nil {
+		b.WriteString("---\n\n")
+		b.WriteString(formatConcurrency(*ext.Concurrency))
+	}
+
+	// Taint paths (security mode)
+	if len(ext.Taints) > 0 {
+		b.WriteString("---\n\n")
+		b.WriteString("## Taint Paths\n\n")
+		for _, taint := range ext.Taints {
+			b.WriteString(formatTaintPath(taint))
+		}
+	}
+
+	// Budget trimming (if any)
+	if ext.Budget != nil {
+		b.WriteString("---\n\n")
+		b.WriteString(formatBudget(*ext.Budget))
+	}
+
 	// Callers (if enabled)
 	if opts.ShowCallers && len(ext.Callers) > 0 {
 		b.WriteString("---\n\n")
@@ -158,6 +239,12 @@ func ToMarkdown(ext types.Extract, opts types.Options) (string, error) {
 		b.WriteString("\n```\n\n")
 	}
 
+	// Extraction metadata (if provided)
+	if meta != nil {
+		b.WriteString("---\n\n")
+		b.WriteString(formatMetadata(*meta))
+	}
+
 	// Footer
 	b.WriteString("---\n\n")
 	b.WriteString("*Generated by go-scope*\n")
@@ -165,10 +252,36 @@ func ToMarkdown(ext types.Extract, opts types.Options) (string, error) {
 	return b.String(), nil
 }
 
-// formatReference formats a single reference
+// effectBadges renders a function's side effects as code spans: `io` `logs`
+func effectBadges(effects []string) string {
+	badges := make([]string, len(effects))
+	for i, effect := range effects {
+		badges[i] = "`" + effect + "`"
+	}
+	return strings.Join(badges, " ")
+}
+
+// formatReference formats a single reference according to its rendering mode:
+// full code, signature and doc, or name only
 func formatReference(ref types.Reference, opts types.Options) string {
 	var b strings.Builder
 
+	// Name-only references are a single line
+	if ref.Render == "name" {
+		b.WriteString(fmt.Sprintf("- **%s**", ref.Symbol.Name))
+		if ref.Symbol.Kind != "" {
+			b.WriteString(fmt.Sprintf(" (%s)", ref.Symbol.Kind))
+		}
+		if ref.Symbol.Package != "" {
+			b.WriteString(fmt.Sprintf(" — %s", ref.Symbol.Package))
+		}
+		if ref.Symbol.File != "" {
+			b.WriteString(fmt.Sprintf(" (%s:%d)", filepath.Base(ref.Symbol.File), ref.Symbol.Line))
+		}
+		b.WriteString("\n\n")
+		return b.String()
+	}
+
 	// Symbol name as heading
 	b.WriteString(fmt.Sprintf("#### %s\n\n", ref.Symbol.Name))
 
@@ -188,17 +301,29 @@ func formatReference(ref types.Reference, opts types.Options) string {
 		b.WriteString("\n\n")
 	}
 
+	if ref.Symbol.Role != "" {
+		b.WriteString(fmt.Sprintf("**Role**: %s\n\n", ref.Symbol.Role))
+	}
+
+	if len(ref.Symbol.Effects) > 0 {
+		b.WriteString(fmt.Sprintf("**Effects**: %s\n\n", effectBadges(ref.Symbol.Effects)))
+	}
+
 	// Documentation
 	if ref.Symbol.Doc != "" {
 		b.WriteString(fmt.Sprintf("%s\n\n", strings.TrimSpace(ref.Symbol.Doc)))
 	}
 
 	// Code
-	if ref.External && ref.Stub {
-		// External stub - just show signature
+	if ref.Stub || ref.Render == "signature" {
+		// Stub or signature mode - just show signature
 		if ref.Signature != "" {
-			b.WriteString(fmt.Sprintf("```go\n// External: %s\n%s\n```\n\n", ref.Symbol.Package, ref.Signature))
-		} else {
+			b.WriteString("```go\n")
+			if ref.External {
+				b.WriteString(fmt.Sprintf("// External: %s\n", ref.Symbol.Package))
+			}
+			b.WriteString(fmt.Sprintf("%s\n```\n\n", ref.Signature))
+		} else if ref.External {
 			b.WriteString(fmt.Sprintf("*External symbol from `%s`*\n\n", ref.Symbol.Package))
 		}
 	} else if ref.Symbol.Code != "" {
@@ -219,6 +344,81 @@ func formatReference(ref types.Reference, opts types.Options) string {
 	return b.String()
 }
 
+// formatBudget summarises how the extract was trimmed to fit its budget
+func formatBudget(report types.BudgetReport) string {
+	var b strings.Builder
+
+	b.WriteString("## Budget\n\n")
+	b.WriteString(fmt.Sprintf("Trimmed to fit %d bytes (~%d tokens): %d reduced to signatures, %d omitted",
+		report.MaxBytes, report.MaxTokens, len(report.Stubbed), len(report.Omitted)))
+	if report.TargetTruncated {
+		b.WriteString(", target truncated")
+	}
+	b.WriteString(".\n\n")
+
+	if len(report.Omitted) > 0 {
+		b.WriteString("Omitted:\n")
+		for _, id := range report.Omitted {
+			b.WriteString(fmt.Sprintf("- `%s`\n", id))
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// formatMetadata formats the extraction metadata section
+func formatMetadata(meta types.Metadata) string {
+	var b strings.Builder
+
+	b.WriteString("## Extraction Metadata\n\n")
+	if meta.Module != "" {
+		module := meta.Module
+		if meta.ModuleVersion != "" {
+			module += "@" + meta.ModuleVersion
+		}
+		b.WriteString(fmt.Sprintf("- Module: `%s`\n", module))
+	}
+	if meta.VCSRevision != "" {
+		revision := meta.VCSRevision
+		if meta.VCSModified {
+			revision += " (modified)"
+		}
+		b.WriteString(fmt.Sprintf("- Revision: `%s`\n", revision))
+	}
+	if meta.GoVersion != "" {
+		b.WriteString(fmt.Sprintf("- Go: %s\n", meta.GoVersion))
+	}
+	if meta.ToolVersion != "" || meta.Toolchain != "" {
+		b.WriteString(fmt.Sprintf("- go-scope: %s", strings.TrimSpace(meta.ToolVersion)))
+		if meta.Toolchain != "" {
+			b.WriteString(fmt.Sprintf(" (%s)", meta.Toolchain))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(fmt.Sprintf("- Symbols: %d, Lines: %d\n", meta.TotalSymbols, meta.TotalLines))
+	if meta.Timings.Total > 0 {
+		t := meta.Timings
+		b.WriteString(fmt.Sprintf("- Timing: load %s, locate %s, collect %s, interfaces %s, DI %s, analysis %s (total %s)\n",
+			roundDuration(t.Load), roundDuration(t.Locate), roundDuration(t.Collect),
+			roundDuration(t.Interfaces), roundDuration(t.DI), roundDuration(t.Analysis), roundDuration(t.Total)))
+	}
+	for _, warning := range meta.Warnings {
+		b.WriteString(fmt.Sprintf("- Warning: %s\n", warning))
+	}
+	b.WriteString("\n")
+
+	return b.String()
+}
+
+// roundDuration rounds a duration for display
+func roundDuration(d time.Duration) time.Duration {
+	if d >= time.Second {
+		return d.Round(10 * time.Millisecond)
+	}
+	return d.Round(10 * time.Microsecond)
+}
+
 // formatFilePos formats file and line as a readable string
 func formatFilePos(file string, line int) string {
 	if file == "" {
@@ -227,6 +427,158 @@ func formatFilePos(file string, line int) string {
 	return fmt.Sprintf("%s:%d", filepath.Base(file), line)
 }
 
+// optionLines describes a constructor's options, defaults and call sites,
+// one line each
+func optionLines(options types.ConstructorOptions) []string {
+	var lines []string
+	if options.Style == "config" {
+		lines = append(lines, fmt.Sprintf("config `%s`", qualifiedName(options.Type)))
+	} else {
+		lines = append(lines, fmt.Sprintf("options `...%s`", qualifiedName(options.Type)))
+	}
+
+	for _, option := range options.Options {
+		switch {
+		case option.Type != "":
+			lines = append(lines, fmt.Sprintf("field `%s` `%s`", option.Name, option.Type))
+		case len(option.Fields) > 0:
+			lines = append(lines, fmt.Sprintf("option `%s` sets %s", option.Name, strings.Join(option.Fields, ", ")))
+		default:
+			lines = append(lines, fmt.Sprintf("option `%s`", option.Name))
+		}
+	}
+	for _, def := range options.Defaults {
+		lines = append(lines, fmt.Sprintf("default `%s` = `%s`", def.Field, def.Value))
+	}
+	for _, call := range options.CallSites {
+		line := fmt.Sprintf("called by %s (`%s`)", call.Function, formatFilePos(call.File, call.Line))
+		if len(call.Options) > 0 {
+			line += fmt.Sprintf(" with `%s`", strings.Join(call.Options, "`, `"))
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// formatErrorPath renders an error the target returns: its origin, the
+// return it leaves the target by, its wrapping and the callers matching it
+func formatErrorPath(errPath types.ErrorPath, targetFile string) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("- **%s** `%s`", errPath.Origin, errPath.Value))
+	if errPath.Message != "" && errPath.Origin == "sentinel" {
+		b.WriteString(fmt.Sprintf(" (%q)", errPath.Message))
+	}
+	b.WriteString(fmt.Sprintf(" — `%s` in `%s`\n", formatFilePos(errPath.File, errPath.Line), path.Base(errPath.Function)))
+
+	returned := fmt.Sprintf("  - returned at `%s`", formatFilePos(targetFile, errPath.Return))
+	if len(errPath.Via) > 0 {
+		via := make([]string, len(errPath.Via))
+		for i, fn := range errPath.Via {
+			via[i] = "`" + path.Base(fn) + "`"
+		}
+		returned += " via " + strings.Join(via, " → ")
+	}
+	b.WriteString(returned + "\n")
+
+	for _, wrap := range errPath.Wraps {
+		b.WriteString(fmt.Sprintf("  - wrapped by `%q`\n", wrap))
+	}
+	switch {
+	case errPath.Sentinel == "":
+		b.WriteString("  - opaque: no sentinel or type for callers to match\n")
+	case len(errPath.CheckedBy) == 0:
+		b.WriteString("  - not checked by callers\n")
+	}
+	for _, check := range errPath.CheckedBy {
+		b.WriteString(fmt.Sprintf("  - checked by `%s` with %s (`%s`)\n", path.Base(check.Function), check.Check, formatFilePos(check.File, check.Line)))
+	}
+	return b.String()
+}
+
+// concurrencyCounts are the categories of the concurrency summary, with the
+// site kinds each counts
+var concurrencyCounts = []struct {
+	label string
+	kinds []string
+}{
+	{"goroutines", []string{"go", "errgroup-go"}},
+	{"channel ops", []string{"send", "receive", "select", "close", "make-chan"}},
+	{"lock ops", []string{"lock", "unlock", "rlock", "runlock"}},
+	{"waits", []string{"wg-add", "wg-done", "wg-wait", "errgroup-wait"}},
+	{"contexts passed", []string{"ctx-pass"}},
+	{"new contexts", []string{"ctx-new"}},
+}
+
+// formatConcurrency renders the concurrency summary: the issues found, then
+// each function's operation counts and sites
+func formatConcurrency(c types.Concurrency) string {
+	var b strings.Builder
+	b.WriteString("## Concurrency\n\n")
+
+	if len(c.Issues) > 0 {
+		b.WriteString("Issues:\n")
+		for _, issue := range c.Issues {
+			b.WriteString(fmt.Sprintf("- **%s** `%s` in `%s`: %s\n", issue.Kind, formatFilePos(issue.File, issue.Line), path.Base(issue.Function), issue.Message))
+		}
+		b.WriteString("\n")
+	}
+
+	for _, fn := range c.Functions {
+		var counts []string
+		for _, category := range concurrencyCounts {
+			n := 0
+			for _, site := range fn.Sites {
+				for _, kind := range category.kinds {
+					if site.Kind == kind {
+						n++
+					}
+				}
+			}
+			if n > 0 {
+				counts = append(counts, fmt.Sprintf("%s: %d", category.label, n))
+			}
+		}
+		b.WriteString(fmt.Sprintf("**%s**", path.Base(fn.Function)))
+		if fn.Context {
+			b.WriteString(" (takes a context)")
+		}
+		b.WriteString(" — " + strings.Join(counts, ", ") + "\n")
+
+		for _, site := range fn.Sites {
+			line := fmt.Sprintf("- `%s` %s", formatFilePos(site.File, site.Line), site.Kind)
+			if site.Value != "" {
+				line += fmt.Sprintf(" `%s`", site.Value)
+			}
+			if site.Deferred {
+				line += " (deferred)"
+			}
+			b.WriteString(line + "\n")
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// formatTaintPath renders a source→sink path with its call chain and the
+// statements carrying the input
+func formatTaintPath(taint types.TaintPath) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("**%s** `%s` → **%s** `%s`\n\n", taint.Source.Kind, taint.Source.Value, taint.Sink.Kind, taint.Sink.Value))
+
+	chain := make([]string, len(taint.Chain))
+	for i, fn := range taint.Chain {
+		chain[i] = "`" + path.Base(fn) + "`"
+	}
+	b.WriteString(fmt.Sprintf("Call chain: %s\n\n", strings.Join(chain, " → ")))
+
+	b.WriteString(fmt.Sprintf("- source `%s`: `%s`\n", formatFilePos(taint.Source.File, taint.Source.Line), taint.Source.Code))
+	for _, step := range taint.Steps {
+		b.WriteString(fmt.Sprintf("- %s `%s` (`%s`)\n", step.Kind, step.Value, formatFilePos(step.File, step.Line)))
+	}
+	b.WriteString(fmt.Sprintf("- sink `%s`: `%s`\n\n", formatFilePos(taint.Sink.File, taint.Sink.Line), taint.Sink.Code))
+	return b.String()
+}
+
 // Helper to check if a string is in a slice
 func contains(slice []string, str string) bool {
 	for _, s := range slice {
@@ -236,3 +588,11 @@ func contains(slice []string, str string) bool {
 	}
 	return false
 }
+
+// qualifiedName names a symbol by package name, "memory.Store"
+func qualifiedName(sym types.Symbol) string {
+	if sym.Package == "" {
+		return sym.Name
+	}
+	return path.Base(sym.Package) + "." + sym.Name
+}
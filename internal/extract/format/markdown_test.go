@@ -3,6 +3,7 @@ package format
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/extract-scope-go/go-scope/internal/types"
 	"github.com/stretchr/testify/assert"
@@ -244,3 +245,255 @@ func TestFormatDepthGrouping(t *testing.T) {
 
 	assert.True(t, depth1Pos < depth2Pos, "Depth 1 deps should come before depth 2")
 }
+
+// TestFormatWithMetadata tests the extraction metadata section
+func TestFormatWithMetadata(t *testing.T) {
+	// Given: An extract and the metadata recorded while extracting it
+	ext := types.Extract{
+		Target: types.Symbol{Name: "Add", Kind: "func", Code: "func Add() {}"},
+	}
+	meta := types.Metadata{
+		ExtractedAt:   time.Date(2024, 3, 4, 5, 6, 7, 0, time.UTC),
+		GoVersion:     "go1.22",
+		Toolchain:     "go1.24.4",
+		Module:        "example.com/ex1",
+		ModuleVersion: "(devel)",
+		VCSRevision:   "abc123",
+		VCSModified:   true,
+		TotalSymbols:  2,
+		TotalLines:    12,
+		Timings:       types.Timings{Load: 120 * time.Millisecond, Total: 125 * time.Millisecond},
+	}
+
+	// When: We format with metadata
+	result, err := ToMarkdownWithMetadata(ext, meta, types.Options{})
+
+	// Then: Should record where and when the extract came from
+	require.NoError(t, err)
+	assert.Contains(t, result, "**Extracted**: 2024-03-04 05:06:07")
+	assert.Contains(t, result, "## Extraction Metadata")
+	assert.Contains(t, result, "- Module: `example.com/ex1@(devel)`")
+	assert.Contains(t, result, "- Revision: `abc123 (modified)`")
+	assert.Contains(t, result, "- Go: go1.22")
+	assert.Contains(t, result, "- Symbols: 2, Lines: 12")
+	assert.Contains(t, result, "load 120ms")
+
+	// Plain ToMarkdown has no metadata to show
+	plain, err := ToMarkdown(ext, types.Options{})
+	require.NoError(t, err)
+	assert.NotContains(t, plain, "## Extraction Metadata")
+}
+
+// TestFormatRenderModes tests full, signature and name-only references
+func TestFormatRenderModes(t *testing.T) {
+	// Given: One reference in each rendering mode
+	ext := types.Extract{
+		Target: types.Symbol{Name: "Run", Kind: "func"},
+		References: []types.Reference{
+			{
+				Symbol: types.Symbol{Name: "Full", Kind: "func", Doc: "Full is shown in full", Code: "func Full() {\n\tfullBody()\n}"},
+				Depth:  1,
+				Render: "full",
+			},
+			{
+				Symbol:    types.Symbol{Name: "Sig", Kind: "func", Doc: "Sig has a contract", Code: "func Sig() int {\n\treturn sigBody()\n}"},
+				Signature: "func Sig() int",
+				Depth:     2,
+				Render:    "signature",
+			},
+			{
+				Symbol: types.Symbol{Name: "Named", Kind: "func", Package: "example.com/deep", File: "/src/deep.go", Line: 9, Doc: "Named hides its doc", Code: "func Named() {\n\tnamedBody()\n}"},
+				Depth:  3,
+				Render: "name",
+			},
+		},
+	}
+
+	// When: We format as markdown
+	result, err := ToMarkdown(ext, types.Options{})
+
+	// Then: Each reference shows only what its mode allows
+	require.NoError(t, err)
+	assert.Contains(t, result, "fullBody()")
+	assert.Contains(t, result, "Full is shown in full")
+
+	assert.Contains(t, result, "Sig has a contract")
+	assert.Contains(t, result, "```go\nfunc Sig() int\n```")
+	assert.NotContains(t, result, "sigBody()")
+
+	assert.Contains(t, result, "- **Named** (func) — example.com/deep (deep.go:9)")
+	assert.NotContains(t, result, "Named hides its doc")
+	assert.NotContains(t, result, "namedBody()")
+}
+
+// TestFormatRoles tests that hexagonal roles appear for the target and references
+func TestFormatRoles(t *testing.T) {
+	// Given: An adapter method that uses a domain entity
+	ext := types.Extract{
+		Target: types.Symbol{Name: "Get", Kind: "method", Receiver: "*Repository", Role: "secondary-adapter"},
+		References: []types.Reference{
+			{Symbol: types.Symbol{Name: "User", Kind: "type", Role: "domain-entity"}, Depth: 1},
+			{Symbol: types.Symbol{Name: "id", Kind: "var"}, Depth: 1},
+		},
+	}
+
+	// When: We format as markdown
+	result, err := ToMarkdown(ext, types.Options{})
+
+	// Then: Classified symbols show their role
+	require.NoError(t, err)
+	assert.Contains(t, result, "**Role**: secondary-adapter\n")
+	assert.Contains(t, result, "**Role**: domain-entity\n")
+	assert.Equal(t, 2, strings.Count(result, "**Role**"))
+}
+
+// TestFormatInterfaceGaps tests the near-miss implementation report
+func TestFormatInterfaceGaps(t *testing.T) {
+	// Given: A repository missing one method and mistyping another
+	ext := types.Extract{
+		Target: types.Symbol{Name: "UserRepository", Kind: "interface"},
+		InterfaceGaps: []types.InterfaceGap{{
+			Interface: types.Symbol{Name: "UserRepository", Package: "example.com/app/domain"},
+			Type:      types.Symbol{Name: "FooRepo", Package: "example.com/app/adapters/foo"},
+			Missing: []types.MethodGap{
+				{Method: "Delete(ctx context.Context, id string) error", Reason: "missing"},
+				{Method: "Get(id string) (*domain.User, error)", Reason: "wrong-signature", Found: "Get(id int) (*domain.User, error)"},
+			},
+		}},
+	}
+
+	// When: We format as markdown
+	result, err := ToMarkdown(ext, types.Options{})
+
+	// Then: Each gap names the method to add or fix
+	require.NoError(t, err)
+	assert.Contains(t, result, "## Interface Gaps")
+	assert.Contains(t, result, "**foo.FooRepo** does not implement **domain.UserRepository**:")
+	assert.Contains(t, result, "- missing `Delete(ctx context.Context, id string) error`\n")
+	assert.Contains(t, result, "- has `Get(id int) (*domain.User, error)`, want `Get(id string) (*domain.User, error)`\n")
+}
+
+// TestFormatTaintPaths tests rendering a source→sink path with its call chain
+func TestFormatTaintPaths(t *testing.T) {
+	// Given: A request parameter reaching a SQL query in another package
+	ext := types.Extract{
+		Target: types.Symbol{Name: "Search", Kind: "method", Receiver: "*Handler"},
+		Taints: []types.TaintPath{{
+			Source: types.TaintEndpoint{Kind: "http", Value: "r *http.Request", Function: "example.com/app/server.Handler.Search", File: "/src/server/http.go", Line: 26, Code: "func (h *Handler) Search(w http.ResponseWriter, r *http.Request) {"},
+			Sink:   types.TaintEndpoint{Kind: "sql", Value: "s.db.Query", Function: "example.com/app/store.Store.Find", File: "/src/store/store.go", Line: 18, Code: "rows, err := s.db.Query(query)"},
+			Chain:  []string{"example.com/app/server.Handler.Search", "example.com/app/store.Store.Find"},
+			Steps: []types.FlowStep{
+				{Direction: "forward", Kind: "call-arg", Value: "h.users.Find", File: "/src/server/http.go", Line: 28},
+			},
+		}},
+	}
+
+	// When: We format as markdown
+	result, err := ToMarkdown(ext, types.Options{Taint: true})
+
+	// Then: The path reads from source through the chain to the sink
+	require.NoError(t, err)
+	assert.Contains(t, result, "## Taint Paths\n\n**http** `r *http.Request` → **sql** `s.db.Query`\n\n")
+	assert.Contains(t, result, "Call chain: `server.Handler.Search` → `store.Store.Find`\n")
+	assert.Contains(t, result, "- source `http.go:26`: `func (h *Handler) Search(w http.ResponseWriter, r *http.Request) {`\n"+
+		"- call-arg `h.users.Find` (`http.go:28`)\n"+
+		"- sink `store.go:18`: `rows, err := s.db.Query(query)`\n")
+}
+
+// TestFormatErrorPaths tests rendering an error's origin, wrapping and checks
+func TestFormatErrorPaths(t *testing.T) {
+	// Given: A wrapped sentinel checked by a caller, and an opaque error
+	ext := types.Extract{
+		Target: types.Symbol{Name: "Rename", Kind: "method", File: "/src/app/service.go"},
+		ErrorPaths: []types.ErrorPath{
+			{
+				Origin: "sentinel", Value: "domain.ErrNotFound", Message: "user not found", Sentinel: "example.com/app/domain.ErrNotFound",
+				Function: "example.com/app/memory.Repository.Get", File: "/src/app/memory/repo.go", Line: 23, Return: 20,
+				Wraps:     []string{"get user %s: %w"},
+				Via:       []string{"example.com/app/memory.Repository.Get"},
+				CheckedBy: []types.ErrorCheck{{Function: "example.com/app/rest.Handler.ServeHTTP", Check: "errors.Is", File: "/src/app/rest/handler.go", Line: 26}},
+			},
+			{Origin: "errorf", Value: `fmt.Errorf("no name")`, Function: "example.com/app/email.Notifier.Notify", File: "/src/app/email/notifier.go", Line: 22, Return: 28},
+		},
+	}
+
+	// When: We format as markdown
+	result, err := ToMarkdown(ext, types.Options{})
+
+	// Then: Each error reads from origin to the callers matching it
+	require.NoError(t, err)
+	assert.Contains(t, result, "## Error Paths\n\n"+
+		"- **sentinel** `domain.ErrNotFound` (\"user not found\") — `repo.go:23` in `memory.Repository.Get`\n"+
+		"  - returned at `service.go:20` via `memory.Repository.Get`\n"+
+		"  - wrapped by `\"get user %s: %w\"`\n"+
+		"  - checked by `rest.Handler.ServeHTTP` with errors.Is (`handler.go:26`)\n"+
+		"- **errorf** `fmt.Errorf(\"no name\")` — `notifier.go:22` in `email.Notifier.Notify`\n"+
+		"  - returned at `service.go:28`\n"+
+		"  - opaque: no sentinel or type for callers to match\n")
+}
+
+// TestFormatConcurrency tests rendering concurrency issues and per-function sites
+func TestFormatConcurrency(t *testing.T) {
+	// Given: A worker with a lock released without defer
+	ext := types.Extract{
+		Target: types.Symbol{Name: "Pool", Kind: "struct", File: "/src/worker/pool.go"},
+		Concurrency: &types.Concurrency{
+			Functions: []types.ConcurrencyFunction{
+				{
+					Function: "example.com/worker.Pool.Start", Context: true,
+					Sites: []types.ConcurrencySite{
+						{Kind: "wg-add", Value: "p.wg", File: "/src/worker/pool.go", Line: 28},
+						{Kind: "go", Value: "p.work", File: "/src/worker/pool.go", Line: 29},
+						{Kind: "ctx-pass", Value: "p.work", File: "/src/worker/pool.go", Line: 29},
+					},
+				},
+				{
+					Function: "example.com/worker.Pool.finish",
+					Sites: []types.ConcurrencySite{
+						{Kind: "lock", Value: "p.mu", File: "/src/worker/pool.go", Line: 75},
+						{Kind: "unlock", Value: "p.mu", File: "/src/worker/pool.go", Line: 77},
+					},
+				},
+			},
+			Issues: []types.ConcurrencyIssue{{
+				Kind: "lock-without-defer", Message: "p.mu.Lock() has no deferred p.mu.Unlock()",
+				Function: "example.com/worker.Pool.finish", File: "/src/worker/pool.go", Line: 75,
+			}},
+		},
+	}
+
+	// When: We format as markdown
+	result, err := ToMarkdown(ext, types.Options{})
+
+	// Then: Issues come first, then each function's counts and sites
+	require.NoError(t, err)
+	assert.Contains(t, result, "## Concurrency\n\n"+
+		"Issues:\n"+
+		"- **lock-without-defer** `pool.go:75` in `worker.Pool.finish`: p.mu.Lock() has no deferred p.mu.Unlock()\n\n"+
+		"**worker.Pool.Start** (takes a context) — goroutines: 1, waits: 1, contexts passed: 1\n"+
+		"- `pool.go:28` wg-add `p.wg`\n"+
+		"- `pool.go:29` go `p.work`\n"+
+		"- `pool.go:29` ctx-pass `p.work`\n\n"+
+		"**worker.Pool.finish** — lock ops: 2\n")
+}
+
+// TestFormatEffects tests that side effects appear as badges for functions
+func TestFormatEffects(t *testing.T) {
+	// Given: A target performing I/O, and a pure helper
+	ext := types.Extract{
+		Target: types.Symbol{Name: "main", Kind: "func", Effects: []string{"io", "logs", "panics"}},
+		References: []types.Reference{
+			{Symbol: types.Symbol{Name: "validate", Kind: "func", Effects: []string{"pure"}}, Depth: 1},
+			{Symbol: types.Symbol{Name: "User", Kind: "type"}, Depth: 1},
+		},
+	}
+
+	// When: We format as markdown
+	result, err := ToMarkdown(ext, types.Options{})
+
+	// Then: Each function has its badges, and the type has none
+	require.NoError(t, err)
+	assert.Contains(t, result, "**Effects**: `io` `logs` `panics`\n")
+	assert.Contains(t, result, "**Effects**: `pure`\n\n")
+	assert.Equal(t, 2, strings.Count(result, "**Effects**"))
+}
@@ -0,0 +1,162 @@
+package format
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+)
+
+// SymbolsMarkdown renders a titled list of symbols with their locations
+// relative to root
+func SymbolsMarkdown(title string, symbols []types.Symbol, root string) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("# %s\n\n", title))
+
+	if len(symbols) == 0 {
+		b.WriteString("No symbols found.\n")
+		return b.String()
+	}
+
+	for _, sym := range symbols {
+		b.WriteString(fmt.Sprintf("- `%s` (%s) — %s\n", sym.ID(), sym.Kind, relativePos(root, sym.File, sym.Line)))
+	}
+	return b.String()
+}
+
+// CallersMarkdown renders the uses of a symbol with their locations relative to root
+func CallersMarkdown(sym types.Symbol, callers []types.Caller, root string) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("# Callers of %s\n\n", sym.ID()))
+
+	if len(callers) == 0 {
+		b.WriteString("No callers found.\n")
+		return b.String()
+	}
+
+	for _, caller := range callers {
+		b.WriteString(fmt.Sprintf("- %s", relativePos(root, caller.File, caller.Line)))
+		if caller.Function != "" {
+			b.WriteString(fmt.Sprintf(" in `%s`", caller.Function))
+		}
+		if caller.Context != "" {
+			b.WriteString(fmt.Sprintf(": `%s`", caller.Context))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// DIGraphMarkdown renders a DI graph as the frameworks of each package, then
+// providers with their products and dependencies
+func DIGraphMarkdown(graph *types.DIGraph) string {
+	var b strings.Builder
+	frameworks := "none"
+	if len(graph.Frameworks) > 0 {
+		frameworks = strings.Join(graph.Frameworks, ", ")
+	}
+	b.WriteString(fmt.Sprintf("# DI Graph (%s)\n\n", frameworks))
+	for _, warning := range graph.Warnings {
+		b.WriteString(fmt.Sprintf("> Warning: %s\n\n", warning))
+	}
+
+	if len(graph.Packages) > 0 {
+		b.WriteString("## Packages\n\n")
+		for _, pkg := range graph.Packages {
+			b.WriteString(fmt.Sprintf("- `%s`: %s\n", pkg.Package, strings.Join(pkg.Frameworks, ", ")))
+		}
+		b.WriteString("\n## Bindings\n\n")
+	}
+
+	if len(graph.Bindings) == 0 {
+		b.WriteString("No bindings found.\n")
+		return b.String()
+	}
+
+	for _, binding := range graph.Bindings {
+		b.WriteString("- ")
+		if binding.Kind != "" && binding.Kind != "provide" {
+			b.WriteString(binding.Kind + " ")
+		}
+		b.WriteString(fmt.Sprintf("`%s`", binding.Provider.ID()))
+		if binding.Product.Name != "" {
+			b.WriteString(fmt.Sprintf(" → `%s`", binding.Product.ID()))
+		}
+
+		var notes []string
+		if len(graph.Frameworks) > 1 {
+			notes = append(notes, binding.Framework)
+		}
+		if binding.Scope != "" {
+			notes = append(notes, binding.Scope)
+		}
+		if binding.Init != "" {
+			notes = append(notes, binding.Init)
+		}
+		if binding.Module != "" {
+			notes = append(notes, "module "+binding.Module)
+		}
+		if len(notes) > 0 {
+			b.WriteString(fmt.Sprintf(" (%s)", strings.Join(notes, ", ")))
+		}
+		b.WriteString("\n")
+
+		for _, value := range binding.Provides {
+			b.WriteString(fmt.Sprintf("  - provides %s\n", diValueString(value)))
+		}
+		if len(binding.Requires) > 0 {
+			for _, value := range binding.Requires {
+				b.WriteString(fmt.Sprintf("  - needs %s\n", diValueString(value)))
+			}
+		} else {
+			for _, dep := range binding.Dependencies {
+				b.WriteString(fmt.Sprintf("  - needs `%s`\n", dep.ID()))
+			}
+		}
+		if len(binding.Hooks) > 0 {
+			b.WriteString(fmt.Sprintf("  - hooks %s\n", strings.Join(binding.Hooks, ", ")))
+		}
+		if len(binding.Injectors) > 0 {
+			b.WriteString(fmt.Sprintf("  - injectors %s\n", strings.Join(binding.Injectors, ", ")))
+		}
+		if len(binding.Apps) > 0 {
+			b.WriteString(fmt.Sprintf("  - apps %s\n", strings.Join(binding.Apps, ", ")))
+		}
+		if binding.Options != nil {
+			for _, line := range optionLines(*binding.Options) {
+				b.WriteString("  - " + line + "\n")
+			}
+		}
+	}
+	return b.String()
+}
+
+// diValueString formats a container value with its tags,
+// "`Repository` name:\"cache\" (optional)"
+func diValueString(value types.DIValue) string {
+	s := fmt.Sprintf("`%s`", value.Type)
+	if value.Name != "" {
+		s += fmt.Sprintf(" name:%q", value.Name)
+	}
+	if value.Group != "" {
+		s += fmt.Sprintf(" group:%q", value.Group)
+	}
+	if value.Optional {
+		s += " (optional)"
+	}
+	return s
+}
+
+// relativePos formats file:line relative to root when possible
+func relativePos(root, file string, line int) string {
+	if root != "" && file != "" {
+		if rel, err := filepath.Rel(root, file); err == nil && !strings.HasPrefix(rel, "..") {
+			file = filepath.ToSlash(rel)
+		}
+	}
+	if file == "" {
+		return fmt.Sprintf("line %d", line)
+	}
+	return fmt.Sprintf("%s:%d", file, line)
+}
@@ -0,0 +1,212 @@
+package format
+
+import (
+	"testing"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSymbolsMarkdown tests symbol lists with root-relative locations
+func TestSymbolsMarkdown(t *testing.T) {
+	// Given: A symbol inside and one outside the root
+	symbols := []types.Symbol{
+		{Name: "Repository", Kind: "struct", Package: "example.com/app/memory", File: "/src/app/memory/repo.go", Line: 6},
+		{Name: "Reader", Kind: "interface", Package: "io", File: "/usr/lib/go/src/io/io.go", Line: 86},
+	}
+
+	// When: We render them
+	result := SymbolsMarkdown("Implementations of example.com/app.Store", symbols, "/src/app")
+
+	// Then: Paths are relative to the root where possible
+	assert.Contains(t, result, "# Implementations of example.com/app.Store")
+	assert.Contains(t, result, "- `example.com/app/memory.Repository` (struct) — memory/repo.go:6")
+	assert.Contains(t, result, "- `io.Reader` (interface) — /usr/lib/go/src/io/io.go:86")
+
+	assert.Contains(t, SymbolsMarkdown("Search", nil, ""), "No symbols found.")
+}
+
+// TestCallersMarkdown tests caller lists
+func TestCallersMarkdown(t *testing.T) {
+	// Given: One call site inside a function and one at package level
+	sym := types.Symbol{Name: "New", Package: "example.com/app/store"}
+	callers := []types.Caller{
+		{File: "/src/app/main.go", Line: 12, Function: "main", Context: "s := store.New()"},
+		{File: "/src/app/vars.go", Line: 3},
+	}
+
+	// When: We render them
+	result := CallersMarkdown(sym, callers, "/src/app")
+
+	// Then: Each use is listed with its function and source line
+	assert.Contains(t, result, "# Callers of example.com/app/store.New")
+	assert.Contains(t, result, "- main.go:12 in `main`: `s := store.New()`")
+	assert.Contains(t, result, "- vars.go:3\n")
+}
+
+// TestDIGraphMarkdown tests provider, product and dependency rendering
+func TestDIGraphMarkdown(t *testing.T) {
+	// Given: A constructor binding with one dependency
+	bindings := []types.DIBinding{{
+		Provider:     types.Symbol{Name: "NewService", Package: "example.com/app"},
+		Product:      types.Symbol{Name: "Service", Package: "example.com/app"},
+		Dependencies: []types.Symbol{{Name: "Store", Package: "example.com/app"}},
+		Framework:    "manual",
+		Scope:        "singleton",
+	}}
+
+	// When: We render the graph
+	result := DIGraphMarkdown(&types.DIGraph{Frameworks: []string{"manual"}, Bindings: bindings})
+
+	// Then: The binding and its dependency are listed
+	assert.Contains(t, result, "# DI Graph (manual)")
+	assert.Contains(t, result, "- `example.com/app.NewService` → `example.com/app.Service` (singleton)")
+	assert.Contains(t, result, "  - needs `example.com/app.Store`")
+}
+
+// TestDIGraphMarkdownTags tests container values with names, groups and hooks
+func TestDIGraphMarkdownTags(t *testing.T) {
+	// Given: An Fx constructor in a module, and an invoked function
+	bindings := []types.DIBinding{
+		{
+			Provider:  types.Symbol{Name: "NewService", Package: "example.com/app/users"},
+			Product:   types.Symbol{Name: "Service", Package: "example.com/app/users"},
+			Framework: "fx",
+			Scope:     "singleton",
+			Kind:      "provide",
+			Module:    "users",
+			Provides:  []types.DIValue{{Type: "*example.com/app/users.Service"}},
+			Requires: []types.DIValue{
+				{Type: "example.com/app/users.Repository", Name: "cache", Optional: true},
+				{Type: "example.com/app/users.Handler", Group: "routes"},
+			},
+			Hooks: []string{"OnStart", "OnStop"},
+		},
+		{
+			Provider:  types.Symbol{Name: "main.func1", Package: "example.com/app"},
+			Framework: "fx",
+			Kind:      "invoke",
+		},
+	}
+
+	// When: We render the graph
+	result := DIGraphMarkdown(&types.DIGraph{Frameworks: []string{"fx"}, Bindings: bindings})
+
+	// Then: Tags, modules, kinds and hooks are shown
+	assert.Contains(t, result, "- `example.com/app/users.NewService` → `example.com/app/users.Service` (singleton, module users)\n")
+	assert.Contains(t, result, "  - provides `*example.com/app/users.Service`\n")
+	assert.Contains(t, result, "  - needs `example.com/app/users.Repository` name:\"cache\" (optional)\n")
+	assert.Contains(t, result, "  - needs `example.com/app/users.Handler` group:\"routes\"\n")
+	assert.Contains(t, result, "  - hooks OnStart, OnStop\n")
+	assert.Contains(t, result, "- invoke `example.com/app.main.func1`\n")
+}
+
+// TestDIGraphMarkdownInjectors tests listing the Wire injectors of a binding
+func TestDIGraphMarkdownInjectors(t *testing.T) {
+	// Given: A wire.Bind used by one injector
+	bindings := []types.DIBinding{{
+		Provider:  types.Symbol{Name: "SQLStore", Package: "example.com/app/store"},
+		Product:   types.Symbol{Name: "Repository", Package: "example.com/app/store"},
+		Framework: "wire",
+		Scope:     "singleton",
+		Kind:      "bind",
+		Module:    "store.SQLSet",
+		Provides:  []types.DIValue{{Type: "example.com/app/store.Repository"}},
+		Requires:  []types.DIValue{{Type: "*example.com/app/store.SQLStore"}},
+		Injectors: []string{"main.InitializeServer"},
+	}}
+
+	// When: We render the graph
+	result := DIGraphMarkdown(&types.DIGraph{Frameworks: []string{"wire"}, Bindings: bindings})
+
+	// Then: The binding names its set and injector
+	assert.Contains(t, result, "- bind `example.com/app/store.SQLStore` → `example.com/app/store.Repository` (singleton, module store.SQLSet)\n")
+	assert.Contains(t, result, "  - injectors main.InitializeServer\n")
+}
+
+// TestDIGraphMarkdownApps tests listing the Fx, dig or do apps of a binding
+func TestDIGraphMarkdownApps(t *testing.T) {
+	// Given: A constructor registered by two Fx apps
+	bindings := []types.DIBinding{{
+		Provider:  types.Symbol{Name: "NewConn", Package: "example.com/app/db"},
+		Framework: "fx",
+		Scope:     "singleton",
+		Provides:  []types.DIValue{{Type: "*example.com/app/db.Conn"}},
+		Apps:      []string{"app.main", "migrate.main"},
+	}}
+
+	// When: We render the graph
+	result := DIGraphMarkdown(&types.DIGraph{Frameworks: []string{"fx"}, Bindings: bindings})
+
+	// Then: The binding names both apps
+	assert.Contains(t, result, "  - apps app.main, migrate.main\n")
+}
+
+// TestDIGraphMarkdownFrameworks tests a module mixing frameworks across packages
+func TestDIGraphMarkdownFrameworks(t *testing.T) {
+	// Given: Wire in the application and manual constructors in a library
+	graph := &types.DIGraph{
+		Frameworks: []string{"wire", "manual"},
+		Packages: []types.DIPackage{
+			{Package: "example.com/app/cmd/server", Frameworks: []string{"wire"}},
+			{Package: "example.com/app/lib", Frameworks: []string{"manual"}},
+		},
+		Bindings: []types.DIBinding{{
+			Provider:  types.Symbol{Name: "NewClient", Package: "example.com/app/lib"},
+			Framework: "manual",
+			Scope:     "singleton",
+		}},
+	}
+
+	// When: We render the graph
+	result := DIGraphMarkdown(graph)
+
+	// Then: Frameworks are listed per package and per binding
+	assert.Contains(t, result, "# DI Graph (wire, manual)\n")
+	assert.Contains(t, result, "## Packages\n\n- `example.com/app/cmd/server`: wire\n- `example.com/app/lib`: manual\n")
+	assert.Contains(t, result, "- `example.com/app/lib.NewClient` (manual, singleton)\n")
+}
+
+// TestDIGraphMarkdownNone tests a module without DI
+func TestDIGraphMarkdownNone(t *testing.T) {
+	// Given: No frameworks or bindings
+	graph := &types.DIGraph{}
+
+	// When: We render the graph
+	result := DIGraphMarkdown(graph)
+
+	// Then: The graph says so
+	assert.Equal(t, "# DI Graph (none)\n\nNo bindings found.\n", result)
+}
+
+// TestDIGraphMarkdownOptions tests rendering a provider's functional options
+func TestDIGraphMarkdownOptions(t *testing.T) {
+	// Given: A provider taking ...Option, called once with a timeout
+	graph := &types.DIGraph{
+		Frameworks: []string{"manual"},
+		Bindings: []types.DIBinding{{
+			Provider:  types.Symbol{Name: "NewServer", Package: "example.com/app/server"},
+			Framework: "manual",
+			Options: &types.ConstructorOptions{
+				Style: "functional",
+				Type:  types.Symbol{Name: "Option", Package: "example.com/app/server"},
+				Options: []types.ConstructorOption{
+					{Name: "WithTimeout", Fields: []string{"timeout"}},
+					{Name: "WithTags", Fields: []string{"tags", "sorted"}},
+				},
+				Defaults:  []types.OptionDefault{{Field: "timeout", Value: "30 * time.Second"}},
+				CallSites: []types.OptionCall{{Function: "main", File: "/app/main.go", Line: 12, Options: []string{"server.WithTimeout(time.Minute)"}}},
+			},
+		}},
+	}
+
+	// When: We render the graph
+	result := DIGraphMarkdown(graph)
+
+	// Then: Options, defaults and call sites are listed under the binding
+	assert.Contains(t, result, "  - options `...server.Option`\n"+
+		"  - option `WithTimeout` sets timeout\n"+
+		"  - option `WithTags` sets tags, sorted\n"+
+		"  - default `timeout` = `30 * time.Second`\n"+
+		"  - called by main (`main.go:12`) with `server.WithTimeout(time.Minute)`\n")
+}
@@ -0,0 +1,97 @@
+package format
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+)
+
+// SchemaVersion is the version of the canonical result document.
+// The major component changes only when existing fields are removed or
+// change meaning; readers reject documents with a newer major version.
+const SchemaVersion = "1.0"
+
+// SchemaID identifies the JSON Schema describing the result document
+const SchemaID = "https://github.com/extract-scope-go/go-scope/schema/result/v1.json"
+
+//go:embed schema.json
+var jsonSchema string
+
+// JSONSchema returns the JSON Schema document for the canonical result format
+func JSONSchema() string {
+	return jsonSchema
+}
+
+// Document is the canonical, versioned JSON representation of a types.Result
+type Document struct {
+	Schema        string `json:"$schema,omitempty"`
+	SchemaVersion string `json:"schemaVersion"`
+	types.Result
+}
+
+// ToResultJSON converts a full Result (extract and metadata) to the
+// canonical JSON document. Unlike ToJSON, nothing is dropped or reshaped.
+func ToResultJSON(result types.Result) (string, error) {
+	// The rendered output is derived from the extract; don't nest it
+	result.Rendered = ""
+
+	doc := Document{
+		Schema:        SchemaID,
+		SchemaVersion: SchemaVersion,
+		Result:        result,
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// FromJSON decodes a canonical result document produced by ToResultJSON
+func FromJSON(data []byte) (*types.Result, error) {
+	var doc Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode result document: %w", err)
+	}
+
+	if doc.SchemaVersion == "" {
+		return nil, fmt.Errorf("missing schemaVersion: not a go-scope result document")
+	}
+
+	if err := checkSchemaVersion(doc.SchemaVersion); err != nil {
+		return nil, err
+	}
+
+	return &doc.Result, nil
+}
+
+// checkSchemaVersion ensures a document version is readable by this build
+func checkSchemaVersion(version string) error {
+	major, err := schemaMajor(version)
+	if err != nil {
+		return err
+	}
+
+	current, _ := schemaMajor(SchemaVersion)
+	if major > current {
+		return fmt.Errorf("unsupported schemaVersion %s (this build reads up to %d.x)", version, current)
+	}
+
+	return nil
+}
+
+// schemaMajor parses the major component of a "major.minor" version
+func schemaMajor(version string) (int, error) {
+	majorStr, _, _ := strings.Cut(version, ".")
+	major, err := strconv.Atoi(majorStr)
+	if err != nil {
+		return 0, fmt.Errorf("invalid schemaVersion %q", version)
+	}
+	return major, nil
+}
@@ -0,0 +1,241 @@
+package format
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestResultJSONRoundTrip tests that a full Result survives encode/decode
+func TestResultJSONRoundTrip(t *testing.T) {
+	// Given: A result with every section populated
+	constructor := types.Symbol{Name: "NewService", Kind: "func", Package: "example.com/svc", InterfaceType: "Service", Implementation: "service"}
+	result := types.Result{
+		Extract: types.Extract{
+			Target: types.Symbol{
+				Name:     "Handle",
+				Kind:     "method",
+				Receiver: "*Handler",
+				Package:  "example.com/svc",
+				File:     "/src/handler.go",
+				Line:     10,
+				EndLine:  20,
+				Column:   1,
+				Code:     "func (h *Handler) Handle() {}",
+				Doc:      "Handle serves a request\n",
+				Exported: true,
+				Effects:  []string{"io", "logs"},
+			},
+			References: []types.Reference{
+				{
+					Symbol:       types.Symbol{Name: "helper", Kind: "func", Package: "example.com/svc", Code: "func helper() {}", Effects: []string{"pure"}},
+					Reason:       "direct-call",
+					Depth:        1,
+					ReferencedBy: "Handle",
+				},
+				{
+					Symbol:    types.Symbol{Name: "Println", Kind: "func", Package: "fmt", Exported: true},
+					Reason:    "direct-call",
+					Depth:     1,
+					External:  true,
+					Stub:      true,
+					Signature: "func Println(a ...any) (n int, err error)",
+				},
+			},
+			External: []string{"fmt.Println"},
+			Callers:  []types.Caller{{File: "main.go", Line: 3, Function: "main"}},
+			Metrics:  &types.Metrics{LinesOfCode: 11, CyclomaticComplexity: 2},
+			GitHistory: []types.GitBlame{
+				{Commit: "abc123", Author: "dev@example.com", Date: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), Message: "init"},
+			},
+			InterfaceMappings: []types.InterfaceMapping{
+				{
+					Interface:       types.Symbol{Name: "Service", Kind: "interface", Package: "example.com/svc"},
+					Implementations: []types.Symbol{{Name: "service", Kind: "struct", Package: "example.com/svc"}},
+					Constructor:     &constructor,
+					DIFramework:     "manual",
+				},
+			},
+			DIBindings: []types.DIBinding{
+				{
+					Provider:     constructor,
+					Product:      types.Symbol{Name: "Service", Kind: "interface", Package: "example.com/svc"},
+					Dependencies: []types.Symbol{{Name: "Repo", Kind: "interface", Package: "example.com/svc"}},
+					Framework:    "manual",
+					Scope:        "singleton",
+				},
+			},
+			DetectedDIFramework: "manual",
+			ErrorPaths: []types.ErrorPath{{
+				Origin: "sentinel", Value: "ErrMissing", Message: "missing", Sentinel: "example.com/svc.ErrMissing",
+				Function: "example.com/svc.helper", File: "/src/handler.go", Line: 30, Return: 15,
+				Wraps:     []string{"handle: %w"},
+				Via:       []string{"example.com/svc.helper"},
+				CheckedBy: []types.ErrorCheck{{Function: "main", Check: "errors.Is", File: "main.go", Line: 4}},
+			}},
+			Concurrency: &types.Concurrency{
+				Functions: []types.ConcurrencyFunction{{
+					Function: "example.com/svc.Handler.Handle", Context: true,
+					Sites: []types.ConcurrencySite{{Kind: "unlock", Value: "h.mu", File: "/src/handler.go", Line: 11, Code: "defer h.mu.Unlock()", Deferred: true}},
+				}},
+				Issues: []types.ConcurrencyIssue{{Kind: "goroutine-without-context", Message: "goroutine started without a context", Function: "example.com/svc.Handler.Handle", File: "/src/handler.go", Line: 13, Code: "go h.flush()"}},
+			},
+			Taints: []types.TaintPath{{
+				Source: types.TaintEndpoint{Kind: "http", Value: "r *http.Request", Function: "example.com/svc.Handler.Handle", File: "/src/handler.go", Line: 10},
+				Sink:   types.TaintEndpoint{Kind: "exec", Value: "exec.Command", Function: "example.com/svc.Handler.Handle", File: "/src/handler.go", Line: 12},
+				Chain:  []string{"example.com/svc.Handler.Handle"},
+				Steps:  []types.FlowStep{{Direction: "forward", Kind: "call-arg", Value: "r.FormValue", Function: "example.com/svc.Handler.Handle", File: "/src/handler.go", Line: 12}},
+			}},
+		},
+		Metadata: types.Metadata{
+			ExtractedAt:   time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC),
+			GoVersion:     "go1.24.4",
+			Module:        "example.com/svc",
+			ModuleVersion: "v1.2.3",
+			TotalSymbols:  3,
+			TotalLines:    25,
+			Options:       types.Options{Depth: 2, Format: "result", Annotate: true, Taint: true},
+		},
+	}
+
+	// When: We encode and decode it
+	data, err := ToResultJSON(result)
+	require.NoError(t, err)
+
+	decoded, err := FromJSON([]byte(data))
+
+	// Then: Nothing should be lost
+	require.NoError(t, err)
+	assert.Equal(t, result, *decoded)
+	assert.Contains(t, data, `"schemaVersion": "`+SchemaVersion+`"`)
+}
+
+// TestResultJSONOmitsRendered tests that rendered output is not nested in the document
+func TestResultJSONOmitsRendered(t *testing.T) {
+	// Given: A result that was already rendered
+	result := types.Result{
+		Extract:  types.Extract{Target: types.Symbol{Name: "Add", Kind: "func"}},
+		Rendered: "# Code Extract: Add",
+	}
+
+	// When: We encode it
+	data, err := ToResultJSON(result)
+
+	// Then: The rendered text should not be part of the document
+	require.NoError(t, err)
+	assert.NotContains(t, data, "Code Extract")
+}
+
+// TestFromJSONRejectsUnversioned tests that arbitrary JSON is not accepted
+func TestFromJSONRejectsUnversioned(t *testing.T) {
+	// Given: The visualizer JSON, which has no schema version
+	viz, err := ToJSON(types.Extract{Target: types.Symbol{Name: "Add"}}, types.Options{})
+	require.NoError(t, err)
+
+	// When: We try to load it as a result document
+	_, err = FromJSON([]byte(viz))
+
+	// Then: Should be rejected
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "schemaVersion")
+}
+
+// TestFromJSONVersionCompatibility tests major/minor version handling
+func TestFromJSONVersionCompatibility(t *testing.T) {
+	tests := []struct {
+		version string
+		wantErr bool
+	}{
+		{"1.0", false},
+		{"1.7", false},
+		{"0.9", false},
+		{"2.0", true},
+		{"latest", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			data := `{"schemaVersion": "` + tt.version + `", "extract": {"target": {"name": "Add"}}, "metadata": {}}`
+
+			result, err := FromJSON([]byte(data))
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "Add", result.Extract.Target.Name)
+		})
+	}
+}
+
+// TestJSONSchemaCoversTypes tests that the schema documents every serialized field
+func TestJSONSchemaCoversTypes(t *testing.T) {
+	// Given: The embedded JSON Schema
+	var schema struct {
+		ID         string                    `json:"$id"`
+		Properties map[string]any            `json:"properties"`
+		Defs       map[string]map[string]any `json:"$defs"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(JSONSchema()), &schema))
+	assert.Equal(t, SchemaID, schema.ID)
+
+	defs := map[string]reflect.Type{
+		"symbol":              reflect.TypeOf(types.Symbol{}),
+		"reference":           reflect.TypeOf(types.Reference{}),
+		"caller":              reflect.TypeOf(types.Caller{}),
+		"metrics":             reflect.TypeOf(types.Metrics{}),
+		"gitBlame":            reflect.TypeOf(types.GitBlame{}),
+		"interfaceMapping":    reflect.TypeOf(types.InterfaceMapping{}),
+		"interfaceGap":        reflect.TypeOf(types.InterfaceGap{}),
+		"methodGap":           reflect.TypeOf(types.MethodGap{}),
+		"diBinding":           reflect.TypeOf(types.DIBinding{}),
+		"diPackage":           reflect.TypeOf(types.DIPackage{}),
+		"diValue":             reflect.TypeOf(types.DIValue{}),
+		"constructorOptions":  reflect.TypeOf(types.ConstructorOptions{}),
+		"constructorOption":   reflect.TypeOf(types.ConstructorOption{}),
+		"optionDefault":       reflect.TypeOf(types.OptionDefault{}),
+		"optionCall":          reflect.TypeOf(types.OptionCall{}),
+		"errorPath":           reflect.TypeOf(types.ErrorPath{}),
+		"errorCheck":          reflect.TypeOf(types.ErrorCheck{}),
+		"concurrency":         reflect.TypeOf(types.Concurrency{}),
+		"concurrencyFunction": reflect.TypeOf(types.ConcurrencyFunction{}),
+		"concurrencySite":     reflect.TypeOf(types.ConcurrencySite{}),
+		"concurrencyIssue":    reflect.TypeOf(types.ConcurrencyIssue{}),
+		"taintPath":           reflect.TypeOf(types.TaintPath{}),
+		"taintEndpoint":       reflect.TypeOf(types.TaintEndpoint{}),
+		"flowStep":            reflect.TypeOf(types.FlowStep{}),
+		"extract":             reflect.TypeOf(types.Extract{}),
+		"options":             reflect.TypeOf(types.Options{}),
+		"metadata":            reflect.TypeOf(types.Metadata{}),
+		"timings":             reflect.TypeOf(types.Timings{}),
+		"budgetReport":        reflect.TypeOf(types.BudgetReport{}),
+	}
+
+	// Then: Every JSON field of every type should appear in its definition
+	for name, typ := range defs {
+		def, ok := schema.Defs[name]
+		require.True(t, ok, "schema is missing $defs/%s", name)
+		props, _ := def["properties"].(map[string]any)
+
+		for i := 0; i < typ.NumField(); i++ {
+			tag := typ.Field(i).Tag.Get("json")
+			field, _, _ := strings.Cut(tag, ",")
+			if field == "" || field == "-" {
+				continue
+			}
+			assert.Contains(t, props, field, "schema $defs/%s is missing %q", name, field)
+		}
+	}
+
+	for i := 0; i < reflect.TypeOf(types.Result{}).NumField(); i++ {
+		field, _, _ := strings.Cut(reflect.TypeOf(types.Result{}).Field(i).Tag.Get("json"), ",")
+		assert.Contains(t, schema.Properties, field)
+	}
+}
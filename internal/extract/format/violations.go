@@ -0,0 +1,33 @@
+package format
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+)
+
+// ViolationsText renders architecture violations one per block, compiler
+// style ("file:line: ..."), with the reference chain indented below
+func ViolationsText(violations []types.Violation, root string) string {
+	var b strings.Builder
+
+	for _, v := range violations {
+		b.WriteString(fmt.Sprintf("%s: %s may not depend on %s: %s → %s\n",
+			relativePos(root, v.From.File, v.From.Line), v.FromLayer, v.ToLayer, v.From.ID(), v.To.ID()))
+		for _, ref := range v.Chain {
+			b.WriteString(fmt.Sprintf("    %s → %s (%s, %s)\n",
+				ref.ReferencedByID, ref.Symbol.ID(), ref.Reason, relativePos(root, ref.Symbol.File, ref.Symbol.Line)))
+		}
+	}
+
+	switch len(violations) {
+	case 0:
+		b.WriteString("No architecture violations.\n")
+	case 1:
+		b.WriteString("1 architecture violation\n")
+	default:
+		b.WriteString(fmt.Sprintf("%d architecture violations\n", len(violations)))
+	}
+	return b.String()
+}
@@ -0,0 +1,42 @@
+package format
+
+import (
+	"testing"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestViolationsText tests compiler-style violation output with reference chains
+func TestViolationsText(t *testing.T) {
+	// Given: A domain symbol reaching an adapter through a helper
+	violations := []types.Violation{{
+		From:      types.Symbol{Name: "Load", Package: "example.com/app/domain", File: "/src/app/domain/load.go", Line: 10},
+		FromLayer: "domain",
+		To:        types.Symbol{Name: "DB", Package: "example.com/app/adapters/sql", File: "/src/app/adapters/sql/db.go", Line: 5},
+		ToLayer:   "adapters",
+		Chain: []types.Reference{
+			{
+				Symbol:         types.Symbol{Name: "Open", Package: "example.com/app/util", File: "/src/app/util/open.go", Line: 3},
+				ReferencedByID: "example.com/app/domain.Load",
+				Reason:         "direct-call",
+			},
+			{
+				Symbol:         types.Symbol{Name: "DB", Package: "example.com/app/adapters/sql", File: "/src/app/adapters/sql/db.go", Line: 5},
+				ReferencedByID: "example.com/app/util.Open",
+				Reason:         "direct-call",
+			},
+		},
+	}}
+
+	// When: We render them
+	result := ViolationsText(violations, "/src/app")
+
+	// Then: The violation line is followed by each hop and a summary
+	assert.Contains(t, result, "domain/load.go:10: domain may not depend on adapters: example.com/app/domain.Load → example.com/app/adapters/sql.DB\n")
+	assert.Contains(t, result, "    example.com/app/domain.Load → example.com/app/util.Open (direct-call, util/open.go:3)\n")
+	assert.Contains(t, result, "    example.com/app/util.Open → example.com/app/adapters/sql.DB (direct-call, adapters/sql/db.go:5)\n")
+	assert.Contains(t, result, "1 architecture violation\n")
+
+	assert.Equal(t, "No architecture violations.\n", ViolationsText(nil, ""))
+}
@@ -0,0 +1,95 @@
+package extract
+
+import (
+	gotypes "go/types"
+	"strings"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+)
+
+// FindGaps reports the module's concrete types that nearly implement an
+// interface of the extract: they have most of its methods by name, but lack
+// others or declare them with different signatures. Generic types are
+// skipped, as their signatures only settle on instantiation.
+func (ia *InterfaceAnalyzer) FindGaps(symbols []types.Symbol, mappings []types.InterfaceMapping) []types.InterfaceGap {
+	if ia.index == nil {
+		return nil
+	}
+
+	interfaces := ia.findInterfaces(symbols)
+	for _, mapping := range mappings {
+		interfaces = append(interfaces, mapping.Interface)
+	}
+
+	var gaps []types.InterfaceGap
+	seen := make(map[string]bool)
+	for _, iface := range interfaces {
+		if seen[iface.ID()] {
+			continue
+		}
+		seen[iface.ID()] = true
+
+		ifaceName := ia.index.byID[iface.ID()]
+		if ifaceName == nil || !isMethodInterface(ifaceName) || isGeneric(ifaceName) {
+			continue
+		}
+		ifaceType := ifaceName.Type().Underlying().(*gotypes.Interface)
+
+		for _, concrete := range ia.index.concretes {
+			if isGeneric(concrete) {
+				continue
+			}
+			// Method sets of *T include those of T
+			if missing, _ := gotypes.MissingMethod(gotypes.NewPointer(concrete.Type()), ifaceType, true); missing == nil {
+				continue
+			}
+			if missing := methodGaps(concrete, ifaceType); len(missing) > 0 {
+				gaps = append(gaps, types.InterfaceGap{
+					Interface: ia.index.symbols[ifaceName],
+					Type:      ia.index.symbols[concrete],
+					Missing:   missing,
+				})
+			}
+		}
+	}
+
+	return gaps
+}
+
+// methodGaps lists the interface methods a type lacks or declares with a
+// different signature. It returns nil unless the type has a majority of the
+// methods by name, so unrelated types, and types implementing only a smaller
+// interface embedded in this one, are not reported.
+func methodGaps(concrete *gotypes.TypeName, iface *gotypes.Interface) []types.MethodGap {
+	ptr := gotypes.NewPointer(concrete.Type())
+
+	var gaps []types.MethodGap
+	named := 0
+	for i := 0; i < iface.NumMethods(); i++ {
+		want := iface.Method(i)
+		obj, _, _ := gotypes.LookupFieldOrMethod(ptr, false, want.Pkg(), want.Name())
+		got, ok := obj.(*gotypes.Func)
+
+		switch {
+		case !ok:
+			gaps = append(gaps, types.MethodGap{Method: methodString(want), Reason: "missing"})
+		case !gotypes.Identical(got.Type(), want.Type()):
+			named++
+			gaps = append(gaps, types.MethodGap{Method: methodString(want), Reason: "wrong-signature", Found: methodString(got)})
+		default:
+			named++
+		}
+	}
+
+	if named*2 <= iface.NumMethods() {
+		return nil
+	}
+	return gaps
+}
+
+// methodString formats a method as "Name(params) results", qualifying types
+// by package name
+func methodString(fn *gotypes.Func) string {
+	sig := gotypes.TypeString(fn.Type(), func(pkg *gotypes.Package) string { return pkg.Name() })
+	return fn.Name() + strings.TrimPrefix(sig, "func")
+}
@@ -0,0 +1,54 @@
+package extract
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// extractStoreInterface extracts the interface declared at line in the key-value example's store package
+func extractStoreInterface(t *testing.T, line int) types.Extract {
+	t.Helper()
+	root := filepath.Join("..", "..", "examples", "ex3")
+	target := types.Target{Root: root, File: filepath.Join(root, "pkg", "store", "store.go"), Line: line, Column: 6}
+
+	result, err := ExtractSymbol(context.Background(), target, types.Options{Depth: 0})
+	require.NoError(t, err)
+	return result.Extract
+}
+
+// TestFindGapsMissingMethod tests reporting methods a nearly complete type lacks
+func TestFindGapsMissingMethod(t *testing.T) {
+	// When: We extract Store, which the map and disk stores fall short of
+	ext := extractStoreInterface(t, 20)
+
+	// Then: Both are reported; the disk store also has a mismatched Get
+	require.Len(t, ext.InterfaceGaps, 2)
+
+	mapGap := ext.InterfaceGaps[0]
+	assert.Equal(t, "example.com/ex3/pkg/store.Store", mapGap.Interface.ID())
+	assert.Equal(t, "example.com/ex3/pkg/memory.Map", mapGap.Type.ID())
+	assert.Equal(t, []types.MethodGap{{Method: "Delete(key string)", Reason: "missing"}}, mapGap.Missing)
+
+	diskGap := ext.InterfaceGaps[1]
+	assert.Equal(t, "example.com/ex3/pkg/disk.Disk", diskGap.Type.ID())
+	assert.Contains(t, diskGap.Missing, types.MethodGap{
+		Method: "Get(key string) (string, bool)",
+		Reason: "wrong-signature",
+		Found:  "Get(key string) ([]byte, error)",
+	})
+}
+
+// TestFindGapsIgnoresSmallerInterfaces tests that implementing an embedded interface is not a near miss
+func TestFindGapsIgnoresSmallerInterfaces(t *testing.T) {
+	// When: We extract ReadWriter, which Static and ReadOnly implement only the Reader half of
+	ext := extractStoreInterface(t, 14)
+
+	// Then: Only the disk store, which has both methods by name, is reported
+	require.Len(t, ext.InterfaceGaps, 1)
+	assert.Equal(t, "example.com/ex3/pkg/disk.Disk", ext.InterfaceGaps[0].Type.ID())
+}
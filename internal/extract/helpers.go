@@ -2,7 +2,9 @@ package extract
 
 import (
 	"go/token"
-	"go/types"
+	gotypes "go/types"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
 )
 
 // symbolKey uniquely identifies a symbol for deduplication
@@ -15,8 +17,46 @@ type symbolKey struct {
 // visitedSet tracks visited symbols during traversal
 type visitedSet map[symbolKey]bool
 
-// objectInfo wraps types.Object with additional metadata
+// objectInfo wraps gotypes.Object with additional metadata
 type objectInfo struct {
-	obj   types.Object
+	obj   gotypes.Object
 	depth int
 }
+
+// objectID returns the identity of obj in the same form as types.Symbol.ID
+func objectID(obj gotypes.Object) string {
+	sym := types.Symbol{
+		Name:     obj.Name(),
+		Receiver: receiverName(obj),
+	}
+	if obj.Pkg() != nil {
+		sym.Package = obj.Pkg().Path()
+	}
+	return sym.ID()
+}
+
+// receiverName returns the receiver type of a method ("*Handler"), or "" for other objects
+func receiverName(obj gotypes.Object) string {
+	fn, ok := obj.(*gotypes.Func)
+	if !ok {
+		return ""
+	}
+
+	sig, ok := fn.Type().(*gotypes.Signature)
+	if !ok || sig.Recv() == nil {
+		return ""
+	}
+
+	recv := sig.Recv().Type()
+	prefix := ""
+	if ptr, ok := recv.(*gotypes.Pointer); ok {
+		prefix = "*"
+		recv = ptr.Elem()
+	}
+
+	if named, ok := recv.(*gotypes.Named); ok {
+		return prefix + named.Obj().Name()
+	}
+
+	return ""
+}
@@ -0,0 +1,157 @@
+package extract
+
+import (
+	"go/ast"
+	"go/token"
+	gotypes "go/types"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+	"golang.org/x/tools/go/packages"
+)
+
+// ImplementationIndex maps every interface declared in the module to the
+// module's concrete types that satisfy it, and back. It is built once per
+// package load, so lookups do not depend on what an extraction reached.
+type ImplementationIndex struct {
+	symbols         map[*gotypes.TypeName]types.Symbol
+	byID            map[string]*gotypes.TypeName
+	concretes       []*gotypes.TypeName                          // Non-interface types, in declaration order
+	implementations map[*gotypes.TypeName][]types.Implementation // Interface → concrete types
+	interfaces      map[*gotypes.TypeName][]types.Implementation // Concrete type → interfaces
+}
+
+// NewImplementationIndex indexes the type declarations of the loaded packages
+func NewImplementationIndex(pkgs []*packages.Package, fset *token.FileSet) *ImplementationIndex {
+	ix := &ImplementationIndex{
+		symbols:         make(map[*gotypes.TypeName]types.Symbol),
+		byID:            make(map[string]*gotypes.TypeName),
+		implementations: make(map[*gotypes.TypeName][]types.Implementation),
+		interfaces:      make(map[*gotypes.TypeName][]types.Implementation),
+	}
+
+	locator := &Locator{fset: fset, pkgs: pkgs}
+	var ifaces, concretes []*gotypes.TypeName
+	for _, pkg := range pkgs {
+		for _, astFile := range pkg.Syntax {
+			for _, decl := range astFile.Decls {
+				genDecl, ok := decl.(*ast.GenDecl)
+				if !ok || genDecl.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range genDecl.Specs {
+					typeSpec := spec.(*ast.TypeSpec)
+					typeName, ok := pkg.TypesInfo.Defs[typeSpec.Name].(*gotypes.TypeName)
+					if !ok || typeName.IsAlias() {
+						continue
+					}
+
+					doc := typeSpec.Doc
+					if doc == nil {
+						doc = genDecl.Doc
+					}
+					sym, _ := locator.extractFromTypeSpec(pkg, astFile, typeSpec, doc)
+					ix.symbols[typeName] = *sym
+					ix.byID[sym.ID()] = typeName
+
+					if isMethodInterface(typeName) {
+						ifaces = append(ifaces, typeName)
+					} else if !gotypes.IsInterface(typeName.Type()) {
+						concretes = append(concretes, typeName)
+					}
+				}
+			}
+		}
+	}
+
+	ix.concretes = concretes
+	for _, iface := range ifaces {
+		for _, concrete := range concretes {
+			ok, pointer := satisfies(concrete, iface)
+			if !ok {
+				continue
+			}
+			impl := types.Implementation{
+				Interface: ix.symbols[iface],
+				Type:      ix.symbols[concrete],
+				Pointer:   pointer,
+			}
+			ix.implementations[iface] = append(ix.implementations[iface], impl)
+			ix.interfaces[concrete] = append(ix.interfaces[concrete], impl)
+		}
+	}
+
+	return ix
+}
+
+// Implementations returns the concrete types satisfying an interface symbol
+func (ix *ImplementationIndex) Implementations(iface types.Symbol) []types.Implementation {
+	return ix.implementations[ix.byID[iface.ID()]]
+}
+
+// Interfaces returns the module interfaces a concrete type symbol satisfies
+func (ix *ImplementationIndex) Interfaces(concrete types.Symbol) []types.Implementation {
+	return ix.interfaces[ix.byID[concrete.ID()]]
+}
+
+// implementationIndex returns the index of the loaded packages, building it
+// on first use
+func (l *Locator) implementationIndex() *ImplementationIndex {
+	if l.index == nil {
+		l.index = NewImplementationIndex(l.pkgs, l.fset)
+	}
+	return l.index
+}
+
+// isMethodInterface reports whether a type is an interface with methods that
+// can be implemented; empty interfaces and type-set constraints are skipped
+func isMethodInterface(typeName *gotypes.TypeName) bool {
+	iface, ok := typeName.Type().Underlying().(*gotypes.Interface)
+	return ok && iface.IsMethodSet() && iface.NumMethods() > 0
+}
+
+// satisfies reports whether a concrete type implements an interface, and
+// whether only its pointer type does. Methods promoted from embedded fields
+// and interfaces count. Generic types cannot be checked before instantiation,
+// so when either side has type parameters the methods are matched by name
+// and arity instead.
+func satisfies(concrete, iface *gotypes.TypeName) (ok, pointer bool) {
+	ifaceType := iface.Type().Underlying().(*gotypes.Interface)
+	typ := concrete.Type()
+
+	if !isGeneric(concrete) && !isGeneric(iface) {
+		if gotypes.Implements(typ, ifaceType) {
+			return true, false
+		}
+		return gotypes.Implements(gotypes.NewPointer(typ), ifaceType), true
+	}
+
+	if matchesMethods(gotypes.NewMethodSet(typ), ifaceType) {
+		return true, false
+	}
+	return matchesMethods(gotypes.NewMethodSet(gotypes.NewPointer(typ)), ifaceType), true
+}
+
+// isGeneric reports whether a named type has type parameters
+func isGeneric(typeName *gotypes.TypeName) bool {
+	named, ok := typeName.Type().(*gotypes.Named)
+	return ok && named.TypeParams().Len() > 0
+}
+
+// matchesMethods reports whether a method set has every interface method
+// with the same parameter and result counts
+func matchesMethods(methods *gotypes.MethodSet, iface *gotypes.Interface) bool {
+	for i := 0; i < iface.NumMethods(); i++ {
+		want := iface.Method(i)
+		sel := methods.Lookup(want.Pkg(), want.Name())
+		if sel == nil {
+			return false
+		}
+		got, wantSig := sel.Obj().Type().(*gotypes.Signature), want.Type().(*gotypes.Signature)
+		if got.Params().Len() != wantSig.Params().Len() ||
+			got.Results().Len() != wantSig.Results().Len() ||
+			got.Variadic() != wantSig.Variadic() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,101 @@
+package extract
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// implementationNames lists the concrete side of each implementation, starred for pointer receivers
+func implementationNames(impls []types.Implementation) []string {
+	var names []string
+	for _, impl := range impls {
+		name := impl.Type.Name
+		if impl.Pointer {
+			name = "*" + name
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// TestImplementationIndex tests pointer receivers, embedding and generics across packages
+func TestImplementationIndex(t *testing.T) {
+	// Given: The key-value example, with interfaces and implementations in separate packages
+	ws := loadExample(t, "ex3")
+
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{"store.Reader", []string{"*Map", "Static", "ReadOnly"}},
+		{"store.Writer", []string{"*Map", "*Disk"}},
+		{"store.ReadWriter", []string{"*Map"}},
+		{"store.Loader", []string{"*Cache"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			// When: We query the interface
+			iface, impls, err := ws.Implementations(tt.query)
+
+			// Then: Every implementing type is found
+			require.NoError(t, err)
+			assert.Equal(t, "interface", iface.Kind)
+			assert.Equal(t, tt.expected, implementationNames(impls))
+		})
+	}
+}
+
+// TestImplementationIndexReverse tests listing the interfaces a concrete type satisfies
+func TestImplementationIndexReverse(t *testing.T) {
+	// Given: The key-value example
+	ws := loadExample(t, "ex3")
+
+	// When: We query the map store
+	sym, impls, err := ws.Implementations("memory.Map")
+
+	// Then: It satisfies all three interfaces through its pointer
+	require.NoError(t, err)
+	assert.Equal(t, "struct", sym.Kind)
+	var ifaces []string
+	for _, impl := range impls {
+		assert.True(t, impl.Pointer)
+		assert.Equal(t, "Map", impl.Type.Name)
+		ifaces = append(ifaces, impl.Interface.Name)
+	}
+	assert.Equal(t, []string{"Reader", "Writer", "ReadWriter"}, ifaces)
+
+	// Functions are not types
+	_, _, err = ws.Implementations("store.Lookup")
+	assert.ErrorContains(t, err, "not a type")
+}
+
+// TestExtractFindsImplementationsOutsideScope tests that extraction maps interfaces module-wide
+func TestExtractFindsImplementationsOutsideScope(t *testing.T) {
+	// Given: The Reader interface, whose implementations it never references
+	root := filepath.Join("..", "..", "examples", "ex3")
+	target := types.Target{Root: root, File: filepath.Join(root, "pkg", "store", "store.go"), Line: 4, Column: 6}
+
+	// When: We extract it at depth 0
+	result, err := ExtractSymbol(context.Background(), target, types.Options{Depth: 0})
+	require.NoError(t, err)
+
+	// Then: Its implementations in the memory package are still mapped
+	require.Len(t, result.Extract.InterfaceMappings, 1)
+	mapping := result.Extract.InterfaceMappings[0]
+	assert.Equal(t, "Reader", mapping.Interface.Name)
+	var names []string
+	for _, impl := range mapping.Implementations {
+		names = append(names, impl.ID())
+	}
+	assert.Equal(t, []string{
+		"example.com/ex3/pkg/memory.Map",
+		"example.com/ex3/pkg/memory.Static",
+		"example.com/ex3/pkg/memory.ReadOnly",
+	}, names)
+}
@@ -11,8 +11,9 @@ import (
 
 // InterfaceAnalyzer finds interface-to-implementation mappings
 type InterfaceAnalyzer struct {
-	pkgs []*packages.Package
-	fset *token.FileSet
+	pkgs  []*packages.Package
+	fset  *token.FileSet
+	index *ImplementationIndex // Module-wide index; nil searches only the given symbols
 }
 
 // NewInterfaceAnalyzer creates a new interface analyzer
@@ -23,6 +24,13 @@ func NewInterfaceAnalyzer(pkgs []*packages.Package, fset *token.FileSet) *Interf
 	}
 }
 
+// SetIndex makes the analyzer look up implementations and implemented
+// interfaces across the whole module rather than only among the symbols
+// passed to AnalyzeInterfaces
+func (ia *InterfaceAnalyzer) SetIndex(index *ImplementationIndex) {
+	ia.index = index
+}
+
 // AnalyzeInterfaces finds all interface-implementation relationships
 func (ia *InterfaceAnalyzer) AnalyzeInterfaces(symbols []types.Symbol) []types.InterfaceMapping {
 	var mappings []types.InterfaceMapping
@@ -85,6 +93,15 @@ func (ia *InterfaceAnalyzer) findInterfaces(symbols []types.Symbol) []types.Symb
 func (ia *InterfaceAnalyzer) discoverInterfacesFromStructs(symbols []types.Symbol) []types.Symbol {
 	var discovered []types.Symbol
 
+	if ia.index != nil {
+		for _, sym := range symbols {
+			for _, impl := range ia.index.Interfaces(sym) {
+				discovered = append(discovered, impl.Interface)
+			}
+		}
+		return discovered
+	}
+
 	for _, sym := range symbols {
 		if sym.Kind != "struct" {
 			continue
@@ -221,6 +238,13 @@ func (ia *InterfaceAnalyzer) extractCode(start, end token.Pos) string {
 func (ia *InterfaceAnalyzer) findImplementations(iface types.Symbol, symbols []types.Symbol) []types.Symbol {
 	var implementations []types.Symbol
 
+	if ia.index != nil {
+		for _, impl := range ia.index.Implementations(iface) {
+			implementations = append(implementations, impl.Type)
+		}
+		return implementations
+	}
+
 	// Get the interface type
 	ifaceObj := ia.findObjectBySymbol(iface)
 	if ifaceObj == nil {
@@ -259,128 +283,295 @@ func (ia *InterfaceAnalyzer) implementsInterface(structSym types.Symbol, iface *
 		gotypes.Implements(gotypes.NewPointer(structType), iface)
 }
 
-// findConstructor finds a constructor function that returns the interface
+// findConstructor finds a constructor of the interface among the symbols: a
+// function, generic function or factory method whose first result is the
+// interface, an interface embedding it or a type implementing it, optionally
+// followed by an error or cleanup function. Names like New<Interface> are
+// preferred, then constructors returning the interface itself.
 func (ia *InterfaceAnalyzer) findConstructor(iface types.Symbol, symbols []types.Symbol) *types.Symbol {
-	// Common constructor patterns: New<InterfaceName>, New<Name>Service, etc.
+	ifaceName, ok := ia.findObjectBySymbol(iface).(*gotypes.TypeName)
+	if !ok || !gotypes.IsInterface(ifaceName.Type()) {
+		return nil
+	}
+
+	// Common constructor names rank above other functions of the right shape
 	constructorPatterns := []string{
 		"New" + iface.Name,
 		"Create" + iface.Name,
 		"Make" + iface.Name,
 	}
 
-	for _, sym := range symbols {
-		if sym.Kind != "func" {
+	var found *types.Symbol
+	bestRank := 0
+	for i, sym := range symbols {
+		if sym.Kind != "func" && sym.Kind != "method" {
 			continue
 		}
 
-		// Check if name matches pattern
-		matches := false
+		funcObj, ok := ia.findObjectBySymbol(sym).(*gotypes.Func)
+		if !ok {
+			continue
+		}
+		result := constructedType(funcObj.Type().(*gotypes.Signature))
+		if result == nil {
+			continue
+		}
+
+		rank := 0
+		if named := namedOrigin(result); named != nil && named.Obj() == ifaceName {
+			rank = 2
+		} else if returnsImplementation(result, ifaceName) {
+			rank = 1
+		}
+		if rank == 0 {
+			continue
+		}
 		for _, pattern := range constructorPatterns {
 			if sym.Name == pattern {
-				matches = true
-				break
+				rank += 2
 			}
 		}
 
-		if !matches {
-			continue
+		if rank > bestRank {
+			found = &symbols[i]
+			bestRank = rank
 		}
+	}
+
+	if found == nil {
+		return nil
+	}
+
+	symCopy := *found
+	symCopy.InterfaceType = iface.Name
+
+	// Find what concrete type it instantiates
+	impl := ia.findConstructorImplementation(&symCopy)
+	if impl != "" {
+		symCopy.Implementation = impl
+	}
+
+	return &symCopy
+}
 
-		// Check if function returns the interface type
-		funcObj := ia.findObjectBySymbol(sym)
-		if funcObj == nil {
+// constructedType returns the type a function constructs: its first result,
+// when every other result is an error or a cleanup function
+func constructedType(sig *gotypes.Signature) gotypes.Type {
+	results := sig.Results()
+	if results.Len() == 0 {
+		return nil
+	}
+	for i := 1; i < results.Len(); i++ {
+		typ := results.At(i).Type()
+		if typ.String() == "error" {
 			continue
 		}
-
-		funcType, ok := funcObj.Type().(*gotypes.Signature)
-		if !ok {
+		if cleanup, ok := typ.Underlying().(*gotypes.Signature); ok && cleanup.Params().Len() == 0 && cleanup.Results().Len() == 0 {
 			continue
 		}
+		return nil
+	}
+	return results.At(0).Type()
+}
 
-		// Check return type
-		results := funcType.Results()
-		if results.Len() == 0 {
-			continue
+// returnsImplementation reports whether a constructor result satisfies the
+// interface: a concrete type, or a pointer to one, implementing it, or an
+// interface embedding it
+func returnsImplementation(result gotypes.Type, iface *gotypes.TypeName) bool {
+	ptr, isPointer := result.(*gotypes.Pointer)
+	if isPointer {
+		result = ptr.Elem()
+	}
+	named := namedOrigin(result)
+	if named == nil {
+		return false
+	}
+
+	ok, pointer := satisfies(named.Obj(), iface)
+	return ok && (isPointer || !pointer)
+}
+
+// namedOrigin returns the generic declaration of a named type, or the
+// named type itself
+func namedOrigin(typ gotypes.Type) *gotypes.Named {
+	named, ok := typ.(*gotypes.Named)
+	if !ok {
+		return nil
+	}
+	return named.Origin()
+}
+
+// findConstructorImplementation finds the concrete type a constructor
+// returns, from type information: its declared result when concrete, or else
+// the values its return statements yield, following local variables to what
+// is assigned to them and calls to the functions that build them. The type is
+// recorded by name, qualified when declared in another package ("memory.Map").
+func (ia *InterfaceAnalyzer) findConstructorImplementation(constructor *types.Symbol) string {
+	if typeName := ia.constructorType(*constructor); typeName != nil {
+		constructor.Implementation = typeName.Name()
+		if typeName.Pkg() != nil && typeName.Pkg().Path() != constructor.Package {
+			constructor.Implementation = typeName.Pkg().Name() + "." + typeName.Name()
 		}
+	}
+
+	return constructor.Implementation
+}
 
-		// Get first return type
-		firstReturn := results.At(0).Type()
+// constructorType returns the declaration of the concrete type a
+// constructor returns, or nil when unknown
+func (ia *InterfaceAnalyzer) constructorType(constructor types.Symbol) *gotypes.TypeName {
+	funcObj, ok := ia.findObjectBySymbol(constructor).(*gotypes.Func)
+	if !ok {
+		return nil
+	}
+	return ia.constructedConcrete(funcObj, make(map[*gotypes.Func]bool))
+}
 
-		// Check if it's a named type matching the interface
-		if named, ok := firstReturn.(*gotypes.Named); ok {
-			if named.Obj().Name() == iface.Name && named.Obj().Pkg().Path() == iface.Package {
-				// Found constructor!
-				symCopy := sym
-				symCopy.InterfaceType = iface.Name
+// constructedConcrete returns the concrete type a function constructs, or
+// nil when unknown. Seen guards against constructors calling each other.
+func (ia *InterfaceAnalyzer) constructedConcrete(funcObj *gotypes.Func, seen map[*gotypes.Func]bool) *gotypes.TypeName {
+	funcObj = funcObj.Origin()
+	if seen[funcObj] {
+		return nil
+	}
+	seen[funcObj] = true
 
-				// Try to find what concrete type it instantiates
-				impl := ia.findConstructorImplementation(&symCopy)
-				if impl != "" {
-					symCopy.Implementation = impl
-				}
+	result := constructedType(funcObj.Type().(*gotypes.Signature))
+	if result == nil {
+		return nil
+	}
+	if typeName := concreteTypeName(result); typeName != nil {
+		return typeName
+	}
+
+	pkg, decl := ia.funcDecl(funcObj)
+	if decl == nil || decl.Body == nil {
+		return nil
+	}
 
-				return &symCopy
+	var found *gotypes.TypeName
+	ast.Inspect(decl.Body, func(n ast.Node) bool {
+		if found != nil {
+			return false
+		}
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false // Its returns are not the constructor's
+		case *ast.ReturnStmt:
+			if len(node.Results) > 0 {
+				found = ia.exprConcrete(pkg, decl.Body, node.Results[0], seen)
 			}
 		}
+		return true
+	})
+	return found
+}
+
+// exprConcrete returns the concrete type of the value an expression yields
+// within a function body
+func (ia *InterfaceAnalyzer) exprConcrete(pkg *packages.Package, body *ast.BlockStmt, expr ast.Expr, seen map[*gotypes.Func]bool) *gotypes.TypeName {
+	expr = ast.Unparen(expr)
+	if typeName := concreteTypeName(pkg.TypesInfo.TypeOf(expr)); typeName != nil {
+		return typeName
 	}
 
+	switch e := expr.(type) {
+	case *ast.CallExpr:
+		// A constructor returning another constructor's result
+		if callee, ok := calleeObject(pkg, e.Fun).(*gotypes.Func); ok {
+			return ia.constructedConcrete(callee, seen)
+		}
+
+	case *ast.Ident:
+		// A local variable: the values assigned to it
+		local, ok := pkg.TypesInfo.Uses[e].(*gotypes.Var)
+		if !ok || local.Pos() < body.Pos() || local.Pos() > body.End() {
+			return nil
+		}
+		var found *gotypes.TypeName
+		ast.Inspect(body, func(n ast.Node) bool {
+			if found != nil {
+				return false
+			}
+			var lhs, rhs []ast.Expr
+			switch node := n.(type) {
+			case *ast.AssignStmt:
+				lhs, rhs = node.Lhs, node.Rhs
+			case *ast.ValueSpec:
+				for _, name := range node.Names {
+					lhs = append(lhs, name)
+				}
+				rhs = node.Values
+			default:
+				return true
+			}
+			if len(lhs) != len(rhs) {
+				return true
+			}
+			for i, target := range lhs {
+				ident, ok := target.(*ast.Ident)
+				if !ok || (pkg.TypesInfo.Defs[ident] != local && pkg.TypesInfo.Uses[ident] != local) {
+					continue
+				}
+				if value, ok := ast.Unparen(rhs[i]).(*ast.Ident); ok && pkg.TypesInfo.Uses[value] == local {
+					continue
+				}
+				if typeName := ia.exprConcrete(pkg, body, rhs[i], seen); typeName != nil {
+					found = typeName
+				}
+			}
+			return true
+		})
+		return found
+	}
 	return nil
 }
 
-// findConstructorImplementation analyzes constructor body to find concrete type
-func (ia *InterfaceAnalyzer) findConstructorImplementation(constructor *types.Symbol) string {
-	// Find the function AST node
+// concreteTypeName returns the declaration of a concrete named type or a
+// pointer to one, or nil for interfaces and unnamed types
+func concreteTypeName(typ gotypes.Type) *gotypes.TypeName {
+	if ptr, ok := typ.(*gotypes.Pointer); ok {
+		typ = ptr.Elem()
+	}
+	named := namedOrigin(typ)
+	if named == nil || gotypes.IsInterface(named) {
+		return nil
+	}
+	return named.Obj()
+}
+
+// calleeObject returns the object a called expression refers to, through
+// selectors and generic instantiations
+func calleeObject(pkg *packages.Package, fun ast.Expr) gotypes.Object {
+	switch e := ast.Unparen(fun).(type) {
+	case *ast.Ident:
+		return pkg.TypesInfo.Uses[e]
+	case *ast.SelectorExpr:
+		return pkg.TypesInfo.Uses[e.Sel]
+	case *ast.IndexExpr:
+		return calleeObject(pkg, e.X)
+	case *ast.IndexListExpr:
+		return calleeObject(pkg, e.X)
+	}
+	return nil
+}
+
+// funcDecl finds the declaration of a function or method of the loaded
+// packages
+func (ia *InterfaceAnalyzer) funcDecl(funcObj *gotypes.Func) (*packages.Package, *ast.FuncDecl) {
 	for _, pkg := range ia.pkgs {
-		if pkg.PkgPath != constructor.Package {
+		if funcObj.Pkg() == nil || pkg.PkgPath != funcObj.Pkg().Path() {
 			continue
 		}
-
 		for _, astFile := range pkg.Syntax {
-			ast.Inspect(astFile, func(n ast.Node) bool {
-				fn, ok := n.(*ast.FuncDecl)
-				if !ok || fn.Name.Name != constructor.Name {
-					return true
+			for _, decl := range astFile.Decls {
+				if fd, ok := decl.(*ast.FuncDecl); ok && pkg.TypesInfo.Defs[fd.Name] == funcObj {
+					return pkg, fd
 				}
-
-				// Look for return statements
-				ast.Inspect(fn.Body, func(ret ast.Node) bool {
-					retStmt, ok := ret.(*ast.ReturnStmt)
-					if !ok {
-						return true
-					}
-
-					for _, expr := range retStmt.Results {
-						// Look for &TypeName{...} pattern
-						if unary, ok := expr.(*ast.UnaryExpr); ok && unary.Op == token.AND {
-							if comp, ok := unary.X.(*ast.CompositeLit); ok {
-								if ident, ok := comp.Type.(*ast.Ident); ok {
-									constructor.Implementation = ident.Name
-									return false
-								}
-								if sel, ok := comp.Type.(*ast.SelectorExpr); ok {
-									constructor.Implementation = sel.Sel.Name
-									return false
-								}
-							}
-						}
-
-						// Look for TypeName{...} pattern
-						if comp, ok := expr.(*ast.CompositeLit); ok {
-							if ident, ok := comp.Type.(*ast.Ident); ok {
-								constructor.Implementation = ident.Name
-								return false
-							}
-						}
-					}
-					return true
-				})
-
-				return false
-			})
+			}
 		}
 	}
-
-	return constructor.Implementation
+	return nil, nil
 }
 
 // detectConstructorFramework detects if constructor uses a DI framework
@@ -440,13 +631,17 @@ func (ia *InterfaceAnalyzer) findObjectBySymbol(sym types.Symbol) gotypes.Object
 
 				switch node := n.(type) {
 				case *ast.TypeSpec:
-					if node.Name.Name == sym.Name {
+					if node.Name.Name == sym.Name && isTypeKind(sym.Kind) {
 						foundObj = pkg.TypesInfo.Defs[node.Name]
 						return false
 					}
 				case *ast.FuncDecl:
-					if node.Name.Name == sym.Name {
-						foundObj = pkg.TypesInfo.Defs[node.Name]
+					if node.Name.Name == sym.Name && (node.Recv != nil) == (sym.Kind == "method") {
+						obj := pkg.TypesInfo.Defs[node.Name]
+						if sym.Kind == "method" && types.ReceiverBase(receiverName(obj)) != types.ReceiverBase(sym.Receiver) {
+							return false
+						}
+						foundObj = obj
 						return false
 					}
 				}
@@ -462,6 +657,11 @@ func (ia *InterfaceAnalyzer) findObjectBySymbol(sym types.Symbol) gotypes.Object
 	return nil
 }
 
+// isTypeKind reports whether a symbol kind names a type declaration
+func isTypeKind(kind string) bool {
+	return kind == "type" || kind == "interface" || kind == "struct"
+}
+
 // ExtractInterfaceReferences creates Reference entries for interface relationships
 func ExtractInterfaceReferences(mappings []types.InterfaceMapping, depth int) []types.Reference {
 	var refs []types.Reference
@@ -470,21 +670,23 @@ func ExtractInterfaceReferences(mappings []types.InterfaceMapping, depth int) []
 		// Add references for each implementation
 		for _, impl := range mapping.Implementations {
 			ref := types.Reference{
-				Symbol:       impl,
-				Reason:       "implements-interface",
-				Depth:        depth,
-				External:     false,
-				ReferencedBy: mapping.Interface.Name,
+				Symbol:         impl,
+				Reason:         "implements-interface",
+				Depth:          depth,
+				External:       false,
+				ReferencedBy:   mapping.Interface.Name,
+				ReferencedByID: mapping.Interface.ID(),
 			}
 			refs = append(refs, ref)
 
 			// Add reverse reference from implementation to interface
 			ifaceRef := types.Reference{
-				Symbol:       mapping.Interface,
-				Reason:       "interface-contract",
-				Depth:        depth,
-				External:     false,
-				ReferencedBy: impl.Name,
+				Symbol:         mapping.Interface,
+				Reason:         "interface-contract",
+				Depth:          depth,
+				External:       false,
+				ReferencedBy:   impl.Name,
+				ReferencedByID: impl.ID(),
 			}
 			refs = append(refs, ifaceRef)
 		}
@@ -492,11 +694,12 @@ func ExtractInterfaceReferences(mappings []types.InterfaceMapping, depth int) []
 		// Add reference for constructor if present
 		if mapping.Constructor != nil {
 			constructorRef := types.Reference{
-				Symbol:       *mapping.Constructor,
-				Reason:       "returns-interface",
-				Depth:        depth,
-				External:     false,
-				ReferencedBy: mapping.Interface.Name,
+				Symbol:         *mapping.Constructor,
+				Reason:         "returns-interface",
+				Depth:          depth,
+				External:       false,
+				ReferencedBy:   mapping.Interface.Name,
+				ReferencedByID: mapping.Interface.ID(),
 			}
 			refs = append(refs, constructorRef)
 		}
@@ -1,10 +1,15 @@
 package extract
 
 import (
+	"go/ast"
+	"go/parser"
 	"go/token"
+	gotypes "go/types"
 	"testing"
 
 	"github.com/extract-scope-go/go-scope/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"golang.org/x/tools/go/packages"
 )
 
@@ -204,3 +209,82 @@ func TestFindConstructorPattern(t *testing.T) {
 		})
 	}
 }
+
+// TestFindConstructorBySignature tests constructors found by what they return rather than their name
+func TestFindConstructorBySignature(t *testing.T) {
+	// Given: The interface example, with functional options, a factory method and a generic constructor
+	ws := loadExample(t, "ex3")
+	analyzer := NewInterfaceAnalyzer(ws.locator.pkgs, ws.locator.fset)
+	symbols := ws.Symbols()
+
+	tests := []struct {
+		iface          string
+		constructor    string
+		implementation string
+	}{
+		{"store.ReadWriter", "Open", "Map"},   // Options, returned through a local variable with an error
+		{"store.Reader", "Reader", "Static"},  // Factory method assigning an interface variable
+		{"store.Loader", "NewCache", "Cache"}, // Generic constructor returning a generic implementation
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.iface, func(t *testing.T) {
+			iface, err := ws.Resolve(tt.iface)
+			require.NoError(t, err)
+
+			// When: We look for its constructor
+			constructor := analyzer.findConstructor(iface, symbols)
+
+			// Then: The constructor and the concrete type it returns are found
+			require.NotNil(t, constructor)
+			assert.Equal(t, tt.constructor, constructor.Name)
+			assert.Equal(t, iface.Name, constructor.InterfaceType)
+			assert.Equal(t, tt.implementation, constructor.Implementation)
+		})
+	}
+}
+
+// TestFindConstructorImplementationQualified tests following a constructor call into another package
+func TestFindConstructorImplementationQualified(t *testing.T) {
+	// Given: A function in main returning the Map that memory.Open builds
+	ws := loadExample(t, "ex3")
+	analyzer := NewInterfaceAnalyzer(ws.locator.pkgs, ws.locator.fset)
+	constructor := types.Symbol{Package: "example.com/ex3/cmd/kv", Name: "openStore", Kind: "func"}
+
+	// When: We find the concrete type it returns
+	impl := analyzer.findConstructorImplementation(&constructor)
+
+	// Then: The type is qualified by its package
+	assert.Equal(t, "memory.Map", impl)
+
+	// And: Functions constructing nothing have no implementation
+	assert.Nil(t, analyzer.constructorType(types.Symbol{Package: "example.com/ex3/pkg/store", Name: "Lookup", Kind: "func"}))
+}
+
+// TestFindObjectBySymbolKind tests telling a method from a type of the same name
+func TestFindObjectBySymbolKind(t *testing.T) {
+	// Given: A package declaring type Reader and a Factory.Reader method
+	src := `package p
+
+type Reader interface{ Read() string }
+
+type Factory struct{}
+
+func (Factory) Reader() Reader { return nil }
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "p.go", src, 0)
+	require.NoError(t, err)
+	info := &gotypes.Info{Defs: make(map[*ast.Ident]gotypes.Object)}
+	_, err = (&gotypes.Config{}).Check("p", fset, []*ast.File{file}, info)
+	require.NoError(t, err)
+	analyzer := NewInterfaceAnalyzer([]*packages.Package{{PkgPath: "p", Syntax: []*ast.File{file}, TypesInfo: info}}, fset)
+
+	// When: We look up each by its kind
+	method := analyzer.findObjectBySymbol(types.Symbol{Package: "p", Name: "Reader", Kind: "method", Receiver: "Factory"})
+	iface := analyzer.findObjectBySymbol(types.Symbol{Package: "p", Name: "Reader", Kind: "interface"})
+
+	// Then: The method resolves to the function, the interface to the type
+	assert.IsType(t, &gotypes.Func{}, method)
+	assert.IsType(t, &gotypes.TypeName{}, iface)
+}
@@ -8,15 +8,23 @@ import (
 	gotypes "go/types"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/extract-scope-go/go-scope/internal/extract/di"
 	"github.com/extract-scope-go/go-scope/internal/types"
 	"golang.org/x/tools/go/packages"
 )
 
 // Locator finds symbols at specific positions in Go source files
 type Locator struct {
-	fset *token.FileSet
-	pkgs []*packages.Package
+	fset            *token.FileSet
+	pkgs            []*packages.Package
+	index           *ImplementationIndex // Built on first use, see implementationIndex
+	roles           *RoleClassifier      // Built on first use, see roleClassifier
+	detector        *di.Detector         // Built on first use, see diDetector
+	detectorWarning string               // Why the rules file's DI registries are missing, if they are
+	vcs             *vcsInfo             // Read on first use, see vcsState
+	flow            *flowIndex           // Built on first use, see flowIndex
 }
 
 // NewLocator creates a new Locator instance
@@ -33,6 +41,11 @@ func (l *Locator) LocateSymbol(root, file string, line, col int) (*types.Symbol,
 		return nil, fmt.Errorf("failed to load packages: %w", err)
 	}
 
+	return l.locate(file, line, col)
+}
+
+// locate finds the symbol at the specified position in already loaded packages
+func (l *Locator) locate(file string, line, col int) (*types.Symbol, error) {
 	// Find the package containing this file
 	pkg, astFile := l.findFileInPackages(file)
 	if pkg == nil || astFile == nil {
@@ -73,6 +86,11 @@ func (l *Locator) loadPackages(root, file string) error {
 		return fmt.Errorf("file does not exist: %s", absFile)
 	}
 
+	return l.loadModule(root)
+}
+
+// loadModule loads every package under root
+func (l *Locator) loadModule(root string) error {
 	// Configure package loading
 	cfg := &packages.Config{
 		Mode: packages.NeedName |
@@ -102,6 +120,11 @@ func (l *Locator) loadPackages(root, file string) error {
 	}
 
 	l.pkgs = pkgs
+	l.index = nil
+	l.roles = nil
+	l.detector = nil
+	l.detectorWarning = ""
+	l.flow = nil
 	return nil
 }
 
@@ -353,6 +376,14 @@ func (l *Locator) exprToString(expr ast.Expr) string {
 		return "*" + l.exprToString(e.X)
 	case *ast.SelectorExpr:
 		return l.exprToString(e.X) + "." + e.Sel.Name
+	case *ast.IndexExpr:
+		return l.exprToString(e.X) + "[" + l.exprToString(e.Index) + "]"
+	case *ast.IndexListExpr:
+		params := make([]string, len(e.Indices))
+		for i, idx := range e.Indices {
+			params[i] = l.exprToString(idx)
+		}
+		return l.exprToString(e.X) + "[" + strings.Join(params, ", ") + "]"
 	default:
 		return fmt.Sprintf("%T", expr)
 	}
@@ -0,0 +1,155 @@
+package extract
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+)
+
+// fillModuleMetadata records the module, Go version and VCS state of the
+// extracted source so the extract can be reproduced and attributed
+func fillModuleMetadata(meta *types.Metadata, locator *Locator, target *types.Symbol, root string) {
+	meta.Toolchain = runtime.Version()
+	meta.ToolVersion = toolVersion()
+	meta.GoVersion = meta.Toolchain
+
+	for _, pkg := range locator.pkgs {
+		if pkg.PkgPath != target.Package || pkg.Module == nil {
+			continue
+		}
+
+		meta.Module = pkg.Module.Path
+		meta.ModuleVersion = pkg.Module.Version
+		if pkg.Module.Main && meta.ModuleVersion == "" {
+			meta.ModuleVersion = "(devel)"
+		}
+		if pkg.Module.GoVersion != "" {
+			meta.GoVersion = "go" + pkg.Module.GoVersion
+		}
+		break
+	}
+
+	vcs := locator.vcsState(root)
+	meta.VCSRevision, meta.VCSModified = vcs.revision, vcs.modified
+}
+
+// toolVersion describes the running go-scope build from its embedded build info
+func toolVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+
+	// Released and pseudo versions already identify the commit
+	if info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+
+	version := "(devel)"
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			version += " " + setting.Value
+		case "vcs.modified":
+			if setting.Value == "true" {
+				version += "+dirty"
+			}
+		}
+	}
+
+	return version
+}
+
+// vcsInfo is the git state of the loaded source
+type vcsInfo struct {
+	revision string
+	modified bool
+	gitDir   string // Absolute git directory, empty outside a checkout
+	stamp    string // gitStamp of gitDir when the state was read
+}
+
+// vcsState returns the git state of root. Commits, checkouts and stashes
+// leave the Go sources alone, so rather than relying on the workspace
+// reloading, the state is read again whenever git's HEAD, the branch it
+// points to or the index changes.
+func (l *Locator) vcsState(root string) vcsInfo {
+	if l.vcs == nil || gitStamp(l.vcs.gitDir) != l.vcs.stamp {
+		l.vcs = readVCSState(root)
+	}
+	return *l.vcs
+}
+
+// readVCSState returns the git revision of root and whether it has uncommitted changes.
+// Trees that aren't git checkouts (or machines without git) report nothing.
+func readVCSState(root string) *vcsInfo {
+	out, err := exec.Command("git", "-C", root, "rev-parse", "--absolute-git-dir", "HEAD").Output()
+	if err != nil {
+		return &vcsInfo{}
+	}
+	lines := strings.Fields(string(out))
+	if len(lines) != 2 {
+		return &vcsInfo{}
+	}
+	vcs := &vcsInfo{revision: lines[1], gitDir: lines[0]}
+	vcs.stamp = gitStamp(vcs.gitDir)
+
+	status, err := exec.Command("git", "-C", root, "status", "--porcelain", "--untracked-files=no").Output()
+	if err == nil {
+		vcs.modified = len(strings.TrimSpace(string(status))) > 0
+	}
+	return vcs
+}
+
+// gitStamp summarizes the files of a git directory that change with the
+// checked-out revision and the index: HEAD, the branch it points to,
+// packed-refs and the index
+func gitStamp(gitDir string) string {
+	if gitDir == "" {
+		return ""
+	}
+
+	head, _ := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	files := []string{"HEAD", "packed-refs", "index"}
+	if ref, ok := strings.CutPrefix(strings.TrimSpace(string(head)), "ref: "); ok {
+		files = append(files, filepath.FromSlash(ref))
+	}
+
+	var b strings.Builder
+	b.Write(head)
+	for _, file := range files {
+		if info, err := os.Stat(filepath.Join(gitDir, file)); err == nil {
+			fmt.Fprintf(&b, "%s %d %d\n", file, info.ModTime().UnixNano(), info.Size())
+		}
+	}
+	return b.String()
+}
+
+// countLines totals the source lines shown in full in the extract, counting each symbol once
+func countLines(target types.Symbol, references []types.Reference) int {
+	seen := map[string]bool{target.ID(): true}
+	total := symbolLines(target)
+
+	for _, ref := range references {
+		if ref.Stub || (ref.Render != "" && ref.Render != "full") || seen[ref.Symbol.ID()] {
+			continue
+		}
+		seen[ref.Symbol.ID()] = true
+		total += symbolLines(ref.Symbol)
+	}
+
+	return total
+}
+
+// symbolLines counts the lines of a symbol's code
+func symbolLines(sym types.Symbol) int {
+	if sym.Code == "" {
+		return 0
+	}
+	return strings.Count(strings.TrimRight(sym.Code, "\n"), "\n") + 1
+}
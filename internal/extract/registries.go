@@ -0,0 +1,57 @@
+package extract
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/extract-scope-go/go-scope/internal/extract/di"
+	"github.com/extract-scope-go/go-scope/internal/rules"
+	"golang.org/x/tools/go/packages"
+)
+
+// diDetector returns the DI detector of the loaded packages, creating it on
+// first use so the analyses it caches are shared by every query. The warning
+// is set when the rules file could not be read and its registries are missing.
+func (l *Locator) diDetector() (*di.Detector, string) {
+	if l.detector == nil {
+		l.detector, l.detectorWarning = newDIDetector(l.pkgs, l.fset)
+	}
+	return l.detector, l.detectorWarning
+}
+
+// newDIDetector creates a DI detector for the loaded packages, registering
+// the registries declared in the main module's rules file, if it has one. A
+// rules file that fails to load leaves the built-in frameworks and a warning;
+// only go-scope check treats it as an error.
+func newDIDetector(pkgs []*packages.Package, fset *token.FileSet) (*di.Detector, string) {
+	detector := di.NewDetector(pkgs, fset)
+
+	dir := ""
+	for _, pkg := range pkgs {
+		if pkg.Module != nil && pkg.Module.Main {
+			dir = pkg.Module.Dir
+			break
+		}
+	}
+	if dir == "" {
+		return detector, ""
+	}
+
+	file := filepath.Join(dir, rules.DefaultFile)
+	if _, err := os.Stat(file); errors.Is(err, fs.ErrNotExist) {
+		return detector, ""
+	}
+	cfg, err := rules.Load(file)
+	if err != nil {
+		return detector, fmt.Sprintf("DI registries ignored: %v", err)
+	}
+
+	for _, registry := range cfg.DI.Registries {
+		detector.Register(di.NewRegistry(registry.Framework, registry.Call, registry.Args))
+	}
+	return detector, ""
+}
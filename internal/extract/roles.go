@@ -0,0 +1,391 @@
+package extract
+
+import (
+	gotypes "go/types"
+	"strings"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+	"golang.org/x/tools/go/packages"
+)
+
+// Package path elements that place a package in a hexagonal layer. The
+// innermost matching element wins, so internal/adapters/http is primary.
+var (
+	domainElements    = []string{"domain", "entity", "entities", "model", "models"}
+	primaryElements   = []string{"http", "rest", "api", "handler", "handlers", "transport", "web", "controller", "controllers", "cli", "server"}
+	secondaryElements = []string{"adapter", "adapters", "infra", "infrastructure", "persistence", "repository", "repositories", "storage", "store", "db", "database", "postgres", "mysql", "sqlite", "redis", "mongo", "client", "clients", "gateway"}
+)
+
+// Imports that mark a package as infrastructure. Inbound frameworks make
+// primary adapters, outbound drivers and SDKs make secondary adapters.
+var (
+	inboundImports  = []string{"github.com/gin-gonic/gin", "github.com/labstack/echo", "github.com/go-chi/chi", "github.com/gorilla/mux", "github.com/gofiber/fiber", "github.com/spf13/cobra", "github.com/urfave/cli"}
+	outboundImports = []string{"database/sql", "net/smtp", "github.com/jackc/pgx", "github.com/lib/pq", "gorm.io/", "github.com/jmoiron/sqlx", "go.mongodb.org/mongo-driver", "github.com/redis/go-redis", "github.com/go-redis/redis", "github.com/segmentio/kafka-go", "github.com/IBM/sarama", "github.com/aws/aws-sdk-go", "cloud.google.com/go"}
+)
+
+// Imports that serve either direction; the type's methods decide
+const (
+	httpImport = "net/http"
+	grpcImport = "google.golang.org/grpc"
+)
+
+// RoleClassifier infers hexagonal-architecture roles for symbols
+type RoleClassifier struct {
+	pkgs         []*packages.Package
+	roles        map[gotypes.Object]string
+	named        []*gotypes.TypeName                       // Package-level named types, see namedTypes
+	ports        []*gotypes.TypeName                       // Non-adapter interfaces with methods, see portTypes
+	implementers map[*gotypes.TypeName][]*gotypes.TypeName // Interface → concrete types, see implementersOf
+}
+
+// NewRoleClassifier creates a classifier for the module's loaded packages
+func NewRoleClassifier(pkgs []*packages.Package) *RoleClassifier {
+	return &RoleClassifier{
+		pkgs:         pkgs,
+		roles:        make(map[gotypes.Object]string),
+		implementers: make(map[*gotypes.TypeName][]*gotypes.TypeName),
+	}
+}
+
+// roleClassifier returns the classifier of the loaded packages, building it
+// on first use so its caches are shared by every extraction
+func (l *Locator) roleClassifier() *RoleClassifier {
+	if l.roles == nil {
+		l.roles = NewRoleClassifier(l.pkgs)
+	}
+	return l.roles
+}
+
+// ClassifyAll sets Role on every symbol it can classify
+func (rc *RoleClassifier) ClassifyAll(symbols []types.Symbol) {
+	for i := range symbols {
+		symbols[i].Role = rc.Classify(symbols[i])
+	}
+}
+
+// ClassifyExtract sets Role on every module symbol of an extract
+func (rc *RoleClassifier) ClassifyExtract(ext *types.Extract) {
+	ext.Target.Role = rc.Classify(ext.Target)
+	for i := range ext.References {
+		ext.References[i].Symbol.Role = rc.Classify(ext.References[i].Symbol)
+	}
+	for i := range ext.InterfaceMappings {
+		mapping := &ext.InterfaceMappings[i]
+		mapping.Interface.Role = rc.Classify(mapping.Interface)
+		rc.ClassifyAll(mapping.Implementations)
+	}
+	for i := range ext.DIBindings {
+		binding := &ext.DIBindings[i]
+		binding.Product.Role = rc.Classify(binding.Product)
+		rc.ClassifyAll(binding.Dependencies)
+	}
+}
+
+// Classify returns the role of a symbol: "driving-port", "driven-port",
+// "primary-adapter", "secondary-adapter", "domain-entity", or "" if none
+// applies. Methods take the role of their receiver type.
+func (rc *RoleClassifier) Classify(sym types.Symbol) string {
+	name := sym.Name
+	if sym.Kind == "method" {
+		name = types.ReceiverBase(sym.Receiver)
+	}
+
+	pkg := rc.findPackage(sym.Package)
+	if pkg == nil || pkg.Types == nil {
+		return ""
+	}
+	typeName, ok := pkg.Types.Scope().Lookup(name).(*gotypes.TypeName)
+	if !ok {
+		return ""
+	}
+
+	if role, ok := rc.roles[typeName]; ok {
+		return role
+	}
+
+	var role string
+	if iface, ok := typeName.Type().Underlying().(*gotypes.Interface); ok {
+		role = rc.classifyInterface(typeName, iface)
+	} else {
+		role = rc.classifyType(typeName)
+	}
+
+	rc.roles[typeName] = role
+	return role
+}
+
+// classifyInterface decides between driving and driven port by ownership.
+// An interface implemented only in its own package is provider-defined: the
+// core offers it to primary adapters (driving). One implemented elsewhere, or
+// only consumed where it is declared, is consumer-defined: the core needs it
+// and secondary adapters provide it (driven). Interfaces declared by adapters
+// are not ports.
+func (rc *RoleClassifier) classifyInterface(typeName *gotypes.TypeName, iface *gotypes.Interface) string {
+	pkgPath := typeName.Pkg().Path()
+	if side := packageSide(pkgPath); side == "primary" || side == "secondary" {
+		return ""
+	}
+
+	implemented, implementedElsewhere := false, false
+	for _, candidate := range rc.implementersOf(typeName, iface) {
+		implemented = true
+		if candidate.Pkg().Path() != pkgPath {
+			implementedElsewhere = true
+		}
+	}
+
+	switch {
+	case implementedElsewhere:
+		return "driven-port"
+	case implemented:
+		return "driving-port"
+	case rc.consumedInPackage(typeName):
+		return "driven-port"
+	default:
+		return ""
+	}
+}
+
+// classifyType classifies a concrete type by the inbound or outbound
+// infrastructure it touches, the ports it implements, and its package
+func (rc *RoleClassifier) classifyType(typeName *gotypes.TypeName) string {
+	pkg := typeName.Pkg()
+	side := packageSide(pkg.Path())
+	inbound, outbound := infrastructureImports(pkg)
+
+	switch {
+	case inbound || side == "primary" || servesRequests(typeName):
+		return "primary-adapter"
+	case outbound || side == "secondary" || rc.implementsForeignPort(typeName):
+		return "secondary-adapter"
+	case side == "domain" && isStruct(typeName):
+		return "domain-entity"
+	default:
+		return ""
+	}
+}
+
+// implementsForeignPort reports whether a type implements an interface
+// declared by another, non-adapter package of the module
+func (rc *RoleClassifier) implementsForeignPort(typeName *gotypes.TypeName) bool {
+	for _, port := range rc.portTypes() {
+		if port.Pkg() == typeName.Pkg() {
+			continue
+		}
+		for _, impl := range rc.implementersOf(port, port.Type().Underlying().(*gotypes.Interface)) {
+			if impl == typeName {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// implementersOf returns the module's concrete types implementing an
+// interface, computed once per interface
+func (rc *RoleClassifier) implementersOf(typeName *gotypes.TypeName, iface *gotypes.Interface) []*gotypes.TypeName {
+	if impls, ok := rc.implementers[typeName]; ok {
+		return impls
+	}
+
+	var impls []*gotypes.TypeName
+	for _, candidate := range rc.namedTypes() {
+		if candidate == typeName || gotypes.IsInterface(candidate.Type()) {
+			continue
+		}
+		if implementsEither(candidate.Type(), iface) {
+			impls = append(impls, candidate)
+		}
+	}
+	rc.implementers[typeName] = impls
+	return impls
+}
+
+// portTypes returns the module's interfaces with methods that are not
+// declared by adapters, collected on first use
+func (rc *RoleClassifier) portTypes() []*gotypes.TypeName {
+	if rc.ports != nil {
+		return rc.ports
+	}
+
+	rc.ports = []*gotypes.TypeName{}
+	for _, candidate := range rc.namedTypes() {
+		iface, ok := candidate.Type().Underlying().(*gotypes.Interface)
+		if !ok || iface.NumMethods() == 0 {
+			continue
+		}
+		if side := packageSide(candidate.Pkg().Path()); side == "primary" || side == "secondary" {
+			continue
+		}
+		rc.ports = append(rc.ports, candidate)
+	}
+	return rc.ports
+}
+
+// consumedInPackage reports whether an interface is used as a struct field
+// or function parameter type in its own package
+func (rc *RoleClassifier) consumedInPackage(typeName *gotypes.TypeName) bool {
+	scope := typeName.Pkg().Scope()
+	for _, name := range scope.Names() {
+		switch obj := scope.Lookup(name).(type) {
+		case *gotypes.TypeName:
+			if st, ok := obj.Type().Underlying().(*gotypes.Struct); ok {
+				for i := 0; i < st.NumFields(); i++ {
+					if usesType(st.Field(i).Type(), typeName) {
+						return true
+					}
+				}
+			}
+		case *gotypes.Func:
+			params := obj.Type().(*gotypes.Signature).Params()
+			for i := 0; i < params.Len(); i++ {
+				if usesType(params.At(i).Type(), typeName) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// namedTypes returns every package-level named type in the module,
+// collected on first use
+func (rc *RoleClassifier) namedTypes() []*gotypes.TypeName {
+	if rc.named != nil {
+		return rc.named
+	}
+
+	names := []*gotypes.TypeName{}
+	for _, pkg := range rc.pkgs {
+		if pkg.Types == nil {
+			continue
+		}
+		scope := pkg.Types.Scope()
+		for _, name := range scope.Names() {
+			if typeName, ok := scope.Lookup(name).(*gotypes.TypeName); ok {
+				names = append(names, typeName)
+			}
+		}
+	}
+	rc.named = names
+	return names
+}
+
+// findPackage returns the loaded package with the given path
+func (rc *RoleClassifier) findPackage(pkgPath string) *packages.Package {
+	for _, pkg := range rc.pkgs {
+		if pkg.PkgPath == pkgPath {
+			return pkg
+		}
+	}
+	return nil
+}
+
+// packageSide places a package by its path: "domain", "primary",
+// "secondary", or "" when no element is recognised
+func packageSide(pkgPath string) string {
+	elements := strings.Split(strings.ToLower(pkgPath), "/")
+	for i := len(elements) - 1; i >= 0; i-- {
+		switch {
+		case contains(domainElements, elements[i]):
+			return "domain"
+		case contains(primaryElements, elements[i]):
+			return "primary"
+		case contains(secondaryElements, elements[i]):
+			return "secondary"
+		}
+	}
+	return ""
+}
+
+// infrastructureImports reports whether a package imports inbound or
+// outbound infrastructure. net/http and gRPC count as outbound (clients)
+// here; servesRequests recognises the server side.
+func infrastructureImports(pkg *gotypes.Package) (inbound, outbound bool) {
+	for _, imp := range pkg.Imports() {
+		path := imp.Path()
+		switch {
+		case hasAnyPrefix(path, inboundImports):
+			inbound = true
+		case hasAnyPrefix(path, outboundImports), path == httpImport, strings.HasPrefix(path, grpcImport):
+			outbound = true
+		}
+	}
+	return inbound, outbound
+}
+
+// servesRequests reports whether a type handles HTTP or gRPC requests: a
+// method takes an http.ResponseWriter, or it embeds a generated gRPC
+// Unimplemented...Server
+func servesRequests(typeName *gotypes.TypeName) bool {
+	methods := gotypes.NewMethodSet(gotypes.NewPointer(typeName.Type()))
+	for i := 0; i < methods.Len(); i++ {
+		params := methods.At(i).Obj().Type().(*gotypes.Signature).Params()
+		for j := 0; j < params.Len(); j++ {
+			if gotypes.TypeString(params.At(j).Type(), nil) == httpImport+".ResponseWriter" {
+				return true
+			}
+		}
+	}
+
+	if st, ok := typeName.Type().Underlying().(*gotypes.Struct); ok {
+		for i := 0; i < st.NumFields(); i++ {
+			field := st.Field(i)
+			if field.Embedded() && strings.HasPrefix(field.Name(), "Unimplemented") && strings.HasSuffix(field.Name(), "Server") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// implementsEither reports whether T or *T implements the interface
+func implementsEither(typ gotypes.Type, iface *gotypes.Interface) bool {
+	return gotypes.Implements(typ, iface) || gotypes.Implements(gotypes.NewPointer(typ), iface)
+}
+
+// usesType reports whether typ is the named type, possibly behind pointers,
+// slices, maps or channels
+func usesType(typ gotypes.Type, typeName *gotypes.TypeName) bool {
+	switch t := typ.(type) {
+	case *gotypes.Named:
+		return t.Obj() == typeName
+	case *gotypes.Pointer:
+		return usesType(t.Elem(), typeName)
+	case *gotypes.Slice:
+		return usesType(t.Elem(), typeName)
+	case *gotypes.Array:
+		return usesType(t.Elem(), typeName)
+	case *gotypes.Map:
+		return usesType(t.Key(), typeName) || usesType(t.Elem(), typeName)
+	case *gotypes.Chan:
+		return usesType(t.Elem(), typeName)
+	}
+	return false
+}
+
+// isStruct reports whether a named type is a struct
+func isStruct(typeName *gotypes.TypeName) bool {
+	_, ok := typeName.Type().Underlying().(*gotypes.Struct)
+	return ok
+}
+
+// hasAnyPrefix reports whether s starts with any of the prefixes
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// contains reports whether a string is in a slice
+func contains(slice []string, str string) bool {
+	for _, s := range slice {
+		if s == str {
+			return true
+		}
+	}
+	return false
+}
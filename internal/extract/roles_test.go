@@ -0,0 +1,84 @@
+package extract
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClassifyRoles tests port and adapter roles in the hexagonal example
+func TestClassifyRoles(t *testing.T) {
+	// Given: The hexagonal example module
+	ws := loadExample(t, "ex2")
+
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{"domain.User", "domain-entity"},
+		{"domain.UserRepository", "driven-port"},
+		{"domain.Notifier", "driven-port"},
+		{"app.Renamer", "driving-port"},
+		{"memory.Repository", "secondary-adapter"},
+		{"memory.Repository.Get", "secondary-adapter"},
+		{"email.Notifier", "secondary-adapter"},
+		{"rest.Handler", "primary-adapter"},
+		{"rest.Handler.ServeHTTP", "primary-adapter"},
+		{"app.UserService", ""},
+		{"app.NewUserService", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			// When: We resolve the symbol from the classified index
+			sym, err := ws.Resolve(tt.query)
+			require.NoError(t, err)
+
+			// Then: It carries the expected role
+			assert.Equal(t, tt.expected, sym.Role)
+		})
+	}
+}
+
+// TestPackageSide tests placing packages by their path elements
+func TestPackageSide(t *testing.T) {
+	tests := []struct {
+		pkgPath  string
+		expected string
+	}{
+		{"example.com/app/internal/domain", "domain"},
+		{"example.com/app/internal/adapters/postgres", "secondary"},
+		{"example.com/app/internal/adapters/http", "primary"},
+		{"example.com/app/internal/app", ""},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, packageSide(tt.pkgPath), tt.pkgPath)
+	}
+}
+
+// TestExtractClassifiesRoles tests that extracts carry roles for the target and references
+func TestExtractClassifiesRoles(t *testing.T) {
+	// Given: The repository's Get method
+	root := filepath.Join("..", "..", "examples", "ex2")
+	target := types.Target{Root: root, File: filepath.Join(root, "internal", "adapters", "memory", "repo.go"), Line: 16, Column: 1}
+
+	// When: We extract it
+	result, err := ExtractSymbol(context.Background(), target, types.Options{Depth: 1})
+	require.NoError(t, err)
+
+	// Then: The target is a secondary adapter and the domain types it uses are classified
+	assert.Equal(t, "secondary-adapter", result.Extract.Target.Role)
+	var user *types.Reference
+	for i, ref := range result.Extract.References {
+		if ref.Symbol.Name == "User" {
+			user = &result.Extract.References[i]
+		}
+	}
+	require.NotNil(t, user)
+	assert.Equal(t, "domain-entity", user.Symbol.Role)
+}
@@ -0,0 +1,48 @@
+package extract
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"strings"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+)
+
+// signatureOf renders the declaration of a symbol without its body
+func signatureOf(sym types.Symbol) string {
+	code := strings.TrimSpace(sym.Code)
+	if code == "" {
+		return ""
+	}
+
+	switch sym.Kind {
+	case "func", "method":
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, "", "package p\n"+code, 0)
+		if err == nil && len(file.Decls) == 1 {
+			if fn, ok := file.Decls[0].(*ast.FuncDecl); ok {
+				fn.Body = nil
+				var b bytes.Buffer
+				if err := printer.Fprint(&b, fset, fn); err == nil {
+					return b.String()
+				}
+			}
+		}
+	case "interface":
+		// An interface is its own contract
+		return "type " + code
+	case "struct", "type":
+		code = "type " + code
+	}
+
+	// Keep the first line, closing any block it opens
+	first, _, multiline := strings.Cut(code, "\n")
+	first = strings.TrimSpace(first)
+	if multiline && strings.HasSuffix(first, "{") {
+		first += " ... }"
+	}
+	return first
+}
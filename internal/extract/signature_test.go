@@ -0,0 +1,49 @@
+package extract
+
+import (
+	"testing"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSignatureOf tests reducing declarations to their signatures
+func TestSignatureOf(t *testing.T) {
+	tests := []struct {
+		name string
+		sym  types.Symbol
+		want string
+	}{
+		{
+			name: "function",
+			sym:  types.Symbol{Kind: "func", Code: "func Add(a, b int) int {\n\treturn a + b\n}"},
+			want: "func Add(a, b int) int",
+		},
+		{
+			name: "method",
+			sym:  types.Symbol{Kind: "method", Code: "func (s *Store) Get(id string) (Item, error) {\n\treturn s.items[id], nil\n}"},
+			want: "func (s *Store) Get(id string) (Item, error)",
+		},
+		{
+			name: "struct",
+			sym:  types.Symbol{Kind: "struct", Code: "Store struct {\n\titems map[string]Item\n}"},
+			want: "type Store struct { ... }",
+		},
+		{
+			name: "interface",
+			sym:  types.Symbol{Kind: "interface", Code: "Getter interface {\n\tGet(id string) (Item, error)\n}"},
+			want: "type Getter interface {\n\tGet(id string) (Item, error)\n}",
+		},
+		{
+			name: "no code",
+			sym:  types.Symbol{Kind: "func"},
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, signatureOf(tt.sym))
+		})
+	}
+}
@@ -0,0 +1,271 @@
+package extract
+
+import (
+	"fmt"
+	"go/ast"
+	gotypes "go/types"
+	"strings"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+)
+
+// taintDepth is how many function boundaries taint is followed across
+const taintDepth = 6
+
+// taintSink is a sensitive function, method or conversion
+type taintSink struct {
+	kind string // "sql", "exec", "template", "path"
+	arg  int    // Argument that must not be tainted, -1 for any
+}
+
+// taintSinks are the sinks by object ID
+var taintSinks = func() map[string]taintSink {
+	sinks := map[string]taintSink{
+		"os/exec.Command":                        {"exec", -1},
+		"os/exec.CommandContext":                 {"exec", -1},
+		"html/template.HTML":                     {"template", 0},
+		"html/template.HTMLAttr":                 {"template", 0},
+		"html/template.JS":                       {"template", 0},
+		"html/template.CSS":                      {"template", 0},
+		"html/template.URL":                      {"template", 0},
+		"html/template.Template.Parse":           {"template", 0},
+		"text/template.Template.Parse":           {"template", 0},
+		"text/template.Template.Execute":         {"template", 1},
+		"text/template.Template.ExecuteTemplate": {"template", 2},
+		"os.Rename":                              {"path", -1},
+		"net/http.ServeFile":                     {"path", 2},
+	}
+	for _, typ := range []string{"DB", "Tx", "Conn"} {
+		for _, method := range []string{"Query", "QueryRow", "Exec", "Prepare"} {
+			sinks["database/sql."+typ+"."+method] = taintSink{"sql", 0}
+			sinks["database/sql."+typ+"."+method+"Context"] = taintSink{"sql", 1}
+		}
+	}
+	for _, fn := range []string{"Open", "OpenFile", "Create", "ReadFile", "WriteFile", "ReadDir", "Remove", "RemoveAll", "Mkdir", "MkdirAll", "Chmod"} {
+		sinks["os."+fn] = taintSink{"path", 0}
+	}
+	return sinks
+}()
+
+// taintSourceCalls are the calls returning untrusted data, by object ID
+var taintSourceCalls = map[string]string{
+	"os.Getenv":          "env",
+	"os.LookupEnv":       "env",
+	"os.Environ":         "env",
+	"os.ReadFile":        "file",
+	"os.Open":            "file",
+	"os.OpenFile":        "file",
+	"io/ioutil.ReadFile": "file",
+}
+
+// taintSource is where untrusted data enters the module: a parameter
+// carrying a request, or a call returning outside input
+type taintSource struct {
+	endpoint types.TaintEndpoint
+	fn       *flowFunc
+	param    *gotypes.Var
+	call     *ast.CallExpr
+}
+
+// TaintPaths follows untrusted input, from HTTP requests, gRPC request
+// messages, environment variables and file reads, to the sinks it reaches:
+// SQL query strings, commands, templates and file paths
+func (w *Workspace) TaintPaths() []types.TaintPath {
+	return taintPaths(w.locator, taintDepth)
+}
+
+// taintPaths finds every source→sink path of the locator's packages
+func taintPaths(locator *Locator, depth int) []types.TaintPath {
+	a := newFlowAnalysis(locator, depth)
+	paths := []types.TaintPath{}
+	seen := make(map[string]bool)
+
+	for _, source := range a.taintSources() {
+		a.reset()
+		a.onCall = func(fn *flowFunc, call *ast.CallExpr, callee gotypes.Object, index, _ int) {
+			sink, ok := taintSinks[objectID(callee)]
+			if !ok || index < 0 || (sink.arg >= 0 && sink.arg != index) {
+				return
+			}
+			endpoint := a.endpoint(fn, call, sink.kind, gotypes.ExprString(call.Fun))
+			key := fmt.Sprintf("%s:%d>%s:%d", source.endpoint.File, source.endpoint.Line, endpoint.File, endpoint.Line)
+			if seen[key] {
+				return
+			}
+			seen[key] = true
+
+			path := types.TaintPath{
+				Source: source.endpoint,
+				Sink:   endpoint,
+				Chain:  []string{source.endpoint.Function},
+				Steps:  append([]types.FlowStep{}, a.trail...),
+			}
+			for _, step := range path.Steps {
+				if step.Function != path.Chain[len(path.Chain)-1] {
+					path.Chain = append(path.Chain, step.Function)
+				}
+			}
+			if endpoint.Function != path.Chain[len(path.Chain)-1] {
+				path.Chain = append(path.Chain, endpoint.Function)
+			}
+			paths = append(paths, path)
+		}
+
+		if source.param != nil {
+			a.forward(source.param, 0, false)
+		} else {
+			a.flowUp(source.fn, source.call, 0, false)
+		}
+	}
+	return paths
+}
+
+// reset forgets the values followed so far, keeping the module's index
+func (a *flowAnalysis) reset() {
+	a.visited = make(map[flowKey]int)
+	a.steps = nil
+	a.stepKeys = make(map[string]bool)
+	a.trail = nil
+}
+
+// taintSources lists the request parameters and input calls of every function
+func (a *flowAnalysis) taintSources() []taintSource {
+	var sources []taintSource
+	for _, pkg := range a.locator.pkgs {
+		for _, astFile := range pkg.Syntax {
+			for _, decl := range astFile.Decls {
+				fd, ok := decl.(*ast.FuncDecl)
+				if !ok || fd.Body == nil {
+					continue
+				}
+				obj, _ := pkg.TypesInfo.Defs[fd.Name].(*gotypes.Func)
+				fn := a.funcs[obj]
+				if fn == nil {
+					continue
+				}
+
+				sources = append(sources, a.requestParams(fn, fn.obj.Type().(*gotypes.Signature))...)
+
+				ast.Inspect(fd.Body, func(n ast.Node) bool {
+					if lit, ok := n.(*ast.FuncLit); ok {
+						// Inline handlers: mux.HandleFunc("/x", func(w, r) {...})
+						if sig, ok := pkg.TypesInfo.TypeOf(lit).(*gotypes.Signature); ok {
+							sources = append(sources, a.requestParams(fn, sig)...)
+						}
+						return true
+					}
+					call, ok := n.(*ast.CallExpr)
+					if !ok {
+						return true
+					}
+					callee := calleeObject(pkg, call.Fun)
+					if callee == nil {
+						return true
+					}
+					if kind, ok := taintSourceCalls[objectID(callee)]; ok {
+						sources = append(sources, taintSource{
+							endpoint: a.endpoint(fn, call, kind, gotypes.ExprString(call.Fun)),
+							fn:       fn,
+							call:     call,
+						})
+					}
+					return true
+				})
+			}
+		}
+	}
+	return sources
+}
+
+// requestParams lists the parameters of a function, or of a function
+// literal written in it, that carry a request
+func (a *flowAnalysis) requestParams(fn *flowFunc, sig *gotypes.Signature) []taintSource {
+	var sources []taintSource
+	for i := 0; i < sig.Params().Len(); i++ {
+		param := sig.Params().At(i)
+		kind := requestKind(sig, i)
+		if kind == "" || param.Name() == "" || param.Name() == "_" {
+			continue
+		}
+		value := param.Name() + " " + gotypes.TypeString(param.Type(), (*gotypes.Package).Name)
+		sources = append(sources, taintSource{
+			endpoint: a.endpoint(fn, a.declaration(param), kind, value),
+			fn:       fn,
+			param:    param,
+		})
+	}
+	return sources
+}
+
+// requestKind returns the source kind of the parameter at index i: "http"
+// for an *http.Request, "grpc" for the request message of a gRPC-style
+// method, func(ctx, *XRequest) (*XResponse, error)
+func requestKind(sig *gotypes.Signature, i int) string {
+	param := sig.Params().At(i).Type()
+	if isNamedType(param, "net/http", "Request") {
+		return "http"
+	}
+
+	if i != 1 || sig.Params().Len() != 2 || sig.Results().Len() != 2 {
+		return ""
+	}
+	if !isNamedType(sig.Params().At(0).Type(), "context", "Context") {
+		return ""
+	}
+	if sig.Results().At(1).Type().String() != "error" {
+		return ""
+	}
+	ptr, ok := param.(*gotypes.Pointer)
+	if !ok {
+		return ""
+	}
+	named, ok := ptr.Elem().(*gotypes.Named)
+	if !ok {
+		return ""
+	}
+	if strings.HasSuffix(named.Obj().Name(), "Request") {
+		return "grpc"
+	}
+	if obj, _, _ := gotypes.LookupFieldOrMethod(ptr, false, nil, "ProtoReflect"); obj != nil {
+		return "grpc"
+	}
+	return ""
+}
+
+// isNamedType reports whether typ, or the type it points to, is pkgPath.name
+func isNamedType(typ gotypes.Type, pkgPath, name string) bool {
+	if ptr, ok := typ.(*gotypes.Pointer); ok {
+		typ = ptr.Elem()
+	}
+	named, ok := typ.(*gotypes.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == pkgPath && named.Obj().Name() == name
+}
+
+// endpoint describes a source or sink at a node
+func (a *flowAnalysis) endpoint(fn *flowFunc, node ast.Node, kind, value string) types.TaintEndpoint {
+	pos := a.locator.fset.Position(node.Pos())
+	return types.TaintEndpoint{
+		Kind:     kind,
+		Value:    value,
+		Function: objectID(fn.obj),
+		File:     pos.Filename,
+		Line:     pos.Line,
+		Code:     a.sourceLine(pos.Filename, pos.Line),
+	}
+}
+
+// taintThrough keeps the paths whose call chain includes a symbol: the
+// function itself, or a method of the type
+func taintThrough(paths []types.TaintPath, sym types.Symbol) []types.TaintPath {
+	id := sym.ID()
+	var through []types.TaintPath
+	for _, path := range paths {
+		for _, fn := range path.Chain {
+			if fn == id || strings.HasPrefix(fn, id+".") {
+				through = append(through, path)
+				break
+			}
+		}
+	}
+	return through
+}
@@ -0,0 +1,156 @@
+package extract
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// taintNames lists paths as "source value -> sink value (file:line)"
+func taintNames(paths []types.TaintPath) []string {
+	var names []string
+	for _, path := range paths {
+		names = append(names, fmt.Sprintf("%s %s -> %s %s (%s:%d)", path.Source.Kind, path.Source.Value, path.Sink.Kind, path.Sink.Value, filepath.Base(path.Sink.File), path.Sink.Line))
+	}
+	return names
+}
+
+// TestTaintPaths tests following each kind of source to each kind of sink
+func TestTaintPaths(t *testing.T) {
+	// Given: The security example, with HTTP and gRPC handlers and a seeding main
+	ws := loadExample(t, "ex7")
+
+	// When: We find the module's taint paths
+	paths := ws.TaintPaths()
+
+	// Then: Requests, environment and files reach SQL, commands, templates and paths
+	assert.ElementsMatch(t, []string{
+		"http r *http.Request -> sql s.db.Query (store.go:18)",
+		"grpc req *pb.SearchRequest -> sql s.db.Query (store.go:18)",
+		"http r *http.Request -> template template.HTML (http.go:46)",
+		"http r *http.Request -> exec exec.Command (http.go:52)",
+		"file os.ReadFile -> sql s.db.Exec (store.go:44)",
+		"env os.Getenv -> path os.ReadFile (main.go:21)",
+		"http r *http.Request -> sql s.db.Query (store.go:18)",    // Inline report handler, through a closure's result
+		"http r *http.Request -> exec exec.Command (admin.go:23)", // Inline convert handler, through a closure's parameter
+		"http r *http.Request -> exec exec.Command (admin.go:35)", // Inline archive handler, through a slice alias
+		"http r *http.Request -> sql s.db.Query (store.go:18)",    // Inline find handler, through a pointer
+		"http r *http.Request -> sql s.db.Query (store.go:18)",    // formValue, through a pointer
+	}, taintNames(paths))
+
+	// And: The gRPC path crosses into the store through the request's getter
+	for _, path := range paths {
+		if path.Source.Kind != "grpc" {
+			continue
+		}
+		assert.Equal(t, []string{"example.com/ex7/internal/server.SearchServer.Search", "example.com/ex7/internal/store.Store.Find"}, path.Chain)
+		var steps []string
+		for _, step := range path.Steps {
+			steps = append(steps, step.Kind+" "+step.Value)
+		}
+		assert.Equal(t, []string{"call-arg req.GetQuery", "call-arg s.users.Find", "assign query"}, steps)
+	}
+}
+
+// TestTaintParameterizedQuery tests that query arguments passed as parameters are not sinks
+func TestTaintParameterizedQuery(t *testing.T) {
+	// Given: The security example, whose User handler passes the ID as a query parameter
+	ws := loadExample(t, "ex7")
+
+	// When/Then: No path ends in Exists
+	for _, path := range ws.TaintPaths() {
+		assert.NotEqual(t, "example.com/ex7/internal/store.Store.Exists", path.Sink.Function)
+	}
+}
+
+// TestExtractTaint tests that an extract reports only the paths through its target
+func TestExtractTaint(t *testing.T) {
+	// Given: The store's Find method in the security example
+	ws := loadExample(t, "ex7")
+	target := types.Target{Root: ws.Root(), File: filepath.Join(ws.Root(), "internal", "store", "store.go"), Line: 16}
+
+	// When: We extract it with and without the security mode
+	result, err := ws.Extract(context.Background(), target, types.Options{Depth: 1, Taint: true})
+	require.NoError(t, err)
+	plain, err := ws.Extract(context.Background(), target, types.Options{Depth: 1})
+	require.NoError(t, err)
+
+	// Then: The HTTP and gRPC searches and the admin report and find handlers reach the query through it
+	assert.ElementsMatch(t, []string{
+		"http r *http.Request -> sql s.db.Query (store.go:18)",
+		"grpc req *pb.SearchRequest -> sql s.db.Query (store.go:18)",
+		"http r *http.Request -> sql s.db.Query (store.go:18)",
+		"http r *http.Request -> sql s.db.Query (store.go:18)",
+		"http r *http.Request -> sql s.db.Query (store.go:18)",
+	}, taintNames(result.Extract.Taints))
+	assert.Contains(t, result.Rendered, "## Taint Paths")
+	assert.Empty(t, plain.Extract.Taints)
+}
+
+// taintFrom returns the path from a source line of admin.go to a sink line of a file
+func taintFrom(t *testing.T, paths []types.TaintPath, source int, sinkFile string, sink int) types.TaintPath {
+	t.Helper()
+	for _, path := range paths {
+		if filepath.Base(path.Source.File) == "admin.go" && path.Source.Line == source &&
+			filepath.Base(path.Sink.File) == sinkFile && path.Sink.Line == sink {
+			return path
+		}
+	}
+	require.Failf(t, "no taint path", "admin.go:%d -> %s:%d", source, sinkFile, sink)
+	return types.TaintPath{}
+}
+
+// taintSteps lists a path's steps as "kind value"
+func taintSteps(path types.TaintPath) []string {
+	var steps []string
+	for _, step := range path.Steps {
+		steps = append(steps, step.Kind+" "+step.Value)
+	}
+	return steps
+}
+
+// TestTaintClosures tests requests of inline handlers followed into and out of closures
+func TestTaintClosures(t *testing.T) {
+	// Given: The security example's admin routes, served by function literals
+	ws := loadExample(t, "ex7")
+
+	// When: We find the module's taint paths
+	paths := ws.TaintPaths()
+
+	// Then: The report filter leaves its closure through the closure's result
+	report := taintFrom(t, paths, 13, "store.go", 18)
+	assert.Equal(t, "example.com/ex7/internal/server.Routes", report.Source.Function)
+	assert.Equal(t, []string{"call-arg r.FormValue", "return return", "call-result filter", "call-arg users.Find", "assign query"}, taintSteps(report))
+
+	// And: The file name enters its closure as an argument
+	convert := taintFrom(t, paths, 21, "admin.go", 23)
+	assert.Equal(t, []string{"call-arg r.FormValue", "call-arg convert"}, taintSteps(convert))
+}
+
+// TestTaintAliases tests values stored through a slice or pointer reaching the variables sharing it
+func TestTaintAliases(t *testing.T) {
+	// Given: The security example's admin routes
+	ws := loadExample(t, "ex7")
+
+	// When: We find the module's taint paths
+	paths := ws.TaintPaths()
+
+	// Then: The directory stored through a subslice reaches the command through the arguments
+	archive := taintFrom(t, paths, 31, "admin.go", 35)
+	assert.Equal(t, []string{"call-arg r.FormValue", "alias args"}, taintSteps(archive))
+
+	// And: The name stored through a pointer by a helper reaches the query through the handler's variable
+	find := taintFrom(t, paths, 41, "store.go", 18)
+	assert.Equal(t, []string{"call-arg formValue", "call-arg r.FormValue", "alias name", "call-arg users.Find", "assign query"}, taintSteps(find))
+	assert.Equal(t, []string{
+		"example.com/ex7/internal/server.Routes",
+		"example.com/ex7/internal/server.formValue",
+		"example.com/ex7/internal/server.Routes",
+		"example.com/ex7/internal/store.Store.Find",
+	}, find.Chain)
+}
@@ -0,0 +1,389 @@
+package extract
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	gotypes "go/types"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+)
+
+// Workspace keeps a module's packages loaded so that repeated queries
+// (editor or assistant sessions) skip the expensive load step
+type Workspace struct {
+	root     string
+	locator  *Locator
+	symbols  []indexedSymbol      // Every declared symbol, in package/file order
+	modTimes map[string]time.Time // Loaded files and their modification times
+	sources  map[string]bool      // Go files under root, to notice added files and packages
+}
+
+// indexedSymbol pairs a declared symbol with its type-checker object
+type indexedSymbol struct {
+	symbol types.Symbol
+	obj    gotypes.Object
+}
+
+// LoadWorkspace loads every package of the module rooted at root
+func LoadWorkspace(root string) (*Workspace, error) {
+	w := &Workspace{root: root}
+	if err := w.load(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Root returns the module root the workspace was loaded from
+func (w *Workspace) Root() string {
+	return w.root
+}
+
+// load (re)loads the packages and rebuilds the symbol index
+func (w *Workspace) load() error {
+	locator := NewLocator()
+	if err := locator.loadModule(w.root); err != nil {
+		return fmt.Errorf("failed to load packages: %w", err)
+	}
+
+	w.locator = locator
+	w.symbols = nil
+	w.modTimes = make(map[string]time.Time)
+	w.sources = goSources(w.root)
+
+	for _, pkg := range locator.pkgs {
+		for _, file := range pkg.CompiledGoFiles {
+			if info, err := os.Stat(file); err == nil {
+				w.modTimes[file] = info.ModTime()
+			}
+		}
+
+		for _, astFile := range pkg.Syntax {
+			for _, decl := range astFile.Decls {
+				switch d := decl.(type) {
+				case *ast.FuncDecl:
+					sym, _ := locator.extractFromFuncDecl(pkg, astFile, d)
+					w.add(sym, pkg.TypesInfo.Defs[d.Name])
+
+				case *ast.GenDecl:
+					for _, spec := range d.Specs {
+						switch s := spec.(type) {
+						case *ast.TypeSpec:
+							doc := s.Doc
+							if doc == nil {
+								doc = d.Doc
+							}
+							sym, _ := locator.extractFromTypeSpec(pkg, astFile, s, doc)
+							w.add(sym, pkg.TypesInfo.Defs[s.Name])
+						case *ast.ValueSpec:
+							doc := s.Doc
+							if doc == nil {
+								doc = d.Doc
+							}
+							sym, _ := locator.extractFromValueSpec(pkg, astFile, s, doc, d.Tok)
+							if sym != nil && s.Names[0].Name != "_" {
+								w.add(sym, pkg.TypesInfo.Defs[s.Names[0]])
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+
+	locator.implementationIndex()
+
+	classifier := locator.roleClassifier()
+	for i := range w.symbols {
+		w.symbols[i].symbol.Role = classifier.Classify(w.symbols[i].symbol)
+	}
+
+	return nil
+}
+
+// add records a symbol in the index
+func (w *Workspace) add(sym *types.Symbol, obj gotypes.Object) {
+	if sym == nil || obj == nil {
+		return
+	}
+	w.symbols = append(w.symbols, indexedSymbol{symbol: *sym, obj: obj})
+}
+
+// Refresh reloads the workspace if any loaded source file changed or
+// vanished, or if Go files were added to the module
+func (w *Workspace) Refresh() error {
+	for file, modTime := range w.modTimes {
+		info, err := os.Stat(file)
+		if err != nil || !info.ModTime().Equal(modTime) {
+			return w.load()
+		}
+	}
+	if !maps.Equal(goSources(w.root), w.sources) {
+		return w.load()
+	}
+	return nil
+}
+
+// goSources lists the Go files of a module, skipping the directories the go
+// command ignores and nested modules
+func goSources(root string) map[string]bool {
+	files := make(map[string]bool)
+	filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path == root {
+				return nil
+			}
+			name := d.Name()
+			if strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "testdata" || name == "vendor" {
+				return filepath.SkipDir
+			}
+			if _, err := os.Stat(filepath.Join(path, "go.mod")); err == nil {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasSuffix(path, ".go") {
+			files[path] = true
+		}
+		return nil
+	})
+	return files
+}
+
+// Extract extracts and formats the symbol at target using the loaded packages
+func (w *Workspace) Extract(ctx context.Context, target types.Target, opts types.Options) (*types.Result, error) {
+	if target.Root == "" {
+		target.Root = w.root
+	}
+
+	result, err := extractLoaded(ctx, w.locator, target, opts, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := formatResult(result, opts); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Symbols returns every symbol declared in the module
+func (w *Workspace) Symbols() []types.Symbol {
+	symbols := make([]types.Symbol, len(w.symbols))
+	for i, entry := range w.symbols {
+		symbols[i] = entry.symbol
+	}
+	return symbols
+}
+
+// Resolve looks up a symbol by name. The query may be a bare name ("Add"), a
+// method ("Store.Get"), or qualified by any trailing part of the package path
+// ("math.Add", "example.com/ex1/pkg/math.Add"); it must match exactly one symbol.
+func (w *Workspace) Resolve(query string) (types.Symbol, error) {
+	entry, err := w.resolve(query)
+	if err != nil {
+		return types.Symbol{}, err
+	}
+	return entry.symbol, nil
+}
+
+// resolve finds the single index entry a query names
+func (w *Workspace) resolve(query string) (indexedSymbol, error) {
+	var matches []indexedSymbol
+	for _, entry := range w.symbols {
+		if matchesQuery(entry.symbol, query) {
+			matches = append(matches, entry)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return indexedSymbol{}, fmt.Errorf("symbol not found: %s", query)
+	case 1:
+		return matches[0], nil
+	default:
+		ids := make([]string, len(matches))
+		for i, match := range matches {
+			ids[i] = match.symbol.ID()
+		}
+		return indexedSymbol{}, fmt.Errorf("symbol %s is ambiguous: %s", query, strings.Join(ids, ", "))
+	}
+}
+
+// matchesQuery reports whether query names sym (see Resolve)
+func matchesQuery(sym types.Symbol, query string) bool {
+	id := sym.ID()
+	local := strings.TrimPrefix(id, sym.Package+".")
+	return id == query || local == query || strings.HasSuffix(id, "/"+query)
+}
+
+// SearchSymbols finds symbols whose name contains query (case-insensitive).
+// Kind filters by symbol kind when set; exact name matches sort first.
+func (w *Workspace) SearchSymbols(query, kind string, limit int) []types.Symbol {
+	lower := strings.ToLower(query)
+
+	var matches []types.Symbol
+	for _, entry := range w.symbols {
+		sym := entry.symbol
+		if kind != "" && sym.Kind != kind {
+			continue
+		}
+		if strings.Contains(strings.ToLower(sym.Name), lower) {
+			matches = append(matches, sym)
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		iExact := strings.EqualFold(matches[i].Name, query)
+		jExact := strings.EqualFold(matches[j].Name, query)
+		if iExact != jExact {
+			return iExact
+		}
+		return matches[i].ID() < matches[j].ID()
+	})
+
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+// FindCallers lists every use of the named symbol across the module
+func (w *Workspace) FindCallers(query string) (types.Symbol, []types.Caller, error) {
+	entry, err := w.resolve(query)
+	if err != nil {
+		return types.Symbol{}, nil, err
+	}
+
+	var callers []types.Caller
+	for _, pkg := range w.locator.pkgs {
+		for _, astFile := range pkg.Syntax {
+			ast.Inspect(astFile, func(n ast.Node) bool {
+				ident, ok := n.(*ast.Ident)
+				if !ok || pkg.TypesInfo.Uses[ident] != entry.obj {
+					return true
+				}
+
+				pos := w.locator.fset.Position(ident.Pos())
+				callers = append(callers, types.Caller{
+					File:     pos.Filename,
+					Line:     pos.Line,
+					Function: w.enclosingFunction(astFile, ident),
+					Context:  sourceLine(pos.Filename, pos.Line),
+				})
+				return true
+			})
+		}
+	}
+
+	return entry.symbol, callers, nil
+}
+
+// Implementations queries the module's implementation index. For an
+// interface it returns the concrete types satisfying it; for a concrete type,
+// the interfaces it satisfies.
+func (w *Workspace) Implementations(query string) (types.Symbol, []types.Implementation, error) {
+	entry, err := w.resolve(query)
+	if err != nil {
+		return types.Symbol{}, nil, err
+	}
+
+	typeName, ok := entry.obj.(*gotypes.TypeName)
+	if !ok {
+		return types.Symbol{}, nil, fmt.Errorf("%s is not a type", entry.symbol.ID())
+	}
+
+	index := w.locator.implementationIndex()
+	var impls []types.Implementation
+	if gotypes.IsInterface(typeName.Type()) {
+		impls = index.Implementations(entry.symbol)
+	} else {
+		impls = index.Interfaces(entry.symbol)
+	}
+
+	// Prefer the workspace's symbols, which carry roles
+	result := make([]types.Implementation, len(impls))
+	for i, impl := range impls {
+		impl.Interface = w.indexed(impl.Interface)
+		impl.Type = w.indexed(impl.Type)
+		result[i] = impl
+	}
+	return entry.symbol, result, nil
+}
+
+// indexed returns the workspace's copy of a declared symbol, or sym itself
+func (w *Workspace) indexed(sym types.Symbol) types.Symbol {
+	id := sym.ID()
+	for _, entry := range w.symbols {
+		if entry.symbol.ID() == id {
+			return entry.symbol
+		}
+	}
+	return sym
+}
+
+// Warnings lists problems that left queries incomplete without failing
+// them, such as a rules file that could not be read
+func (w *Workspace) Warnings() []string {
+	if _, warning := w.locator.diDetector(); warning != "" {
+		return []string{warning}
+	}
+	return nil
+}
+
+// DIGraph detects the module's DI frameworks, per package, and analyzes
+// their bindings
+func (w *Workspace) DIGraph() (*types.DIGraph, error) {
+	detector, _ := w.locator.diDetector()
+	return &types.DIGraph{
+		Frameworks: detector.DetectFrameworks(),
+		Packages:   detector.PackageFrameworks(),
+		Bindings:   detector.AnalyzeDIBindings(w.Symbols()),
+		Warnings:   w.Warnings(),
+	}, nil
+}
+
+// CheckDI validates the module's DI container graphs
+func (w *Workspace) CheckDI() ([]types.DIIssue, error) {
+	detector, _ := w.locator.diDetector()
+	return detector.CheckBindings(w.Symbols()), nil
+}
+
+// enclosingFunction names the function declaration containing node ("Recv.Name" for methods)
+func (w *Workspace) enclosingFunction(file *ast.File, node ast.Node) string {
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || node.Pos() < fn.Pos() || node.End() > fn.End() {
+			continue
+		}
+		if fn.Recv != nil && len(fn.Recv.List) > 0 {
+			recv := w.locator.exprToString(fn.Recv.List[0].Type)
+			return types.ReceiverBase(recv) + "." + fn.Name.Name
+		}
+		return fn.Name.Name
+	}
+	return ""
+}
+
+// sourceLine returns one trimmed line of a file
+func sourceLine(file string, line int) string {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return ""
+	}
+	lines := strings.Split(string(content), "\n")
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+	return strings.TrimSpace(lines[line-1])
+}
@@ -0,0 +1,300 @@
+package extract
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/extract-scope-go/go-scope/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// loadExample loads one of the example modules as a workspace
+func loadExample(t *testing.T, name string) *Workspace {
+	t.Helper()
+	ws, err := LoadWorkspace(filepath.Join("..", "..", "examples", name))
+	require.NoError(t, err)
+	return ws
+}
+
+// extractAt extracts the symbol at a position of a workspace file
+func extractAt(t *testing.T, ws *Workspace, opts types.Options, line, column int, file ...string) *types.Extract {
+	t.Helper()
+	target := types.Target{Root: ws.Root(), File: filepath.Join(append([]string{ws.Root()}, file...)...), Line: line, Column: column}
+	result, err := ws.Extract(context.Background(), target, opts)
+	require.NoError(t, err)
+	return &result.Extract
+}
+
+// TestWorkspaceResolve tests resolving symbol names of varying qualification
+func TestWorkspaceResolve(t *testing.T) {
+	// Given: The hexagonal example module
+	ws := loadExample(t, "ex2")
+
+	// When/Then: Bare, method, and package-qualified names resolve
+	sym, err := ws.Resolve("NewUserService")
+	require.NoError(t, err)
+	assert.Equal(t, "example.com/ex2/internal/app.NewUserService", sym.ID())
+
+	sym, err = ws.Resolve("UserService.Rename")
+	require.NoError(t, err)
+	assert.Equal(t, "method", sym.Kind)
+
+	sym, err = ws.Resolve("email.Notifier")
+	require.NoError(t, err)
+	assert.Equal(t, "struct", sym.Kind)
+
+	// Two types are named Notifier
+	_, err = ws.Resolve("Notifier")
+	assert.ErrorContains(t, err, "ambiguous")
+
+	_, err = ws.Resolve("Missing")
+	assert.ErrorContains(t, err, "not found")
+}
+
+// TestWorkspaceSearchSymbols tests substring search with exact matches first
+func TestWorkspaceSearchSymbols(t *testing.T) {
+	// Given: The hexagonal example module
+	ws := loadExample(t, "ex2")
+
+	// When: We search for "notifier"
+	all := ws.SearchSymbols("notifier", "", 0)
+	interfaces := ws.SearchSymbols("notifier", "interface", 0)
+	limited := ws.SearchSymbols("notifier", "", 1)
+
+	// Then: Exact names come before NewNotifier, and filters apply
+	require.Len(t, all, 3)
+	assert.Equal(t, "Notifier", all[0].Name)
+	assert.Equal(t, "Notifier", all[1].Name)
+	assert.Equal(t, "NewNotifier", all[2].Name)
+
+	require.Len(t, interfaces, 1)
+	assert.Equal(t, "example.com/ex2/internal/domain", interfaces[0].Package)
+	assert.Len(t, limited, 1)
+}
+
+// TestWorkspaceFindCallers tests finding every use of a symbol
+func TestWorkspaceFindCallers(t *testing.T) {
+	// Given: The hexagonal example module
+	ws := loadExample(t, "ex2")
+
+	// When: We look for uses of the repository constructor
+	sym, callers, err := ws.FindCallers("memory.NewRepository")
+
+	// Then: main is the only caller
+	require.NoError(t, err)
+	assert.Equal(t, "NewRepository", sym.Name)
+	require.Len(t, callers, 1)
+	assert.Equal(t, "main", callers[0].Function)
+	assert.Equal(t, "repo := memory.NewRepository()", callers[0].Context)
+	assert.Equal(t, "main.go", filepath.Base(callers[0].File))
+}
+
+// TestWorkspaceImplementations tests listing implementations of an interface
+func TestWorkspaceImplementations(t *testing.T) {
+	// Given: The hexagonal example module
+	ws := loadExample(t, "ex2")
+
+	// When: We list implementations of the repository port
+	iface, impls, err := ws.Implementations("UserRepository")
+
+	// Then: The in-memory adapter implements it
+	require.NoError(t, err)
+	assert.Equal(t, "interface", iface.Kind)
+	require.Len(t, impls, 1)
+	assert.Equal(t, "example.com/ex2/internal/adapters/memory.Repository", impls[0].Type.ID())
+	assert.True(t, impls[0].Pointer)
+	assert.Equal(t, "secondary-adapter", impls[0].Type.Role)
+
+	// Concrete types list the interfaces they implement
+	_, ifaces, err := ws.Implementations("memory.Repository")
+	require.NoError(t, err)
+	require.Len(t, ifaces, 1)
+	assert.Equal(t, "example.com/ex2/internal/domain.UserRepository", ifaces[0].Interface.ID())
+}
+
+// TestWorkspaceExtract tests that extraction reuses the loaded packages
+func TestWorkspaceExtract(t *testing.T) {
+	// Given: A warm workspace for example 1
+	ws := loadExample(t, "ex1")
+	file := filepath.Join(ws.Root(), "pkg", "math", "add.go")
+
+	// When: We extract Add twice
+	for i := 0; i < 2; i++ {
+		result, err := ws.Extract(context.Background(), types.Target{File: file, Line: 7, Column: 1}, types.Options{Depth: 1})
+
+		// Then: Each extraction is formatted and skips package loading
+		require.NoError(t, err)
+		assert.Equal(t, "Add", result.Extract.Target.Name)
+		assert.Contains(t, result.Rendered, "validateInputs")
+		assert.Less(t, result.Metadata.Timings.Load, result.Metadata.Timings.Total)
+	}
+}
+
+// TestWorkspaceRefreshNewPackage tests that Refresh notices a package added after load
+func TestWorkspaceRefreshNewPackage(t *testing.T) {
+	// Given: A loaded copy of example 1
+	root := t.TempDir()
+	require.NoError(t, os.CopyFS(root, os.DirFS(filepath.Join("..", "..", "examples", "ex1"))))
+	ws, err := LoadWorkspace(root)
+	require.NoError(t, err)
+	_, err = ws.Resolve("Subtract")
+	require.Error(t, err)
+
+	// When: A new package is written and the workspace refreshed
+	dir := filepath.Join(root, "pkg", "ops")
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ops.go"), []byte("package ops\n\nfunc Subtract(a, b int) int { return a - b }\n"), 0o644))
+	require.NoError(t, ws.Refresh())
+
+	// Then: Its symbols are indexed
+	sym, err := ws.Resolve("Subtract")
+	require.NoError(t, err)
+	assert.Equal(t, "ops", filepath.Base(sym.Package))
+}
+
+// TestWorkspaceVCSState tests that git runs once while the checkout is unchanged, not once per extraction
+func TestWorkspaceVCSState(t *testing.T) {
+	// Given: A warm workspace whose VCS state was read by a first extraction
+	ws := loadExample(t, "ex1")
+	target := types.Target{File: filepath.Join(ws.Root(), "pkg", "math", "add.go"), Line: 7, Column: 1}
+	_, err := ws.Extract(context.Background(), target, types.Options{})
+	require.NoError(t, err)
+	require.NotNil(t, ws.locator.vcs)
+
+	// When: We extract again
+	ws.locator.vcs = &vcsInfo{revision: "cached"}
+	result, err := ws.Extract(context.Background(), target, types.Options{})
+
+	// Then: The state read at load is reused
+	require.NoError(t, err)
+	assert.Equal(t, "cached", result.Metadata.VCSRevision)
+}
+
+// TestWorkspaceVCSStateCommit tests that a commit made after loading is reported
+func TestWorkspaceVCSStateCommit(t *testing.T) {
+	// Given: A workspace in a git checkout of the first example
+	dir := t.TempDir()
+	require.NoError(t, os.CopyFS(dir, os.DirFS(filepath.Join("..", "..", "examples", "ex1"))))
+	git := func(args ...string) string {
+		cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		out, err := cmd.Output()
+		require.NoError(t, err)
+		return strings.TrimSpace(string(out))
+	}
+	git("init", "-q")
+	git("add", "-A")
+	git("commit", "-q", "-m", "first")
+	ws, err := LoadWorkspace(dir)
+	require.NoError(t, err)
+	target := types.Target{File: filepath.Join(dir, "pkg", "math", "add.go"), Line: 7, Column: 1}
+	first, err := ws.Extract(context.Background(), target, types.Options{})
+	require.NoError(t, err)
+
+	// When: A commit is made without touching the sources, and we extract again
+	git("commit", "-q", "--allow-empty", "-m", "second")
+	require.NoError(t, ws.Refresh())
+	second, err := ws.Extract(context.Background(), target, types.Options{})
+
+	// Then: The new revision is reported
+	require.NoError(t, err)
+	assert.Equal(t, git("rev-parse", "HEAD"), second.Metadata.VCSRevision)
+	assert.NotEqual(t, first.Metadata.VCSRevision, second.Metadata.VCSRevision)
+}
+
+// TestWorkspaceDIGraph tests that the DI graph includes registries configured in .goscope.yaml
+func TestWorkspaceDIGraph(t *testing.T) {
+	// Given: The example mixing dig, samber/do and a configured plugin registry
+	ws := loadExample(t, "ex6")
+
+	// When: We build its DI graph
+	graph, err := ws.DIGraph()
+	require.NoError(t, err)
+
+	// Then: Each framework is reported with the packages using it
+	assert.Equal(t, []string{"dig", "do", "plugins"}, graph.Frameworks)
+	assert.Contains(t, graph.Packages, types.DIPackage{Package: "example.com/ex6/internal/plugins", Frameworks: []string{"plugins"}})
+	assert.Contains(t, graph.Packages, types.DIPackage{Package: "example.com/ex6/internal/web", Frameworks: []string{"dig"}})
+
+	frameworks := make(map[string]bool)
+	for _, binding := range graph.Bindings {
+		frameworks[binding.Framework] = true
+	}
+	assert.True(t, frameworks["dig"] && frameworks["do"] && frameworks["plugins"])
+}
+
+// TestWorkspaceBrokenRulesFile tests that an unreadable .goscope.yaml degrades DI analysis to a warning
+func TestWorkspaceBrokenRulesFile(t *testing.T) {
+	// Given: A copy of example 6 whose rules file does not parse
+	root := t.TempDir()
+	require.NoError(t, os.CopyFS(root, os.DirFS(filepath.Join("..", "..", "examples", "ex6"))))
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".goscope.yaml"), []byte("di: [registries\n"), 0o644))
+	ws, err := LoadWorkspace(root)
+	require.NoError(t, err)
+
+	// When: We extract a symbol and build the DI graph
+	target := types.Target{File: filepath.Join(root, "internal", "web", "web.go"), Line: 37, Column: 1}
+	result, err := ws.Extract(context.Background(), target, types.Options{Depth: 1})
+	require.NoError(t, err)
+	graph, err := ws.DIGraph()
+	require.NoError(t, err)
+
+	// Then: Both succeed with the built-in frameworks and report the broken file
+	assert.Equal(t, "NewHandlers", result.Extract.Target.Name)
+	require.Len(t, result.Metadata.Warnings, 1)
+	assert.Contains(t, result.Metadata.Warnings[0], ".goscope.yaml")
+	assert.Contains(t, result.Rendered, "- Warning: DI registries ignored:")
+	assert.Equal(t, []string{"dig", "do"}, graph.Frameworks)
+	assert.Equal(t, result.Metadata.Warnings, graph.Warnings)
+	assert.Equal(t, graph.Warnings, ws.Warnings())
+}
+
+// TestWorkspaceDIObjectGraphs tests building object graphs from main with resolved implementations
+func TestWorkspaceDIObjectGraphs(t *testing.T) {
+	// Given: The Wire example, whose main calls the production injector
+	ws := loadExample(t, "ex5")
+
+	// When: We build the object graphs of main
+	graphs, err := ws.DIObjectGraphs("main")
+	require.NoError(t, err)
+
+	// Then: The injector's graph passes the SQL store as the repository
+	require.Len(t, graphs, 1)
+	assert.Equal(t, "InitializeServer", graphs[0].Root.Name)
+	var repository *types.DIObjectEdge
+	for i, edge := range graphs[0].Edges {
+		if edge.Value.Type == "example.com/ex5/internal/store.Repository" {
+			repository = &graphs[0].Edges[i]
+		}
+	}
+	require.NotNil(t, repository)
+	require.NotNil(t, repository.Implementation)
+	assert.Equal(t, "example.com/ex5/internal/store.SQLStore", repository.Implementation.ID())
+}
+
+// TestWorkspaceDIObjectGraphsConstructor tests resolving implementations from interface constructors
+func TestWorkspaceDIObjectGraphsConstructor(t *testing.T) {
+	// Given: The Fx example, whose constructors return the Repository interface
+	ws := loadExample(t, "ex4")
+
+	// When: We build the object graphs of the app's main
+	graphs, err := ws.DIObjectGraphs("app.main")
+	require.NoError(t, err)
+
+	// Then: Each repository resolves to the type its constructor instantiates
+	require.Len(t, graphs, 1)
+	implementations := make(map[string]string)
+	for _, edge := range graphs[0].Edges {
+		if edge.Implementation != nil {
+			implementations[edge.To] = edge.Implementation.Name
+		}
+	}
+	assert.Equal(t, map[string]string{
+		"example.com/ex4/internal/users.NewStore": "Store",
+		"example.com/ex4/internal/users.NewCache": "Cache",
+	}, implementations)
+}
@@ -0,0 +1,190 @@
+package jsonrpc
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Standard JSON-RPC 2.0 error codes
+const (
+	ParseError     = -32700
+	InvalidRequest = -32600
+	MethodNotFound = -32601
+	InvalidParams  = -32602
+	InternalError  = -32603
+)
+
+// Message is a JSON-RPC 2.0 request, notification or response
+type Message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Error is a JSON-RPC 2.0 error object
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	return fmt.Sprintf("jsonrpc error %d: %s", e.Code, e.Message)
+}
+
+// IsNotification reports whether the message is a request that expects no response
+func (m *Message) IsNotification() bool {
+	return m.Method != "" && len(m.ID) == 0
+}
+
+// NewResponse builds a successful response to the request with the given ID
+func NewResponse(id json.RawMessage, result any) (*Message, error) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode result: %w", err)
+	}
+	return &Message{JSONRPC: "2.0", ID: id, Result: data}, nil
+}
+
+// NewErrorResponse builds an error response to the request with the given ID
+func NewErrorResponse(id json.RawMessage, code int, message string) *Message {
+	return &Message{JSONRPC: "2.0", ID: id, Error: &Error{Code: code, Message: message}}
+}
+
+// NewNotification builds a notification (or, with an ID, a request) to the peer
+func NewNotification(method string, params any) (*Message, error) {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode params: %w", err)
+	}
+	return &Message{JSONRPC: "2.0", Method: method, Params: data}, nil
+}
+
+// Stream reads and writes framed messages
+type Stream interface {
+	Read() (*Message, error)
+	Write(*Message) error
+}
+
+// headerStream frames messages with Content-Length headers, as LSP does
+type headerStream struct {
+	r  *bufio.Reader
+	w  io.Writer
+	mu sync.Mutex
+}
+
+// NewHeaderStream creates a stream using LSP base protocol framing
+func NewHeaderStream(r io.Reader, w io.Writer) Stream {
+	return &headerStream{r: bufio.NewReader(r), w: w}
+}
+
+// Read reads one Content-Length framed message
+func (s *headerStream) Read() (*Message, error) {
+	length := -1
+	for {
+		line, err := s.r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("malformed header: %q", line)
+		}
+		if strings.EqualFold(strings.TrimSpace(name), "Content-Length") {
+			length, err = strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return nil, fmt.Errorf("invalid Content-Length: %w", err)
+			}
+		}
+	}
+
+	if length < 0 {
+		return nil, fmt.Errorf("missing Content-Length header")
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(s.r, body); err != nil {
+		return nil, err
+	}
+
+	return decode(body)
+}
+
+// Write writes one Content-Length framed message
+func (s *headerStream) Write(msg *Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := fmt.Fprintf(s.w, "Content-Length: %d\r\n\r\n", len(data)); err != nil {
+		return err
+	}
+	_, err = s.w.Write(data)
+	return err
+}
+
+// decode parses a message body, reporting malformed JSON as a ParseError
+func decode(data []byte) (*Message, error) {
+	var msg Message
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return nil, &Error{Code: ParseError, Message: err.Error()}
+	}
+	return &msg, nil
+}
+
+// lineStream frames messages as newline-delimited JSON, as MCP's stdio transport does
+type lineStream struct {
+	r  *bufio.Reader
+	w  io.Writer
+	mu sync.Mutex
+}
+
+// NewLineStream creates a stream with one JSON message per line
+func NewLineStream(r io.Reader, w io.Writer) Stream {
+	return &lineStream{r: bufio.NewReader(r), w: w}
+}
+
+// Read reads one line-delimited message, skipping blank lines
+func (s *lineStream) Read() (*Message, error) {
+	for {
+		line, err := s.r.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			return decode(line)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Write writes one message followed by a newline
+func (s *lineStream) Write(msg *Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err = s.w.Write(append(data, '\n'))
+	return err
+}
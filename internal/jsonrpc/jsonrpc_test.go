@@ -0,0 +1,115 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestHeaderStreamRoundTrip tests Content-Length framing in both directions
+func TestHeaderStreamRoundTrip(t *testing.T) {
+	// Given: A stream writing into a buffer
+	var buf bytes.Buffer
+	out := NewHeaderStream(strings.NewReader(""), &buf)
+
+	// When: We write two messages and read them back
+	req := &Message{JSONRPC: "2.0", ID: json.RawMessage(`1`), Method: "initialize", Params: json.RawMessage(`{"rootUri":"file:///src"}`)}
+	resp, err := NewResponse(json.RawMessage(`1`), map[string]string{"name": "go-scope"})
+	require.NoError(t, err)
+	require.NoError(t, out.Write(req))
+	require.NoError(t, out.Write(resp))
+
+	assert.True(t, strings.HasPrefix(buf.String(), "Content-Length: "))
+
+	in := NewHeaderStream(&buf, nil)
+	first, err := in.Read()
+	require.NoError(t, err)
+	second, err := in.Read()
+	require.NoError(t, err)
+
+	// Then: They should be unchanged
+	assert.Equal(t, "initialize", first.Method)
+	assert.JSONEq(t, `{"rootUri":"file:///src"}`, string(first.Params))
+	assert.JSONEq(t, `{"name":"go-scope"}`, string(second.Result))
+	assert.False(t, first.IsNotification())
+}
+
+// TestHeaderStreamErrors tests malformed frames
+func TestHeaderStreamErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"missing length", "Content-Type: application/json\r\n\r\n{}"},
+		{"bad length", "Content-Length: ten\r\n\r\n{}"},
+		{"malformed header", "nonsense\r\n\r\n{}"},
+		{"short body", "Content-Length: 10\r\n\r\n{}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewHeaderStream(strings.NewReader(tt.input), nil).Read()
+			assert.Error(t, err)
+		})
+	}
+
+	// Invalid JSON is reported as a JSON-RPC parse error
+	_, err := NewHeaderStream(strings.NewReader("Content-Length: 3\r\n\r\n{x}"), nil).Read()
+	var rpcErr *Error
+	require.ErrorAs(t, err, &rpcErr)
+	assert.Equal(t, ParseError, rpcErr.Code)
+}
+
+// TestLineStreamRoundTrip tests newline-delimited framing in both directions
+func TestLineStreamRoundTrip(t *testing.T) {
+	// Given: A stream writing into a buffer
+	var buf bytes.Buffer
+	out := NewLineStream(strings.NewReader(""), &buf)
+
+	// When: We write two messages, add blank lines, and read them back
+	note, err := NewNotification("notifications/initialized", struct{}{})
+	require.NoError(t, err)
+	resp, err := NewResponse(json.RawMessage(`"a"`), []int{1, 2})
+	require.NoError(t, err)
+	require.NoError(t, out.Write(note))
+	buf.WriteString("\n\n")
+	require.NoError(t, out.Write(resp))
+
+	assert.Equal(t, 4, strings.Count(buf.String(), "\n"))
+
+	in := NewLineStream(&buf, nil)
+	first, err := in.Read()
+	require.NoError(t, err)
+	second, err := in.Read()
+	require.NoError(t, err)
+	_, err = in.Read()
+
+	// Then: They should be unchanged, followed by EOF
+	assert.True(t, first.IsNotification())
+	assert.Equal(t, "notifications/initialized", first.Method)
+	assert.JSONEq(t, `[1,2]`, string(second.Result))
+	assert.ErrorIs(t, err, io.EOF)
+
+	// A final message without a trailing newline is still read
+	last, err := NewLineStream(strings.NewReader(`{"jsonrpc":"2.0","method":"ping","id":3}`), nil).Read()
+	require.NoError(t, err)
+	assert.Equal(t, "ping", last.Method)
+}
+
+// TestIsNotification tests distinguishing notifications from requests
+func TestIsNotification(t *testing.T) {
+	note, err := NewNotification("initialized", struct{}{})
+	require.NoError(t, err)
+	assert.True(t, note.IsNotification())
+
+	req := &Message{Method: "shutdown", ID: json.RawMessage(`2`)}
+	assert.False(t, req.IsNotification())
+
+	resp := NewErrorResponse(json.RawMessage(`3`), MethodNotFound, "nope")
+	assert.False(t, resp.IsNotification())
+}
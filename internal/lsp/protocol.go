@@ -0,0 +1,119 @@
+package lsp
+
+import "encoding/json"
+
+// LSP-specific error codes
+const (
+	serverNotInitialized = -32002
+	requestFailed        = -32803
+)
+
+// The subset of the Language Server Protocol used by go-scope
+
+type position struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+type lspRange struct {
+	Start position `json:"start"`
+	End   position `json:"end"`
+}
+
+type textDocumentIdentifier struct {
+	URI string `json:"uri"`
+}
+
+type textDocumentPositionParams struct {
+	TextDocument textDocumentIdentifier `json:"textDocument"`
+	Position     position               `json:"position"`
+}
+
+type initializeParams struct {
+	RootURI      string `json:"rootUri,omitempty"`
+	Capabilities struct {
+		Window struct {
+			ShowDocument struct {
+				Support bool `json:"support"`
+			} `json:"showDocument"`
+		} `json:"window"`
+	} `json:"capabilities"`
+}
+
+type initializeResult struct {
+	Capabilities serverCapabilities `json:"capabilities"`
+	ServerInfo   serverInfo         `json:"serverInfo"`
+}
+
+type serverInfo struct {
+	Name string `json:"name"`
+}
+
+type serverCapabilities struct {
+	HoverProvider          bool                         `json:"hoverProvider"`
+	CodeActionProvider     bool                         `json:"codeActionProvider"`
+	ExecuteCommandProvider *executeCommandOptions       `json:"executeCommandProvider,omitempty"`
+	Workspace              *workspaceServerCapabilities `json:"workspace,omitempty"`
+}
+
+type executeCommandOptions struct {
+	Commands []string `json:"commands"`
+}
+
+type workspaceServerCapabilities struct {
+	TextDocumentContent *textDocumentContentOptions `json:"textDocumentContent,omitempty"`
+}
+
+type textDocumentContentOptions struct {
+	Schemes []string `json:"schemes"`
+}
+
+type markupContent struct {
+	Kind  string `json:"kind"`
+	Value string `json:"value"`
+}
+
+type hover struct {
+	Contents markupContent `json:"contents"`
+}
+
+type codeActionParams struct {
+	TextDocument textDocumentIdentifier `json:"textDocument"`
+	Range        lspRange               `json:"range"`
+}
+
+type codeAction struct {
+	Title   string   `json:"title"`
+	Kind    string   `json:"kind,omitempty"`
+	Command *command `json:"command,omitempty"`
+}
+
+type command struct {
+	Title     string            `json:"title"`
+	Command   string            `json:"command"`
+	Arguments []json.RawMessage `json:"arguments,omitempty"`
+}
+
+type executeCommandParams struct {
+	Command   string            `json:"command"`
+	Arguments []json.RawMessage `json:"arguments,omitempty"`
+}
+
+type textDocumentContentParams struct {
+	URI string `json:"uri"`
+}
+
+type textDocumentContentResult struct {
+	Text string `json:"text"`
+}
+
+type showDocumentParams struct {
+	URI       string `json:"uri"`
+	TakeFocus bool   `json:"takeFocus,omitempty"`
+}
+
+// extractDocument is the result of goscope/extract and the extract command
+type extractDocument struct {
+	URI      string `json:"uri"`
+	Markdown string `json:"markdown"`
+}
@@ -0,0 +1,466 @@
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode/utf16"
+
+	"github.com/extract-scope-go/go-scope/internal/extract"
+	"github.com/extract-scope-go/go-scope/internal/jsonrpc"
+	"github.com/extract-scope-go/go-scope/internal/types"
+)
+
+// ExtractCommand is the workspace/executeCommand name for "Extract scope here"
+const ExtractCommand = "goscope.extract"
+
+// DocumentScheme is the URI scheme of the virtual documents holding extracts
+const DocumentScheme = "goscope"
+
+// Server answers LSP requests from an editor over a JSON-RPC stream
+type Server struct {
+	stream jsonrpc.Stream
+	opts   types.Options
+
+	mu          sync.Mutex
+	workspaces  map[string]*extract.Workspace // Loaded modules by root, refreshed before each use
+	docs        map[string]string             // Virtual document contents by URI
+	showDocs    bool                          // Client supports window/showDocument
+	nextID      int                           // ID of the next server-to-client request
+	shutdown    bool
+	initialized bool
+}
+
+// NewServer creates an LSP server; opts are used for "Extract scope here"
+func NewServer(stream jsonrpc.Stream, opts types.Options) *Server {
+	return &Server{
+		stream:     stream,
+		opts:       opts,
+		workspaces: make(map[string]*extract.Workspace),
+		docs:       make(map[string]string),
+	}
+}
+
+// Run serves requests until the client sends exit or closes the stream
+func (s *Server) Run(ctx context.Context) error {
+	for {
+		msg, err := s.stream.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			var rpcErr *jsonrpc.Error
+			if errors.As(err, &rpcErr) {
+				s.reply(jsonrpc.NewErrorResponse(nil, rpcErr.Code, rpcErr.Message))
+				continue
+			}
+			return err
+		}
+
+		// Responses to our own requests (window/showDocument) need no handling
+		if msg.Method == "" {
+			continue
+		}
+
+		if msg.Method == "exit" {
+			if !s.shutdown {
+				return fmt.Errorf("exit before shutdown")
+			}
+			return nil
+		}
+
+		result, rpcErr := s.handle(ctx, msg)
+		if msg.IsNotification() {
+			continue
+		}
+
+		if rpcErr != nil {
+			s.reply(jsonrpc.NewErrorResponse(msg.ID, rpcErr.Code, rpcErr.Message))
+			continue
+		}
+
+		resp, err := jsonrpc.NewResponse(msg.ID, result)
+		if err != nil {
+			resp = jsonrpc.NewErrorResponse(msg.ID, jsonrpc.InternalError, err.Error())
+		}
+		s.reply(resp)
+	}
+}
+
+// handle dispatches one request or notification
+func (s *Server) handle(ctx context.Context, msg *jsonrpc.Message) (any, *jsonrpc.Error) {
+	if !s.initialized && msg.Method != "initialize" {
+		return nil, &jsonrpc.Error{Code: serverNotInitialized, Message: "server not initialized"}
+	}
+
+	switch msg.Method {
+	case "initialize":
+		var params initializeParams
+		if err := unmarshalParams(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		return s.initialize(params), nil
+
+	case "initialized":
+		return nil, nil
+
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+
+	case "textDocument/hover":
+		var params textDocumentPositionParams
+		if err := unmarshalParams(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		return s.hover(ctx, params), nil
+
+	case "textDocument/codeAction":
+		var params codeActionParams
+		if err := unmarshalParams(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		return s.codeActions(params), nil
+
+	case "workspace/executeCommand":
+		var params executeCommandParams
+		if err := unmarshalParams(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		return s.executeCommand(ctx, params)
+
+	case "workspace/textDocumentContent":
+		var params textDocumentContentParams
+		if err := unmarshalParams(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		return s.documentContent(params)
+
+	case "goscope/extract":
+		var params textDocumentPositionParams
+		if err := unmarshalParams(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		return s.extractAt(ctx, params)
+
+	default:
+		if msg.IsNotification() || strings.HasPrefix(msg.Method, "$/") {
+			return nil, nil
+		}
+		return nil, &jsonrpc.Error{Code: jsonrpc.MethodNotFound, Message: "method not found: " + msg.Method}
+	}
+}
+
+// initialize records client capabilities and advertises ours
+func (s *Server) initialize(params initializeParams) initializeResult {
+	s.initialized = true
+	s.showDocs = params.Capabilities.Window.ShowDocument.Support
+
+	return initializeResult{
+		Capabilities: serverCapabilities{
+			HoverProvider:      true,
+			CodeActionProvider: true,
+			ExecuteCommandProvider: &executeCommandOptions{
+				Commands: []string{ExtractCommand},
+			},
+			Workspace: &workspaceServerCapabilities{
+				TextDocumentContent: &textDocumentContentOptions{Schemes: []string{DocumentScheme}},
+			},
+		},
+		ServerInfo: serverInfo{Name: "go-scope"},
+	}
+}
+
+// hover returns a card with the symbol's depth-1 dependencies and interface mappings
+func (s *Server) hover(ctx context.Context, params textDocumentPositionParams) *hover {
+	target, err := targetFromPosition(params.TextDocument.URI, params.Position)
+	if err != nil {
+		return nil
+	}
+
+	opts := s.opts
+	opts.Depth = 1
+	opts.Format = "markdown"
+	result, err := s.extract(ctx, target, opts)
+	if err != nil {
+		// No symbol under the cursor is not an error for hover
+		return nil
+	}
+
+	return &hover{Contents: markupContent{Kind: "markdown", Value: hoverCard(result.Extract)}}
+}
+
+// extract extracts a target from the workspace of its module, loading the
+// module on first use and reloading it when its sources changed
+func (s *Server) extract(ctx context.Context, target types.Target, opts types.Options) (*types.Result, error) {
+	s.mu.Lock()
+	ws, ok := s.workspaces[target.Root]
+	s.mu.Unlock()
+
+	if ok {
+		if err := ws.Refresh(); err != nil {
+			return nil, err
+		}
+	} else {
+		var err error
+		if ws, err = extract.LoadWorkspace(target.Root); err != nil {
+			return nil, err
+		}
+		s.mu.Lock()
+		s.workspaces[target.Root] = ws
+		s.mu.Unlock()
+	}
+
+	return ws.Extract(ctx, target, opts)
+}
+
+// codeActions offers "Extract scope here" at the requested range
+func (s *Server) codeActions(params codeActionParams) []codeAction {
+	args, _ := json.Marshal(textDocumentPositionParams{
+		TextDocument: params.TextDocument,
+		Position:     params.Range.Start,
+	})
+
+	return []codeAction{{
+		Title: "Extract scope here",
+		Kind:  "source",
+		Command: &command{
+			Title:     "Extract scope here",
+			Command:   ExtractCommand,
+			Arguments: []json.RawMessage{args},
+		},
+	}}
+}
+
+// executeCommand runs "Extract scope here" and opens the result
+func (s *Server) executeCommand(ctx context.Context, params executeCommandParams) (any, *jsonrpc.Error) {
+	if params.Command != ExtractCommand {
+		return nil, &jsonrpc.Error{Code: jsonrpc.InvalidParams, Message: "unknown command: " + params.Command}
+	}
+	if len(params.Arguments) != 1 {
+		return nil, &jsonrpc.Error{Code: jsonrpc.InvalidParams, Message: ExtractCommand + " takes one position argument"}
+	}
+
+	var position textDocumentPositionParams
+	if err := unmarshalParams(params.Arguments[0], &position); err != nil {
+		return nil, err
+	}
+
+	doc, rpcErr := s.extractAt(ctx, position)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	if s.showDocs {
+		s.showDocument(doc.URI)
+	}
+
+	return doc, nil
+}
+
+// extractAt extracts the symbol at a position into a virtual markdown document
+func (s *Server) extractAt(ctx context.Context, params textDocumentPositionParams) (*extractDocument, *jsonrpc.Error) {
+	target, err := targetFromPosition(params.TextDocument.URI, params.Position)
+	if err != nil {
+		return nil, &jsonrpc.Error{Code: jsonrpc.InvalidParams, Message: err.Error()}
+	}
+
+	opts := s.opts
+	opts.Format = "markdown"
+	result, err := s.extract(ctx, target, opts)
+	if err != nil {
+		return nil, &jsonrpc.Error{Code: requestFailed, Message: err.Error()}
+	}
+
+	doc := &extractDocument{
+		URI:      documentURI(target),
+		Markdown: result.Rendered,
+	}
+
+	s.mu.Lock()
+	s.docs[doc.URI] = doc.Markdown
+	s.mu.Unlock()
+
+	return doc, nil
+}
+
+// documentContent serves a virtual document produced by an earlier extraction
+func (s *Server) documentContent(params textDocumentContentParams) (any, *jsonrpc.Error) {
+	s.mu.Lock()
+	text, ok := s.docs[params.URI]
+	s.mu.Unlock()
+
+	if !ok {
+		return nil, &jsonrpc.Error{Code: jsonrpc.InvalidParams, Message: "unknown document: " + params.URI}
+	}
+	return textDocumentContentResult{Text: text}, nil
+}
+
+// showDocument asks the client to open a virtual document
+func (s *Server) showDocument(uri string) {
+	msg, err := jsonrpc.NewNotification("window/showDocument", showDocumentParams{URI: uri, TakeFocus: true})
+	if err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	s.nextID++
+	msg.ID = json.RawMessage(fmt.Sprintf(`"goscope-%d"`, s.nextID))
+	s.mu.Unlock()
+
+	s.reply(msg)
+}
+
+// reply writes a message to the client, ignoring a closed stream
+func (s *Server) reply(msg *jsonrpc.Message) {
+	_ = s.stream.Write(msg)
+}
+
+// hoverCard summarises an extract for a hover popup
+func hoverCard(ext types.Extract) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "**%s** (%s)", ext.Target.Name, ext.Target.Kind)
+	if ext.Target.Package != "" {
+		fmt.Fprintf(&b, " — `%s`", ext.Target.Package)
+	}
+	b.WriteString("\n\n")
+
+	var deps []string
+	seen := make(map[string]bool)
+	for _, ref := range ext.References {
+		if ref.Depth != 1 || ref.Reason != "direct-call" || seen[ref.Symbol.ID()] || ref.Symbol.ID() == ext.Target.ID() {
+			continue
+		}
+		seen[ref.Symbol.ID()] = true
+
+		name := ref.Symbol.Name
+		if ref.External {
+			name = ref.Symbol.Package + "." + name
+		}
+		deps = append(deps, fmt.Sprintf("- `%s` (%s)", name, ref.Symbol.Kind))
+	}
+
+	if len(deps) > 0 {
+		b.WriteString("Dependencies:\n")
+		b.WriteString(strings.Join(deps, "\n"))
+		b.WriteString("\n\n")
+	}
+
+	if len(ext.InterfaceMappings) > 0 {
+		b.WriteString("Interfaces:\n")
+		for _, mapping := range ext.InterfaceMappings {
+			var impls []string
+			for _, impl := range mapping.Implementations {
+				impls = append(impls, "`"+impl.Name+"`")
+			}
+			fmt.Fprintf(&b, "- `%s`", mapping.Interface.Name)
+			if len(impls) > 0 {
+				fmt.Fprintf(&b, " ← %s", strings.Join(impls, ", "))
+			}
+			if mapping.Constructor != nil {
+				fmt.Fprintf(&b, " (constructor `%s`)", mapping.Constructor.Name)
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// targetFromPosition converts an LSP document position to an extraction target.
+// LSP positions are 0-based with characters counted in UTF-16 code units;
+// go-scope targets are 1-based with byte columns.
+func targetFromPosition(uri string, pos position) (types.Target, error) {
+	file, err := uriToPath(uri)
+	if err != nil {
+		return types.Target{}, err
+	}
+
+	root, err := findModuleRoot(file)
+	if err != nil {
+		return types.Target{}, err
+	}
+
+	column := pos.Character + 1
+	if content, err := os.ReadFile(file); err == nil {
+		lines := strings.Split(string(content), "\n")
+		if pos.Line < len(lines) {
+			column = byteColumn(lines[pos.Line], pos.Character)
+		}
+	}
+
+	return types.Target{
+		Root:   root,
+		File:   file,
+		Line:   pos.Line + 1,
+		Column: column,
+	}, nil
+}
+
+// byteColumn converts a UTF-16 offset into a line to a 1-based byte column
+func byteColumn(line string, character int) int {
+	units := 0
+	for i, r := range line {
+		if units >= character {
+			return i + 1
+		}
+		units += utf16.RuneLen(r)
+	}
+	return len(line) + 1
+}
+
+// documentURI names the virtual document for an extraction target
+func documentURI(target types.Target) string {
+	u := url.URL{
+		Scheme:   DocumentScheme,
+		Path:     "/extract" + filepath.ToSlash(target.File) + ".md",
+		RawQuery: fmt.Sprintf("line=%d&column=%d", target.Line, target.Column),
+	}
+	return u.String()
+}
+
+// uriToPath converts a file:// URI to a local path
+func uriToPath(uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", fmt.Errorf("invalid document URI: %w", err)
+	}
+	if u.Scheme != "file" {
+		return "", fmt.Errorf("unsupported document URI scheme: %s", u.Scheme)
+	}
+	return filepath.FromSlash(u.Path), nil
+}
+
+// findModuleRoot returns the nearest directory above file containing go.mod
+func findModuleRoot(file string) (string, error) {
+	dir := filepath.Dir(file)
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("no go.mod found above %s", file)
+		}
+		dir = parent
+	}
+}
+
+// unmarshalParams decodes request parameters, reporting failures as InvalidParams
+func unmarshalParams(data json.RawMessage, v any) *jsonrpc.Error {
+	if len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return &jsonrpc.Error{Code: jsonrpc.InvalidParams, Message: err.Error()}
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"github.com/extract-scope-go/go-scope/internal/jsonrpc"
+	"github.com/extract-scope-go/go-scope/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testClient drives a Server over in-memory pipes
+type testClient struct {
+	t      *testing.T
+	stream jsonrpc.Stream
+	nextID int
+	done   chan error
+}
+
+// startServer runs a server against ex1 and returns a client connected to it
+func startServer(t *testing.T) *testClient {
+	clientRead, serverWrite := io.Pipe()
+	serverRead, clientWrite := io.Pipe()
+
+	server := NewServer(jsonrpc.NewHeaderStream(serverRead, serverWrite), types.Options{Depth: 1})
+	done := make(chan error, 1)
+	go func() {
+		done <- server.Run(context.Background())
+		serverWrite.Close()
+	}()
+
+	t.Cleanup(func() {
+		clientWrite.Close()
+	})
+
+	return &testClient{t: t, stream: jsonrpc.NewHeaderStream(clientRead, clientWrite), done: done}
+}
+
+// call sends a request and returns its response, skipping server-initiated requests
+func (c *testClient) call(method string, params any) *jsonrpc.Message {
+	c.nextID++
+	msg, err := jsonrpc.NewNotification(method, params)
+	require.NoError(c.t, err)
+	msg.ID = json.RawMessage(fmt.Sprint(c.nextID))
+	require.NoError(c.t, c.stream.Write(msg))
+
+	for {
+		resp, err := c.stream.Read()
+		require.NoError(c.t, err)
+		if resp.Method == "" {
+			return resp
+		}
+	}
+}
+
+// notify sends a notification
+func (c *testClient) notify(method string, params any) {
+	msg, err := jsonrpc.NewNotification(method, params)
+	require.NoError(c.t, err)
+	require.NoError(c.t, c.stream.Write(msg))
+}
+
+// addGoURI returns the file:// URI of ex1's add.go
+func addGoURI(t *testing.T) string {
+	abs, err := filepath.Abs(filepath.Join("..", "..", "examples", "ex1", "pkg", "math", "add.go"))
+	require.NoError(t, err)
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(abs)}).String()
+}
+
+// TestInitializeCapabilities tests the advertised capabilities
+func TestInitializeCapabilities(t *testing.T) {
+	client := startServer(t)
+
+	resp := client.call("initialize", map[string]any{"capabilities": map[string]any{}})
+	require.Nil(t, resp.Error)
+
+	var result initializeResult
+	require.NoError(t, json.Unmarshal(resp.Result, &result))
+	assert.True(t, result.Capabilities.HoverProvider)
+	assert.True(t, result.Capabilities.CodeActionProvider)
+	assert.Equal(t, []string{ExtractCommand}, result.Capabilities.ExecuteCommandProvider.Commands)
+	assert.Equal(t, []string{DocumentScheme}, result.Capabilities.Workspace.TextDocumentContent.Schemes)
+	assert.Equal(t, "go-scope", result.ServerInfo.Name)
+}
+
+// TestRequestBeforeInitialize tests that requests are refused until initialize
+func TestRequestBeforeInitialize(t *testing.T) {
+	client := startServer(t)
+
+	resp := client.call("textDocument/hover", map[string]any{})
+
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, serverNotInitialized, resp.Error.Code)
+}
+
+// TestHoverListsDependencies tests the hover card for Add
+func TestHoverListsDependencies(t *testing.T) {
+	client := startServer(t)
+	client.call("initialize", map[string]any{})
+	client.notify("initialized", map[string]any{})
+
+	// Given: The cursor on "func Add" (line 7, 0-based 6)
+	resp := client.call("textDocument/hover", textDocumentPositionParams{
+		TextDocument: textDocumentIdentifier{URI: addGoURI(t)},
+		Position:     position{Line: 6, Character: 5},
+	})
+
+	// Then: The card should list Add's direct dependencies
+	require.Nil(t, resp.Error)
+	var card hover
+	require.NoError(t, json.Unmarshal(resp.Result, &card))
+	assert.Equal(t, "markdown", card.Contents.Kind)
+	assert.Contains(t, card.Contents.Value, "**Add** (func)")
+	assert.Contains(t, card.Contents.Value, "`validateInputs` (func)")
+	assert.Contains(t, card.Contents.Value, "`fmt.Println` (func)")
+
+	// And: Hovering where there is no symbol returns null
+	resp = client.call("textDocument/hover", textDocumentPositionParams{
+		TextDocument: textDocumentIdentifier{URI: addGoURI(t)},
+		Position:     position{Line: 2, Character: 0},
+	})
+	require.Nil(t, resp.Error)
+	assert.Equal(t, "null", string(resp.Result))
+}
+
+// TestExtractScopeHere tests the code action, command and virtual document round trip
+func TestExtractScopeHere(t *testing.T) {
+	client := startServer(t)
+	client.call("initialize", map[string]any{})
+
+	// Given: The code action offered on Add
+	resp := client.call("textDocument/codeAction", codeActionParams{
+		TextDocument: textDocumentIdentifier{URI: addGoURI(t)},
+		Range:        lspRange{Start: position{Line: 6, Character: 0}, End: position{Line: 6, Character: 0}},
+	})
+	require.Nil(t, resp.Error)
+
+	var actions []codeAction
+	require.NoError(t, json.Unmarshal(resp.Result, &actions))
+	require.Len(t, actions, 1)
+	assert.Equal(t, "Extract scope here", actions[0].Title)
+
+	// When: The editor runs its command
+	resp = client.call("workspace/executeCommand", executeCommandParams{
+		Command:   actions[0].Command.Command,
+		Arguments: actions[0].Command.Arguments,
+	})
+
+	// Then: The extract markdown comes back in a virtual document
+	require.Nil(t, resp.Error)
+	var doc extractDocument
+	require.NoError(t, json.Unmarshal(resp.Result, &doc))
+	assert.Contains(t, doc.URI, DocumentScheme+":")
+	assert.Contains(t, doc.Markdown, "# Code Extract: Add")
+	assert.Contains(t, doc.Markdown, "func validateInputs")
+
+	// And: The document can be fetched again by URI
+	resp = client.call("workspace/textDocumentContent", textDocumentContentParams{URI: doc.URI})
+	require.Nil(t, resp.Error)
+	var content textDocumentContentResult
+	require.NoError(t, json.Unmarshal(resp.Result, &content))
+	assert.Equal(t, doc.Markdown, content.Text)
+}
+
+// TestShutdownAndExit tests the orderly shutdown sequence
+func TestShutdownAndExit(t *testing.T) {
+	client := startServer(t)
+	client.call("initialize", map[string]any{})
+
+	resp := client.call("shutdown", nil)
+	require.Nil(t, resp.Error)
+
+	client.notify("exit", nil)
+	assert.NoError(t, <-client.done)
+}
+
+// TestUnknownMethod tests the MethodNotFound error
+func TestUnknownMethod(t *testing.T) {
+	client := startServer(t)
+	client.call("initialize", map[string]any{})
+
+	resp := client.call("textDocument/rename", map[string]any{})
+
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, jsonrpc.MethodNotFound, resp.Error.Code)
+}
+
+// TestExtractReusesWorkspace tests that the module is loaded once across requests
+func TestExtractReusesWorkspace(t *testing.T) {
+	// Given: A server and the position of Add
+	server := NewServer(nil, types.Options{Depth: 1})
+	target, err := targetFromPosition(addGoURI(t), position{Line: 6, Character: 5})
+	require.NoError(t, err)
+
+	// When: We extract twice
+	_, err = server.extract(context.Background(), target, server.opts)
+	require.NoError(t, err)
+	ws := server.workspaces[target.Root]
+	result, err := server.extract(context.Background(), target, server.opts)
+
+	// Then: The second extraction runs against the same loaded workspace
+	require.NoError(t, err)
+	assert.Equal(t, "Add", result.Extract.Target.Name)
+	require.Len(t, server.workspaces, 1)
+	assert.Same(t, ws, server.workspaces[target.Root])
+}
+
+// TestByteColumn tests converting UTF-16 positions to byte columns
+func TestByteColumn(t *testing.T) {
+	// Given: A line with a two-byte rune and a surrogate pair before the call
+	line := `	s := "é😀" + f()`
+
+	// When/Then: Characters after them map to their byte offsets
+	assert.Equal(t, 1, byteColumn(line, 0))
+	assert.Equal(t, 8, byteColumn(line, 7))   // é
+	assert.Equal(t, 10, byteColumn(line, 8))  // 😀
+	assert.Equal(t, 14, byteColumn(line, 10)) // closing quote
+	assert.Equal(t, 18, byteColumn(line, 14)) // f
+	assert.Equal(t, len(line)+1, byteColumn(line, 100))
+}
@@ -0,0 +1,162 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/extract-scope-go/go-scope/internal/extract"
+	"github.com/extract-scope-go/go-scope/internal/jsonrpc"
+	"github.com/extract-scope-go/go-scope/internal/types"
+)
+
+// Server answers MCP requests from an AI assistant over a JSON-RPC stream,
+// running tools against a workspace that stays loaded between calls
+type Server struct {
+	stream jsonrpc.Stream
+	ws     *extract.Workspace
+	opts   types.Options
+}
+
+// NewServer creates an MCP server for a loaded workspace; opts are the
+// defaults for extract_symbol
+func NewServer(stream jsonrpc.Stream, ws *extract.Workspace, opts types.Options) *Server {
+	return &Server{
+		stream: stream,
+		ws:     ws,
+		opts:   opts,
+	}
+}
+
+// Run serves requests until the client closes the stream
+func (s *Server) Run(ctx context.Context) error {
+	for {
+		msg, err := s.stream.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			var rpcErr *jsonrpc.Error
+			if errors.As(err, &rpcErr) {
+				s.reply(jsonrpc.NewErrorResponse(nil, rpcErr.Code, rpcErr.Message))
+				continue
+			}
+			return err
+		}
+
+		// Responses to server requests are never expected
+		if msg.Method == "" {
+			continue
+		}
+
+		result, rpcErr := s.handle(ctx, msg)
+		if msg.IsNotification() {
+			continue
+		}
+
+		if rpcErr != nil {
+			s.reply(jsonrpc.NewErrorResponse(msg.ID, rpcErr.Code, rpcErr.Message))
+			continue
+		}
+
+		resp, err := jsonrpc.NewResponse(msg.ID, result)
+		if err != nil {
+			resp = jsonrpc.NewErrorResponse(msg.ID, jsonrpc.InternalError, err.Error())
+		}
+		s.reply(resp)
+	}
+}
+
+// handle dispatches one request or notification
+func (s *Server) handle(ctx context.Context, msg *jsonrpc.Message) (any, *jsonrpc.Error) {
+	switch msg.Method {
+	case "initialize":
+		var params initializeParams
+		if err := unmarshalParams(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		return s.initialize(params), nil
+
+	case "ping":
+		return struct{}{}, nil
+
+	case "tools/list":
+		return listToolsResult{Tools: toolList()}, nil
+
+	case "tools/call":
+		var params callToolParams
+		if err := unmarshalParams(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		return s.callTool(ctx, params)
+
+	default:
+		if msg.IsNotification() {
+			return nil, nil
+		}
+		return nil, &jsonrpc.Error{Code: jsonrpc.MethodNotFound, Message: "method not found: " + msg.Method}
+	}
+}
+
+// initialize negotiates the protocol version and advertises the tools capability
+func (s *Server) initialize(params initializeParams) initializeResult {
+	version := ProtocolVersion
+	for _, supported := range supportedVersions {
+		if params.ProtocolVersion == supported {
+			version = supported
+			break
+		}
+	}
+
+	return initializeResult{
+		ProtocolVersion: version,
+		Capabilities:    serverCapabilities{Tools: &toolsCapability{}},
+		ServerInfo:      implementation{Name: "go-scope"},
+		Instructions: fmt.Sprintf("Tools for exploring the Go module at %s. Symbols are named bare (Add), "+
+			"as methods (Store.Get), or qualified by package (math.Add, example.com/app/math.Add).", s.ws.Root()),
+	}
+}
+
+// callTool runs a tool. Tool failures are results with IsError set, so the
+// assistant can see and react to them; only unknown tools are protocol errors.
+func (s *Server) callTool(ctx context.Context, params callToolParams) (any, *jsonrpc.Error) {
+	handler, ok := findTool(params.Name)
+	if !ok {
+		return nil, &jsonrpc.Error{Code: jsonrpc.InvalidParams, Message: "unknown tool: " + params.Name}
+	}
+
+	text, err := s.runTool(ctx, handler, params.Arguments)
+	if err != nil {
+		return callToolResult{Content: []content{{Type: "text", Text: err.Error()}}, IsError: true}, nil
+	}
+	return callToolResult{Content: []content{{Type: "text", Text: text}}}, nil
+}
+
+// runTool refreshes the workspace if sources changed, then runs the handler
+func (s *Server) runTool(ctx context.Context, handler toolHandler, args json.RawMessage) (string, error) {
+	if err := s.ws.Refresh(); err != nil {
+		return "", err
+	}
+	if len(args) == 0 {
+		args = json.RawMessage(`{}`)
+	}
+	return handler(ctx, s, args)
+}
+
+// reply writes a message to the client, ignoring a closed stream
+func (s *Server) reply(msg *jsonrpc.Message) {
+	_ = s.stream.Write(msg)
+}
+
+// unmarshalParams decodes request parameters, reporting failures as InvalidParams
+func unmarshalParams(data json.RawMessage, v any) *jsonrpc.Error {
+	if len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return &jsonrpc.Error{Code: jsonrpc.InvalidParams, Message: err.Error()}
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/extract-scope-go/go-scope/internal/extract"
+	"github.com/extract-scope-go/go-scope/internal/jsonrpc"
+	"github.com/extract-scope-go/go-scope/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testClient drives a Server over in-memory pipes
+type testClient struct {
+	t      *testing.T
+	stream jsonrpc.Stream
+	nextID int
+}
+
+// startServer runs a server on an example module and returns a connected client
+func startServer(t *testing.T, example string) *testClient {
+	ws, err := extract.LoadWorkspace(filepath.Join("..", "..", "examples", example))
+	require.NoError(t, err)
+
+	clientRead, serverWrite := io.Pipe()
+	serverRead, clientWrite := io.Pipe()
+
+	server := NewServer(jsonrpc.NewLineStream(serverRead, serverWrite), ws, types.Options{Depth: 1})
+	go func() {
+		_ = server.Run(context.Background())
+		serverWrite.Close()
+	}()
+
+	t.Cleanup(func() {
+		clientWrite.Close()
+	})
+
+	return &testClient{t: t, stream: jsonrpc.NewLineStream(clientRead, clientWrite)}
+}
+
+// call sends a request and returns its response
+func (c *testClient) call(method string, params any) *jsonrpc.Message {
+	c.nextID++
+	msg, err := jsonrpc.NewNotification(method, params)
+	require.NoError(c.t, err)
+	msg.ID = json.RawMessage(fmt.Sprint(c.nextID))
+	require.NoError(c.t, c.stream.Write(msg))
+
+	resp, err := c.stream.Read()
+	require.NoError(c.t, err)
+	return resp
+}
+
+// callTool calls a tool and returns its result
+func (c *testClient) callTool(name string, args map[string]any) callToolResult {
+	resp := c.call("tools/call", map[string]any{"name": name, "arguments": args})
+	require.Nil(c.t, resp.Error)
+
+	var result callToolResult
+	require.NoError(c.t, json.Unmarshal(resp.Result, &result))
+	require.Len(c.t, result.Content, 1)
+	assert.Equal(c.t, "text", result.Content[0].Type)
+	return result
+}
+
+// TestInitialize tests version negotiation and the tools capability
+func TestInitialize(t *testing.T) {
+	client := startServer(t, "ex1")
+
+	tests := []struct {
+		requested string
+		expected  string
+	}{
+		{"2025-03-26", "2025-03-26"},
+		{"1999-01-01", ProtocolVersion},
+	}
+
+	for _, tt := range tests {
+		resp := client.call("initialize", map[string]any{
+			"protocolVersion": tt.requested,
+			"capabilities":    map[string]any{},
+			"clientInfo":      map[string]any{"name": "test", "version": "1.0"},
+		})
+		require.Nil(t, resp.Error)
+
+		var result initializeResult
+		require.NoError(t, json.Unmarshal(resp.Result, &result))
+		assert.Equal(t, tt.expected, result.ProtocolVersion)
+		assert.NotNil(t, result.Capabilities.Tools)
+		assert.Equal(t, "go-scope", result.ServerInfo.Name)
+	}
+
+	// Ping works at any time, unknown methods do not
+	assert.Nil(t, client.call("ping", nil).Error)
+	resp := client.call("resources/list", nil)
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, jsonrpc.MethodNotFound, resp.Error.Code)
+}
+
+// TestToolsList tests that every tool is advertised with a valid input schema
+func TestToolsList(t *testing.T) {
+	client := startServer(t, "ex1")
+
+	resp := client.call("tools/list", nil)
+	require.Nil(t, resp.Error)
+
+	var result listToolsResult
+	require.NoError(t, json.Unmarshal(resp.Result, &result))
+
+	var names []string
+	for _, tool := range result.Tools {
+		names = append(names, tool.Name)
+		var schema map[string]any
+		require.NoError(t, json.Unmarshal(tool.InputSchema, &schema), tool.Name)
+		assert.Equal(t, "object", schema["type"], tool.Name)
+	}
+	assert.Equal(t, []string{"extract_symbol", "find_callers", "list_implementations", "search_symbols", "di_graph"}, names)
+}
+
+// TestExtractSymbolTool tests extraction by name and by position
+func TestExtractSymbolTool(t *testing.T) {
+	client := startServer(t, "ex1")
+
+	// When: We extract Add by name and by file and line
+	byName := client.callTool("extract_symbol", map[string]any{"symbol": "math.Add"})
+	byPos := client.callTool("extract_symbol", map[string]any{"file": "pkg/math/add.go", "line": 7, "depth": 0, "format": "json"})
+
+	// Then: Both use the formatters' output
+	require.False(t, byName.IsError, byName.Content[0].Text)
+	assert.Contains(t, byName.Content[0].Text, "# Code Extract: Add")
+	assert.Contains(t, byName.Content[0].Text, "validateInputs")
+
+	require.False(t, byPos.IsError, byPos.Content[0].Text)
+	var graph map[string]any
+	require.NoError(t, json.Unmarshal([]byte(byPos.Content[0].Text), &graph))
+	assert.Contains(t, graph, "target")
+
+	// Missing arguments are tool errors, not protocol errors
+	missing := client.callTool("extract_symbol", map[string]any{})
+	assert.True(t, missing.IsError)
+	assert.Contains(t, missing.Content[0].Text, "required")
+}
+
+// TestQueryTools tests callers, implementations, search and DI graph on the hexagonal example
+func TestQueryTools(t *testing.T) {
+	client := startServer(t, "ex2")
+
+	callers := client.callTool("find_callers", map[string]any{"symbol": "NewUserService"})
+	require.False(t, callers.IsError, callers.Content[0].Text)
+	assert.Contains(t, callers.Content[0].Text, "cmd/app/main.go:18 in `main`")
+
+	impls := client.callTool("list_implementations", map[string]any{"interface": "domain.Notifier", "format": "json"})
+	require.False(t, impls.IsError, impls.Content[0].Text)
+	var implResult struct {
+		Implementations []types.Symbol `json:"implementations"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(impls.Content[0].Text), &implResult))
+	require.Len(t, implResult.Implementations, 1)
+	assert.Equal(t, "example.com/ex2/internal/adapters/email.Notifier", implResult.Implementations[0].ID())
+	assert.Empty(t, implResult.Implementations[0].Code)
+
+	search := client.callTool("search_symbols", map[string]any{"query": "user", "kind": "interface"})
+	require.False(t, search.IsError, search.Content[0].Text)
+	assert.Contains(t, search.Content[0].Text, "`example.com/ex2/internal/domain.UserRepository` (interface)")
+	assert.NotContains(t, search.Content[0].Text, "UserService")
+
+	graph := client.callTool("di_graph", nil)
+	require.False(t, graph.IsError, graph.Content[0].Text)
+	assert.Contains(t, graph.Content[0].Text, "# DI Graph (manual)")
+	assert.Contains(t, graph.Content[0].Text, "`example.com/ex2/internal/app.NewUserService` → `example.com/ex2/internal/app.UserService`")
+
+	ambiguous := client.callTool("find_callers", map[string]any{"symbol": "Notifier"})
+	assert.True(t, ambiguous.IsError)
+	assert.Contains(t, ambiguous.Content[0].Text, "ambiguous")
+
+	resp := client.call("tools/call", map[string]any{"name": "rm_rf"})
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, jsonrpc.InvalidParams, resp.Error.Code)
+}
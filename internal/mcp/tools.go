@@ -0,0 +1,350 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/extract-scope-go/go-scope/internal/extract/format"
+	"github.com/extract-scope-go/go-scope/internal/types"
+)
+
+// toolHandler runs a tool with its JSON arguments and returns the text answer
+type toolHandler func(ctx context.Context, s *Server, args json.RawMessage) (string, error)
+
+// toolDefinition pairs an advertised tool with its handler
+type toolDefinition struct {
+	tool
+	handler toolHandler
+}
+
+// formatProperty is the input schema of the shared "format" argument
+const formatProperty = `"format": {"type": "string", "enum": ["markdown", "json"], "description": "Output format (default: markdown)"}`
+
+// tools are the tools advertised by tools/list, in order
+var tools = []toolDefinition{
+	{
+		tool: tool{
+			Name: "extract_symbol",
+			Description: "Extract a Go symbol's code together with its dependencies up to a depth, " +
+				"plus interface implementations and DI bindings. Identify the symbol by name or by file and line.",
+			InputSchema: json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "symbol": {"type": "string", "description": "Symbol name, e.g. Add, Store.Get or math.Add"},
+    "file": {"type": "string", "description": "Source file, relative to the module root (instead of symbol)"},
+    "line": {"type": "integer", "minimum": 1, "description": "1-based line in file"},
+    "column": {"type": "integer", "minimum": 1, "description": "1-based column in file (default: 1)"},
+    "depth": {"type": "integer", "minimum": 0, "description": "Dependency depth (0 = target only)"},
+    "maxTokens": {"type": "integer", "minimum": 0, "description": "Trim the extract to about this many tokens"},
+    "taint": {"type": "boolean", "description": "Report untrusted input (requests, environment, files) reaching SQL, commands, templates or file paths through the symbol"},
+    "format": {"type": "string", "enum": ["markdown", "json", "result"], "description": "markdown (default), json (graph) or result (full versioned result)"}
+  }
+}`),
+		},
+		handler: extractSymbol,
+	},
+	{
+		tool: tool{
+			Name:        "find_callers",
+			Description: "List every place in the module that calls or references a symbol, with the enclosing function and source line.",
+			InputSchema: json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "symbol": {"type": "string", "description": "Symbol name, e.g. NewStore or store.Store.Get"},
+    ` + formatProperty + `
+  },
+  "required": ["symbol"]
+}`),
+		},
+		handler: findCallers,
+	},
+	{
+		tool: tool{
+			Name:        "list_implementations",
+			Description: "List the module's concrete types that implement an interface.",
+			InputSchema: json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "interface": {"type": "string", "description": "Interface name, e.g. Repository or domain.Repository"},
+    ` + formatProperty + `
+  },
+  "required": ["interface"]
+}`),
+		},
+		handler: listImplementations,
+	},
+	{
+		tool: tool{
+			Name:        "search_symbols",
+			Description: "Search the module's declared functions, methods, types, variables and constants by name (case-insensitive substring).",
+			InputSchema: json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "query": {"type": "string", "description": "Part of the symbol name"},
+    "kind": {"type": "string", "enum": ["func", "method", "type", "struct", "interface", "var", "const"], "description": "Only return symbols of this kind"},
+    "limit": {"type": "integer", "minimum": 1, "description": "Maximum number of results (default: 50)"},
+    ` + formatProperty + `
+  },
+  "required": ["query"]
+}`),
+		},
+		handler: searchSymbols,
+	},
+	{
+		tool: tool{
+			Name:        "di_graph",
+			Description: "Show the module's dependency injection frameworks, which packages use each, and their bindings: each provider, what it provides and what it needs.",
+			InputSchema: json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    ` + formatProperty + `
+  }
+}`),
+		},
+		handler: diGraph,
+	},
+}
+
+// defaultSearchLimit caps search_symbols results when no limit is given
+const defaultSearchLimit = 50
+
+// toolList returns the advertised tools
+func toolList() []tool {
+	list := make([]tool, len(tools))
+	for i, def := range tools {
+		list[i] = def.tool
+	}
+	return list
+}
+
+// findTool returns the handler of the named tool
+func findTool(name string) (toolHandler, bool) {
+	for _, def := range tools {
+		if def.Name == name {
+			return def.handler, true
+		}
+	}
+	return nil, false
+}
+
+// extractSymbol runs an extraction and returns the formatter's output
+func extractSymbol(ctx context.Context, s *Server, raw json.RawMessage) (string, error) {
+	var args struct {
+		Symbol    string `json:"symbol"`
+		File      string `json:"file"`
+		Line      int    `json:"line"`
+		Column    int    `json:"column"`
+		Depth     *int   `json:"depth"`
+		MaxTokens int    `json:"maxTokens"`
+		Taint     bool   `json:"taint"`
+		Format    string `json:"format"`
+	}
+	if err := decodeArgs(raw, &args); err != nil {
+		return "", err
+	}
+
+	target := types.Target{Root: s.ws.Root(), File: args.File, Line: args.Line, Column: args.Column}
+	switch {
+	case args.Symbol != "":
+		sym, err := s.ws.Resolve(args.Symbol)
+		if err != nil {
+			return "", err
+		}
+		target.File, target.Line, target.Column = sym.File, sym.Line, sym.Column
+	case args.File == "" || args.Line == 0:
+		return "", fmt.Errorf("either symbol or file and line are required")
+	case !filepath.IsAbs(args.File):
+		target.File = filepath.Join(s.ws.Root(), args.File)
+	}
+	if target.Column == 0 {
+		target.Column = 1
+	}
+
+	opts := s.opts
+	if args.Depth != nil {
+		opts.Depth = *args.Depth
+	}
+	if args.MaxTokens > 0 {
+		opts.MaxTokens = args.MaxTokens
+	}
+	if args.Taint {
+		opts.Taint = true
+	}
+	switch args.Format {
+	case "", "markdown":
+		opts.Format = "markdown"
+	case "json", "result":
+		opts.Format = args.Format
+	default:
+		return "", fmt.Errorf("unsupported format: %s", args.Format)
+	}
+
+	result, err := s.ws.Extract(ctx, target, opts)
+	if err != nil {
+		return "", err
+	}
+	return result.Rendered, nil
+}
+
+// findCallers lists the uses of a symbol
+func findCallers(ctx context.Context, s *Server, raw json.RawMessage) (string, error) {
+	var args struct {
+		Symbol string `json:"symbol"`
+		Format string `json:"format"`
+	}
+	if err := decodeArgs(raw, &args); err != nil {
+		return "", err
+	}
+	if args.Symbol == "" {
+		return "", fmt.Errorf("symbol is required")
+	}
+
+	sym, callers, err := s.ws.FindCallers(args.Symbol)
+	if err != nil {
+		return "", err
+	}
+
+	return render(args.Format, func() string {
+		return format.CallersMarkdown(sym, callers, s.ws.Root())
+	}, struct {
+		Symbol  types.Symbol   `json:"symbol"`
+		Callers []types.Caller `json:"callers"`
+	}{brief(sym), emptyIfNil(callers)})
+}
+
+// listImplementations lists the implementations of an interface
+func listImplementations(ctx context.Context, s *Server, raw json.RawMessage) (string, error) {
+	var args struct {
+		Interface string `json:"interface"`
+		Format    string `json:"format"`
+	}
+	if err := decodeArgs(raw, &args); err != nil {
+		return "", err
+	}
+	if args.Interface == "" {
+		return "", fmt.Errorf("interface is required")
+	}
+
+	iface, found, err := s.ws.Implementations(args.Interface)
+	if err != nil {
+		return "", err
+	}
+	if iface.Kind != "interface" {
+		return "", fmt.Errorf("%s is not an interface", iface.ID())
+	}
+
+	impls := make([]types.Symbol, len(found))
+	for i, impl := range found {
+		impls[i] = impl.Type
+	}
+
+	return render(args.Format, func() string {
+		return format.SymbolsMarkdown("Implementations of "+iface.ID(), impls, s.ws.Root())
+	}, struct {
+		Interface       types.Symbol   `json:"interface"`
+		Implementations []types.Symbol `json:"implementations"`
+	}{brief(iface), briefAll(impls)})
+}
+
+// searchSymbols searches declared symbols by name
+func searchSymbols(ctx context.Context, s *Server, raw json.RawMessage) (string, error) {
+	var args struct {
+		Query  string `json:"query"`
+		Kind   string `json:"kind"`
+		Limit  int    `json:"limit"`
+		Format string `json:"format"`
+	}
+	if err := decodeArgs(raw, &args); err != nil {
+		return "", err
+	}
+	if args.Query == "" {
+		return "", fmt.Errorf("query is required")
+	}
+	if args.Limit <= 0 {
+		args.Limit = defaultSearchLimit
+	}
+
+	symbols := s.ws.SearchSymbols(args.Query, args.Kind, args.Limit)
+
+	return render(args.Format, func() string {
+		return format.SymbolsMarkdown(fmt.Sprintf("Symbols matching %q", args.Query), symbols, s.ws.Root())
+	}, struct {
+		Symbols []types.Symbol `json:"symbols"`
+	}{briefAll(symbols)})
+}
+
+// diGraph shows the module's DI bindings
+func diGraph(ctx context.Context, s *Server, raw json.RawMessage) (string, error) {
+	var args struct {
+		Format string `json:"format"`
+	}
+	if err := decodeArgs(raw, &args); err != nil {
+		return "", err
+	}
+
+	graph, err := s.ws.DIGraph()
+	if err != nil {
+		return "", err
+	}
+	for i := range graph.Bindings {
+		graph.Bindings[i].Provider = brief(graph.Bindings[i].Provider)
+		graph.Bindings[i].Product = brief(graph.Bindings[i].Product)
+		graph.Bindings[i].Dependencies = briefAll(graph.Bindings[i].Dependencies)
+	}
+	if graph.Frameworks == nil {
+		graph.Frameworks = []string{}
+	}
+
+	return render(args.Format, func() string {
+		return format.DIGraphMarkdown(graph)
+	}, graph)
+}
+
+// render returns markdown, or v as indented JSON
+func render(formatName string, markdown func() string, v any) (string, error) {
+	switch formatName {
+	case "", "markdown":
+		return markdown(), nil
+	case "json":
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to encode json: %w", err)
+		}
+		return string(data), nil
+	default:
+		return "", fmt.Errorf("unsupported format: %s", formatName)
+	}
+}
+
+// decodeArgs decodes tool arguments
+func decodeArgs(raw json.RawMessage, v any) error {
+	if err := json.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	return nil
+}
+
+// brief drops a symbol's code, keeping answers to list queries small
+func brief(sym types.Symbol) types.Symbol {
+	sym.Code = ""
+	return sym
+}
+
+// briefAll applies brief to every symbol
+func briefAll(symbols []types.Symbol) []types.Symbol {
+	out := make([]types.Symbol, len(symbols))
+	for i, sym := range symbols {
+		out[i] = brief(sym)
+	}
+	return out
+}
+
+// emptyIfNil makes nil slices encode as [] rather than null
+func emptyIfNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
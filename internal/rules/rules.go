@@ -0,0 +1,198 @@
+package rules
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFile is the rules file looked up in the module root
+const DefaultFile = ".goscope.yaml"
+
+// Config declares architecture layers and the dependencies allowed between
+// them, and how dependency injection is wired
+type Config struct {
+	Layers []Layer `yaml:"layers"`
+	DI     DI      `yaml:"di"`
+}
+
+// Layer groups packages; code in a layer may only depend on its own layer,
+// the layers listed in Allow, and packages outside every layer
+type Layer struct {
+	Name     string   `yaml:"name"`
+	Packages []string `yaml:"packages"` // Import path patterns, absolute or relative to the module ("internal/domain/...")
+	Allow    []string `yaml:"allow"`    // Names of layers this layer may depend on
+}
+
+// DI configures dependency injection detection beyond the built-in containers
+type DI struct {
+	Registries []Registry `yaml:"registries"`
+}
+
+// Registry declares a home-grown registration call, such as
+// registry.Register(name, factory), as a DI container
+type Registry struct {
+	Framework string   `yaml:"framework"` // Name the container is reported as (default "registry")
+	Call      string   `yaml:"call"`      // Function, "example.com/app/registry.Register", or method, "example.com/app/registry.Registry.Register"
+	Args      []string `yaml:"args"`      // Role of each argument: "name", "factory" or "" to ignore (default [name, factory])
+}
+
+// Load reads and validates a rules file
+func Load(file string) (*Config, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules: %w", err)
+	}
+
+	cfg, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	return cfg, nil
+}
+
+// Parse decodes and validates rules YAML
+func Parse(data []byte) (*Config, error) {
+	var cfg Config
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("invalid rules: %w", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// Validate checks that layers are named uniquely, match packages, and only
+// allow layers that exist, and that registries name a call and one factory
+func (c *Config) Validate() error {
+	if len(c.Layers) == 0 && len(c.DI.Registries) == 0 {
+		return fmt.Errorf("invalid rules: no layers declared")
+	}
+
+	for _, registry := range c.DI.Registries {
+		if err := registry.validate(); err != nil {
+			return err
+		}
+	}
+
+	names := make(map[string]bool)
+	for _, layer := range c.Layers {
+		if layer.Name == "" {
+			return fmt.Errorf("invalid rules: layer without a name")
+		}
+		if names[layer.Name] {
+			return fmt.Errorf("invalid rules: duplicate layer %q", layer.Name)
+		}
+		if len(layer.Packages) == 0 {
+			return fmt.Errorf("invalid rules: layer %q has no packages", layer.Name)
+		}
+		for _, pattern := range layer.Packages {
+			if _, err := path.Match(strings.TrimSuffix(pattern, "/..."), ""); err != nil {
+				return fmt.Errorf("invalid rules: layer %q: bad pattern %q", layer.Name, pattern)
+			}
+		}
+		names[layer.Name] = true
+	}
+
+	for _, layer := range c.Layers {
+		for _, allowed := range layer.Allow {
+			if !names[allowed] {
+				return fmt.Errorf("invalid rules: layer %q allows unknown layer %q", layer.Name, allowed)
+			}
+		}
+	}
+	return nil
+}
+
+// validate checks that a registry names its call and exactly one factory
+// argument
+func (r Registry) validate() error {
+	if r.Call == "" {
+		return fmt.Errorf("invalid rules: registry without a call")
+	}
+	if len(r.Args) == 0 {
+		return nil
+	}
+
+	factories := 0
+	for _, arg := range r.Args {
+		switch arg {
+		case "factory":
+			factories++
+		case "name", "":
+		default:
+			return fmt.Errorf("invalid rules: registry %q: unknown argument role %q", r.Call, arg)
+		}
+	}
+	if factories != 1 {
+		return fmt.Errorf("invalid rules: registry %q: want one factory argument, got %d", r.Call, factories)
+	}
+	return nil
+}
+
+// LayerOf returns the first layer whose patterns match the package, or ""
+// if the package is in no layer. Patterns are tried against the full import
+// path and the path relative to modulePath.
+func (c *Config) LayerOf(pkgPath, modulePath string) string {
+	rel := ""
+	if modulePath != "" && strings.HasPrefix(pkgPath, modulePath+"/") {
+		rel = strings.TrimPrefix(pkgPath, modulePath+"/")
+	}
+
+	for _, layer := range c.Layers {
+		for _, pattern := range layer.Packages {
+			if matchPattern(pattern, pkgPath) || rel != "" && matchPattern(pattern, rel) {
+				return layer.Name
+			}
+		}
+	}
+	return ""
+}
+
+// Allows reports whether code in layer from may depend on layer to
+func (c *Config) Allows(from, to string) bool {
+	if from == to || from == "" || to == "" {
+		return true
+	}
+	for _, layer := range c.Layers {
+		if layer.Name != from {
+			continue
+		}
+		for _, allowed := range layer.Allow {
+			if allowed == to {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchPattern matches an import path against a pattern. A trailing "/..."
+// matches the package and everything below it, as with the go command;
+// otherwise path.Match wildcards apply per path element.
+func matchPattern(pattern, pkgPath string) bool {
+	if base, ok := strings.CutSuffix(pattern, "/..."); ok {
+		if pkgPath == base || strings.HasPrefix(pkgPath, base+"/") {
+			return true
+		}
+		// Wildcards in the base match any package below a matching prefix
+		parts := strings.Split(pkgPath, "/")
+		depth := strings.Count(base, "/") + 1
+		if len(parts) < depth {
+			return false
+		}
+		matched, _ := path.Match(base, strings.Join(parts[:depth], "/"))
+		return matched
+	}
+
+	matched, _ := path.Match(pattern, pkgPath)
+	return matched
+}
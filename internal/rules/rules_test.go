@@ -0,0 +1,125 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParse tests decoding a rules file
+func TestParse(t *testing.T) {
+	// Given: Rules for a hexagonal layout
+	data := []byte(`
+layers:
+  - name: domain
+    packages: [internal/domain/...]
+  - name: adapters
+    packages: [internal/adapters/..., example.com/app/pkg/*db]
+    allow: [domain]
+`)
+
+	// When: We parse them
+	cfg, err := Parse(data)
+
+	// Then: Layers and allowed dependencies are loaded
+	require.NoError(t, err)
+	require.Len(t, cfg.Layers, 2)
+	assert.Equal(t, "adapters", cfg.Layers[1].Name)
+	assert.Equal(t, []string{"domain"}, cfg.Layers[1].Allow)
+}
+
+// TestParseRegistries tests decoding DI registries without layers
+func TestParseRegistries(t *testing.T) {
+	// Given: A file declaring only a registration call
+	data := []byte(`
+di:
+  registries:
+    - framework: plugins
+      call: example.com/app/registry.Register
+      args: [name, "", factory]
+`)
+
+	// When: We parse it
+	cfg, err := Parse(data)
+
+	// Then: The registry is loaded and no layers are required
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Layers)
+	require.Len(t, cfg.DI.Registries, 1)
+	assert.Equal(t, Registry{Framework: "plugins", Call: "example.com/app/registry.Register", Args: []string{"name", "", "factory"}}, cfg.DI.Registries[0])
+}
+
+// TestParseInvalid tests that malformed rules are rejected
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		err   string
+	}{
+		{"empty", "", "invalid rules"},
+		{"no layers", "layers: []", "no layers declared"},
+		{"unknown field", "layers: [{name: a, packages: [x], allows: [b]}]", "field allows not found"},
+		{"duplicate", "layers: [{name: a, packages: [x]}, {name: a, packages: [y]}]", `duplicate layer "a"`},
+		{"no packages", "layers: [{name: a}]", `layer "a" has no packages`},
+		{"unknown allow", "layers: [{name: a, packages: [x], allow: [b]}]", `allows unknown layer "b"`},
+		{"bad pattern", "layers: [{name: a, packages: ['x/[']}]", "bad pattern"},
+		{"registry without call", "di: {registries: [{args: [factory]}]}", "registry without a call"},
+		{"unknown role", "di: {registries: [{call: r.Register, args: [key, factory]}]}", `unknown argument role "key"`},
+		{"no factory", "di: {registries: [{call: r.Register, args: [name]}]}", "want one factory argument, got 0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.input))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
+
+// TestLayerOf tests package pattern matching
+func TestLayerOf(t *testing.T) {
+	// Given: Layers using relative, absolute and wildcard patterns
+	cfg := &Config{Layers: []Layer{
+		{Name: "domain", Packages: []string{"internal/domain/..."}},
+		{Name: "adapters", Packages: []string{"example.com/app/internal/adapters/...", "pkg/*db"}},
+		{Name: "cmd", Packages: []string{"*/cmd/..."}},
+	}}
+	module := "example.com/app"
+
+	tests := []struct {
+		pkg      string
+		expected string
+	}{
+		{"example.com/app/internal/domain", "domain"},
+		{"example.com/app/internal/domain/user", "domain"},
+		{"example.com/app/internal/domainx", ""},
+		{"example.com/app/internal/adapters/postgres", "adapters"},
+		{"example.com/app/pkg/mongodb", "adapters"},
+		{"example.com/app/pkg/mongodb/driver", ""},
+		{"example.com/app/tools/cmd/gen", "cmd"},
+		{"example.com/other/internal/domain", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pkg, func(t *testing.T) {
+			assert.Equal(t, tt.expected, cfg.LayerOf(tt.pkg, module))
+		})
+	}
+}
+
+// TestAllows tests dependency direction rules
+func TestAllows(t *testing.T) {
+	// Given: Adapters may use the domain, not the other way round
+	cfg := &Config{Layers: []Layer{
+		{Name: "domain", Packages: []string{"internal/domain/..."}},
+		{Name: "adapters", Packages: []string{"internal/adapters/..."}, Allow: []string{"domain"}},
+	}}
+
+	// Then: Only the declared direction (and same-layer or unlayered code) is allowed
+	assert.True(t, cfg.Allows("adapters", "domain"))
+	assert.False(t, cfg.Allows("domain", "adapters"))
+	assert.True(t, cfg.Allows("domain", "domain"))
+	assert.True(t, cfg.Allows("domain", ""))
+}
@@ -1,132 +1,446 @@
 package types
 
 import (
+	"strings"
 	"time"
 )
 
 // Target specifies what to extract
 type Target struct {
-	Root   string // Module root path
-	File   string // Source file path (relative or absolute)
-	Line   int    // 1-based line number
-	Column int    // 1-based column (default: 1)
+	Root   string `json:"root"`   // Module root path
+	File   string `json:"file"`   // Source file path (relative or absolute)
+	Line   int    `json:"line"`   // 1-based line number
+	Column int    `json:"column"` // 1-based column (default: 1)
 }
 
 // Options configures extraction behavior
 type Options struct {
-	Depth          int    // Dependency depth (default: 1, 0 = target only)
-	Format         string // "markdown", "html", "json" (default: "markdown")
-	StubExternal   bool   // Show signatures for external deps (default: true)
-	ShowCallers    bool   // Include reverse dependencies (default: false)
-	ShowTests      bool   // Include test functions (default: false)
-	ContextLines   int    // Extra lines around target (default: 0)
-	Annotate       bool   // Add inline reference comments (default: true)
-	IncludeMetrics bool   // Compute complexity metrics (default: false)
-	GitBlame       bool   // Include git history (default: false)
+	Depth          int    `json:"depth"`          // Dependency depth (default: 1, 0 = target only)
+	Format         string `json:"format"`         // "markdown", "html", "json", "result" (default: "markdown")
+	StubExternal   bool   `json:"stubExternal"`   // Show signatures for external deps (default: true)
+	ShowCallers    bool   `json:"showCallers"`    // Include reverse dependencies (default: false)
+	ShowTests      bool   `json:"showTests"`      // Include test functions (default: false)
+	ContextLines   int    `json:"contextLines"`   // Extra lines around target (default: 0)
+	Annotate       bool   `json:"annotate"`       // Add inline reference comments (default: true)
+	IncludeMetrics bool   `json:"includeMetrics"` // Compute complexity metrics (default: false)
+	GitBlame       bool   `json:"gitBlame"`       // Include git history (default: false)
+	MaxTokens      int    `json:"maxTokens"`      // Rendered output budget in estimated tokens (0 = unlimited)
+	MaxBytes       int    `json:"maxBytes"`       // Rendered output budget in bytes (0 = unlimited)
+	FullDepth      int    `json:"fullDepth"`      // Deepest level shown as full code; deeper shows signatures (0 = all levels)
+	StubDepth      int    `json:"stubDepth"`      // Deepest level shown as signatures; deeper shows names only (0 = all levels)
+	Taint          bool   `json:"taint"`          // Report untrusted input reaching sinks through the target (default: false)
 }
 
 // Symbol represents a Go symbol (function, type, var, etc.)
 type Symbol struct {
-	Package        string   // Full package path
-	Name           string   // Symbol name
-	Kind           string   // "func", "method", "type", "var", "const", "interface", "struct"
-	Receiver       string   // For methods: receiver type
-	File           string   // Source file path
-	Line           int      // Start line
-	EndLine        int      // End line
-	Column         int      // Start column
-	Code           string   // Source code
-	Doc            string   // Documentation comment
-	Exported       bool     // Whether symbol is exported
-	Implements     []string // For structs: interfaces they implement
-	InterfaceType  string   // For constructors: interface type returned
-	Implementation string   // For constructors: concrete type instantiated
+	Package        string   `json:"package"`                  // Full package path
+	Name           string   `json:"name"`                     // Symbol name
+	Kind           string   `json:"kind"`                     // "func", "method", "type", "var", "const", "interface", "struct"
+	Receiver       string   `json:"receiver,omitempty"`       // For methods: receiver type
+	File           string   `json:"file,omitempty"`           // Source file path
+	Line           int      `json:"line,omitempty"`           // Start line
+	EndLine        int      `json:"endLine,omitempty"`        // End line
+	Column         int      `json:"column,omitempty"`         // Start column
+	Code           string   `json:"code,omitempty"`           // Source code
+	Doc            string   `json:"doc,omitempty"`            // Documentation comment
+	Exported       bool     `json:"exported"`                 // Whether symbol is exported
+	Implements     []string `json:"implements,omitempty"`     // For structs: interfaces they implement
+	InterfaceType  string   `json:"interfaceType,omitempty"`  // For constructors: interface type returned
+	Implementation string   `json:"implementation,omitempty"` // For constructors: concrete type returned ("Map", or "memory.Map" from another package)
+	Role           string   `json:"role,omitempty"`           // Hexagonal role: "driving-port", "driven-port", "primary-adapter", "secondary-adapter", "domain-entity"
+	Effects        []string `json:"effects,omitempty"`        // For functions and methods, including callees: "pure", or any of "reads-globals", "writes-globals", "io", "logs", "panics"
+}
+
+// ID returns the fully qualified identity of the symbol: "pkg.Name", or
+// "pkg.Recv.Name" for methods. Graph nodes and edges are keyed by it.
+func (s Symbol) ID() string {
+	name := s.Name
+	if recv := ReceiverBase(s.Receiver); recv != "" {
+		name = recv + "." + name
+	}
+	if s.Package != "" {
+		return s.Package + "." + name
+	}
+	return name
+}
+
+// ReceiverBase strips pointer and type parameters from a receiver ("*List[T]" -> "List")
+func ReceiverBase(recv string) string {
+	recv = strings.TrimLeft(recv, "*")
+	if i := strings.Index(recv, "["); i >= 0 {
+		recv = recv[:i]
+	}
+	return recv
 }
 
 // Reference represents a dependency
 type Reference struct {
-	Symbol       Symbol // Referenced symbol
-	Reason       string // "direct-call", "type-reference", "field-access", "interface-contract", "implements-interface", "returns-interface", "di-binding", "requires-dep"
-	Depth        int    // 0 = target, 1 = direct dep, etc.
-	External     bool   // True if from different module
-	Stub         bool   // True if only signature included
-	Signature    string // For stubs: type signature
-	ReferencedBy string // Which symbol references this
+	Symbol         Symbol `json:"symbol"`                   // Referenced symbol
+	Reason         string `json:"reason"`                   // "direct-call", "type-reference", "field-access", "interface-contract", "implements-interface", "returns-interface", "di-binding", "requires-dep"
+	Depth          int    `json:"depth"`                    // 0 = target, 1 = direct dep, etc.
+	External       bool   `json:"external"`                 // True if from different module
+	Stub           bool   `json:"stub"`                     // True if only signature included
+	Signature      string `json:"signature,omitempty"`      // For stubs: type signature
+	ReferencedBy   string `json:"referencedBy,omitempty"`   // Which symbol references this (name)
+	ReferencedByID string `json:"referencedById,omitempty"` // Which symbol references this (Symbol.ID)
+	Uses           int    `json:"uses,omitempty"`           // How many times ReferencedBy uses the symbol
+	Render         string `json:"render,omitempty"`         // "full" (default), "signature" (signature + doc), "name" (name only)
 }
 
 // Caller represents a reverse dependency
 type Caller struct {
-	File     string // Source file
-	Line     int    // Call site line
-	Function string // Containing function name
-	Context  string // Code snippet around call
+	File     string `json:"file"`              // Source file
+	Line     int    `json:"line"`              // Call site line
+	Function string `json:"function"`          // Containing function name
+	Context  string `json:"context,omitempty"` // Code snippet around call
+}
+
+// DataFlow is a slice of the statements a value flows through, across
+// function boundaries up to a depth
+type DataFlow struct {
+	Variable  FlowVariable `json:"variable"`  // The value the slice starts from
+	Direction string       `json:"direction"` // "forward" (where it goes), "backward" (where it comes from), "both"
+	Depth     int          `json:"depth"`     // Function boundaries followed
+	Steps     []FlowStep   `json:"steps"`     // Statements the value flows through, by depth then position
+	Functions []Symbol     `json:"functions"` // Functions the slice passes through, with their code
+}
+
+// FlowVariable is the variable, parameter or field a data-flow slice starts from
+type FlowVariable struct {
+	Name     string `json:"name"`               // Variable name
+	Type     string `json:"type"`               // Go type
+	Kind     string `json:"kind"`               // "param", "result", "receiver", "local", "field", "var" (package level)
+	Function string `json:"function,omitempty"` // Enclosing function ID, for parameters and locals
+	File     string `json:"file"`               // Declaring file
+	Line     int    `json:"line"`               // Declaring line
+}
+
+// FlowStep is one statement a value flows through
+type FlowStep struct {
+	Direction string `json:"direction"` // "forward" or "backward"
+	Kind      string `json:"kind"`      // "assign", "param", "call-arg", "call-result", "return", "field-store", "field-load", "alias", "range", "send", "use"
+	Value     string `json:"value"`     // Where the value goes or comes from ("trimmed", "u.Name", "Rename(id)")
+	Function  string `json:"function"`  // Enclosing function ID
+	File      string `json:"file"`      // Source file
+	Line      int    `json:"line"`      // Line number
+	Code      string `json:"code"`      // Source line
+	Depth     int    `json:"depth"`     // Function boundaries crossed from the start
+}
+
+// ErrorPath is one error a function can return, traced to where it is created
+type ErrorPath struct {
+	Origin    string       `json:"origin"`              // "new" (errors.New), "errorf" (fmt.Errorf without %w), "sentinel" (package variable), "type" (error type value), "callee" (error of a call not followed), "param", "expr"
+	Value     string       `json:"value"`               // Origin expression: "errors.New(\"empty\")", "domain.ErrNotFound", "strconv.Atoi"
+	Message   string       `json:"message,omitempty"`   // Error text or format, when constant
+	Sentinel  string       `json:"sentinel,omitempty"`  // ID of the sentinel variable or error type callers can match
+	Function  string       `json:"function"`            // ID of the function creating the error
+	File      string       `json:"file"`                // Origin file
+	Line      int          `json:"line"`                // Origin line
+	Return    int          `json:"return"`              // Line of the target's return statement
+	Wraps     []string     `json:"wraps,omitempty"`     // Formats of the fmt.Errorf calls wrapping it, outermost first
+	Via       []string     `json:"via,omitempty"`       // IDs of the callees returning it to the target, the target's callee first
+	CheckedBy []ErrorCheck `json:"checkedBy,omitempty"` // Callers of the target matching the sentinel
+}
+
+// ErrorCheck is a caller matching an error against a sentinel or error type
+type ErrorCheck struct {
+	Function string `json:"function"` // Caller ID
+	Check    string `json:"check"`    // "errors.Is", "errors.As", "=="
+	File     string `json:"file"`     // Source file
+	Line     int    `json:"line"`     // Line number
+}
+
+// TaintPath is a flow of untrusted input from a source to a sensitive sink
+type TaintPath struct {
+	Source TaintEndpoint `json:"source"` // Where the input enters
+	Sink   TaintEndpoint `json:"sink"`   // Where it is used
+	Chain  []string      `json:"chain"`  // IDs of the functions the input passes through, source first
+	Steps  []FlowStep    `json:"steps"`  // Statements carrying the input from source to sink
+}
+
+// TaintEndpoint is the source or sink of a taint path
+type TaintEndpoint struct {
+	Kind     string `json:"kind"`     // Sources: "http", "grpc", "env", "file"; sinks: "sql", "exec", "template", "path"
+	Value    string `json:"value"`    // Parameter ("r *http.Request") or called function ("s.db.Query")
+	Function string `json:"function"` // Enclosing function ID
+	File     string `json:"file"`     // Source file
+	Line     int    `json:"line"`     // Line number
+	Code     string `json:"code"`     // Source line
+}
+
+// Concurrency summarizes the goroutines, channels, locks, wait groups and
+// context propagation of the target and its references
+type Concurrency struct {
+	Functions []ConcurrencyFunction `json:"functions"`        // Functions with concurrency operations
+	Issues    []ConcurrencyIssue    `json:"issues,omitempty"` // Likely mistakes
+}
+
+// ConcurrencyFunction lists the concurrency operations of one function
+type ConcurrencyFunction struct {
+	Function string            `json:"function"` // Function ID
+	Context  bool              `json:"context"`  // Whether it takes a context.Context
+	Sites    []ConcurrencySite `json:"sites"`    // Operations in source order
+}
+
+// ConcurrencySite is one concurrency operation
+type ConcurrencySite struct {
+	Kind     string `json:"kind"`               // "go", "send", "receive", "select", "close", "make-chan", "lock", "unlock", "rlock", "runlock", "wg-add", "wg-done", "wg-wait", "errgroup-go", "errgroup-wait", "ctx-pass", "ctx-new"
+	Value    string `json:"value"`              // Channel, mutex, group or callee: "p.results", "p.mu", "g", "job"
+	File     string `json:"file"`               // Source file
+	Line     int    `json:"line"`               // Line number
+	Code     string `json:"code"`               // Source line
+	Deferred bool   `json:"deferred,omitempty"` // Run by a defer statement
+}
+
+// ConcurrencyIssue is a likely concurrency mistake
+type ConcurrencyIssue struct {
+	Kind     string `json:"kind"`     // "lock-without-defer", "goroutine-without-context", "unbuffered-send-in-loop", "context-not-propagated"
+	Message  string `json:"message"`  // What is wrong
+	Function string `json:"function"` // Enclosing function ID
+	File     string `json:"file"`     // Source file
+	Line     int    `json:"line"`     // Line number
+	Code     string `json:"code"`     // Source line
+}
+
+// Violation is a dependency that breaks an architecture layering rule
+type Violation struct {
+	From      Symbol      `json:"from"`      // Symbol in the layer that may not have the dependency
+	FromLayer string      `json:"fromLayer"` // Layer of From
+	To        Symbol      `json:"to"`        // Symbol in the forbidden layer
+	ToLayer   string      `json:"toLayer"`   // Layer of To
+	Chain     []Reference `json:"chain"`     // References leading from From to To; intermediate symbols are in no layer
 }
 
 // Metrics represents code complexity metrics
 type Metrics struct {
-	LinesOfCode          int
-	LogicalLines         int
-	CyclomaticComplexity int
-	DependencyCount      int
-	DirectDeps           int
-	TransitiveDeps       int
-	ExternalPackages     []string
+	LinesOfCode          int      `json:"linesOfCode"`
+	LogicalLines         int      `json:"logicalLines"`
+	CyclomaticComplexity int      `json:"cyclomaticComplexity"`
+	DependencyCount      int      `json:"dependencyCount"`
+	DirectDeps           int      `json:"directDeps"`
+	TransitiveDeps       int      `json:"transitiveDeps"`
+	ExternalPackages     []string `json:"externalPackages,omitempty"`
 }
 
 // GitBlame represents git history for a line/symbol
 type GitBlame struct {
-	Commit  string    // Commit hash
-	Author  string    // Author email
-	Date    time.Time // Commit date
-	Message string    // Commit message
+	Commit  string    `json:"commit"`  // Commit hash
+	Author  string    `json:"author"`  // Author email
+	Date    time.Time `json:"date"`    // Commit date
+	Message string    `json:"message"` // Commit message
 }
 
 // InterfaceMapping represents an interface-to-implementation relationship
 type InterfaceMapping struct {
-	Interface       Symbol   // The interface definition
-	Implementations []Symbol // Concrete types implementing the interface
-	Constructor     *Symbol  // Constructor function (if found)
-	DIFramework     string   // "wire", "fx", "manual", or empty
+	Interface       Symbol   `json:"interface"`             // The interface definition
+	Implementations []Symbol `json:"implementations"`       // Concrete types implementing the interface
+	Constructor     *Symbol  `json:"constructor,omitempty"` // Constructor function (if found)
+	DIFramework     string   `json:"diFramework,omitempty"` // "wire", "fx", "manual", or empty
+}
+
+// Implementation is a concrete type that satisfies an interface
+type Implementation struct {
+	Interface Symbol `json:"interface"`         // The interface
+	Type      Symbol `json:"type"`              // The concrete type
+	Pointer   bool   `json:"pointer,omitempty"` // Only *Type has the interface's methods (pointer receivers)
+}
+
+// InterfaceGap is a concrete type that nearly implements an interface
+type InterfaceGap struct {
+	Interface Symbol      `json:"interface"` // The interface
+	Type      Symbol      `json:"type"`      // The concrete type falling short
+	Missing   []MethodGap `json:"missing"`   // Interface methods the type lacks or has with another signature
+}
+
+// MethodGap is an interface method a concrete type does not provide
+type MethodGap struct {
+	Method string `json:"method"`          // Wanted method, "Delete(ctx context.Context, id string) error"
+	Reason string `json:"reason"`          // "missing", "wrong-signature"
+	Found  string `json:"found,omitempty"` // For wrong-signature: the type's method
 }
 
 // DIBinding represents a dependency injection binding
 type DIBinding struct {
-	Provider     Symbol   // Provider function (e.g., NewAccountsService)
-	Product      Symbol   // What it provides (interface or concrete type)
-	Dependencies []Symbol // What it requires (constructor parameters)
-	Framework    string   // "wire", "fx", "manual"
-	Scope        string   // "singleton", "transient", "request", etc.
+	Provider     Symbol              `json:"provider"`            // Provider function (e.g., NewAccountsService)
+	Product      Symbol              `json:"product"`             // What it provides (interface or concrete type)
+	Dependencies []Symbol            `json:"dependencies"`        // What it requires (constructor parameters)
+	Framework    string              `json:"framework"`           // "wire", "fx", "dig", "do", "manual", or a registry's name
+	Scope        string              `json:"scope"`               // "singleton", "transient", "injector" (one per Wire injector call), "scoped" (dig child scope), "request" (built per HTTP request)
+	Init         string              `json:"init,omitempty"`      // When a container builds it: "lazy" on first use, "eager" at registration or start
+	Kind         string              `json:"kind,omitempty"`      // "provide" (default), "invoke", "decorate", "supply", "bind", "struct", "fields", "inject"
+	Module       string              `json:"module,omitempty"`    // Enclosing container module: fx.Module name or Wire provider set ("store.SQLSet")
+	Provides     []DIValue           `json:"provides,omitempty"`  // Every value put in the container, with its tags
+	Requires     []DIValue           `json:"requires,omitempty"`  // Every value taken from the container, with its tags
+	Hooks        []string            `json:"hooks,omitempty"`     // Lifecycle hooks: "OnStart", "OnStop" (fx.Lifecycle); "Close", "Shutdown", "Stop" (methods of a product); "cleanup" (Wire cleanup function)
+	Injectors    []string            `json:"injectors,omitempty"` // Wire injectors built with the binding, or the injector itself ("main.InitializeServer")
+	Apps         []string            `json:"apps,omitempty"`      // Fx, dig or do apps the binding is registered in, named after the function creating the container ("app.main")
+	Options      *ConstructorOptions `json:"options,omitempty"`   // How the provider is configured, beyond its dependencies
+}
+
+// ConstructorOptions describes how a constructor is configured: by
+// functional options passed variadically, or by a config struct
+type ConstructorOptions struct {
+	Constructor Symbol              `json:"constructor"`         // The configured constructor
+	Style       string              `json:"style"`               // "functional" (...Option) or "config" (a Config/Options struct parameter)
+	Type        Symbol              `json:"type"`                // The option type or config struct
+	Options     []ConstructorOption `json:"options"`             // Option functions returning the option type, or the config struct's fields
+	Defaults    []OptionDefault     `json:"defaults,omitempty"`  // Fields the constructor sets itself
+	CallSites   []OptionCall        `json:"callSites,omitempty"` // Calls of the constructor and the options they pass
+}
+
+// ConstructorOption is one way to configure a constructor
+type ConstructorOption struct {
+	Name   string   `json:"name"`             // Option function ("WithTimeout") or config field ("Timeout")
+	Symbol *Symbol  `json:"symbol,omitempty"` // The option function
+	Type   string   `json:"type,omitempty"`   // The config field's type
+	Fields []string `json:"fields,omitempty"` // Fields of the configured value an option function sets
+}
+
+// OptionDefault is a field a constructor sets before or instead of its options
+type OptionDefault struct {
+	Field string `json:"field"` // Field of the configured value
+	Value string `json:"value"` // Source of the value set ("30 * time.Second")
+}
+
+// OptionCall is a call of a constructor with the options it passes
+type OptionCall struct {
+	Function string   `json:"function"`          // Enclosing function ("Recv.Name" for methods)
+	File     string   `json:"file"`              // File path
+	Line     int      `json:"line"`              // Line number
+	Options  []string `json:"options,omitempty"` // Source of each option passed, or of each config field set ("Addr: \":8080\"")
+}
+
+// DIValue is a typed value in a DI container, optionally named or in a group
+type DIValue struct {
+	Type     string `json:"type"`               // Fully qualified Go type, "*example.com/app/db.Conn"; for groups, the element type
+	Name     string `json:"name,omitempty"`     // Named value (`name:"ro"`)
+	Group    string `json:"group,omitempty"`    // Value group (`group:"routes"`)
+	Optional bool   `json:"optional,omitempty"` // Dependency may be missing (`optional:"true"`)
+}
+
+// DIPackage records the DI frameworks a package uses
+type DIPackage struct {
+	Package    string   `json:"package"`    // Full package path
+	Frameworks []string `json:"frameworks"` // Frameworks detected in the package, "wire", "manual"
+}
+
+// DIGraph is a module's dependency injection configuration: the frameworks
+// it uses, per package, and their merged bindings
+type DIGraph struct {
+	Frameworks []string    `json:"frameworks"`         // Every framework detected, primary first
+	Packages   []DIPackage `json:"packages,omitempty"` // Frameworks used by each package
+	Bindings   []DIBinding `json:"bindings"`           // Bindings of all frameworks
+	Warnings   []string    `json:"warnings,omitempty"` // Configuration problems, e.g. an unreadable rules file
+}
+
+// DIIssue is a wiring problem found in a DI container's bindings
+type DIIssue struct {
+	Kind      string   `json:"kind"`                // "missing", "duplicate", "unused", "cycle"
+	Framework string   `json:"framework"`           // Framework of the container
+	Container string   `json:"container,omitempty"` // Wire injector or Fx, dig or do app the graph was built for ("main.InitializeServer", "app.main")
+	Value     DIValue  `json:"value"`               // Value missing, provided twice or never required; first value on a cycle
+	Providers []Symbol `json:"providers"`           // Providers at fault: both duplicates, the unused provider, or the one requiring a missing value
+	Chain     []DIStep `json:"chain"`               // Provider → dependency steps leading to the issue, from a root invoke or injector when one reaches it
+}
+
+// DIStep is one edge of a DI dependency chain: a provider requiring a value
+type DIStep struct {
+	Provider Symbol  `json:"provider"` // Binding's provider
+	Requires DIValue `json:"requires"` // Value it takes from the container
+}
+
+// DIObjectGraph is the object graph a DI container constructs for one root:
+// an invoke or Wire injector, and everything it transitively requires
+type DIObjectGraph struct {
+	Root      Symbol         `json:"root"`                // Invoke or injector building the graph
+	Framework string         `json:"framework"`           // Framework of the container
+	Container string         `json:"container,omitempty"` // Wire injector or Fx, dig or do app ("main.InitializeServer", "app.main")
+	Objects   []DIObject     `json:"objects"`             // Objects constructed, root first, in breadth-first order
+	Edges     []DIObjectEdge `json:"edges"`               // Parameters, from the object requiring a value to the one providing it
+}
+
+// DIObject is one provider in an object graph
+type DIObject struct {
+	ID       string    `json:"id"`                 // Provider ID, "#2" for a second registration; "<injector ID>#args" for injector arguments, "missing:<type>" for values nothing provides
+	Provider Symbol    `json:"provider"`           // Provider function, bound type or supplied value
+	Product  Symbol    `json:"product"`            // Main type it provides, when declared in the module
+	Kind     string    `json:"kind"`               // Binding kind ("provide", "invoke", "inject", ...), "argument" or "missing"
+	Scope    string    `json:"scope,omitempty"`    // "singleton", "transient", ...
+	Module   string    `json:"module,omitempty"`   // Enclosing container module
+	Provides []DIValue `json:"provides,omitempty"` // Values it puts in the container
+	Depth    int       `json:"depth"`              // Distance from the root
+}
+
+// DIObjectEdge is a parameter of an object, resolved to the object providing it
+type DIObjectEdge struct {
+	From           string  `json:"from"`                     // ID of the object requiring the value
+	To             string  `json:"to"`                       // ID of the object providing it
+	Value          DIValue `json:"value"`                    // Value passed
+	Implementation *Symbol `json:"implementation,omitempty"` // Concrete type behind an interface value, when known
 }
 
 // Extract represents the extraction result
 type Extract struct {
-	Target             Symbol             // The requested symbol
-	References         []Reference        // Included dependencies
-	External           []string           // External package references (pkg.Symbol format)
-	Callers            []Caller           // What calls this symbol
-	Metrics            *Metrics           // Optional metrics
-	GitHistory         []GitBlame         // Optional git history
-	Graph              string             // Dependency graph (mermaid or text format)
-	InterfaceMappings  []InterfaceMapping // Interface→Implementation mappings
-	DIBindings         []DIBinding        // Dependency injection bindings
-	DetectedDIFramework string            // "wire", "fx", "manual", or "none"
+	Target              Symbol              `json:"target"`                      // The requested symbol
+	References          []Reference         `json:"references"`                  // Included dependencies
+	External            []string            `json:"external,omitempty"`          // External package references (pkg.Symbol format)
+	Callers             []Caller            `json:"callers,omitempty"`           // What calls this symbol
+	Metrics             *Metrics            `json:"metrics,omitempty"`           // Optional metrics
+	GitHistory          []GitBlame          `json:"gitHistory,omitempty"`        // Optional git history
+	Graph               string              `json:"graph,omitempty"`             // Dependency graph (mermaid or text format)
+	InterfaceMappings   []InterfaceMapping  `json:"interfaceMappings,omitempty"` // Interface→Implementation mappings
+	InterfaceGaps       []InterfaceGap      `json:"interfaceGaps,omitempty"`     // Types that nearly implement an extracted interface
+	DIBindings          []DIBinding         `json:"diBindings,omitempty"`        // Dependency injection bindings
+	DetectedDIFramework string              `json:"detectedDIFramework"`         // Primary framework: "wire", "fx", "dig", "do", "manual", a registry's name, or "none"
+	DIFrameworks        []string            `json:"diFrameworks,omitempty"`      // Every DI framework the module uses, primary first
+	DIPackages          []DIPackage         `json:"diPackages,omitempty"`        // DI frameworks used by the extract's packages
+	Options             *ConstructorOptions `json:"options,omitempty"`           // How the target constructor is configured
+	Taints              []TaintPath         `json:"taints,omitempty"`            // Source→sink paths through the target (Options.Taint)
+	ErrorPaths          []ErrorPath         `json:"errorPaths,omitempty"`        // Errors the target function can return
+	Concurrency         *Concurrency        `json:"concurrency,omitempty"`       // Goroutines, channels, locks and contexts of the target and its references
+	Budget              *BudgetReport       `json:"budget,omitempty"`            // How the extract was trimmed to fit MaxTokens/MaxBytes
+}
+
+// BudgetReport records how an extract was trimmed to fit a size budget
+type BudgetReport struct {
+	MaxBytes        int      `json:"maxBytes"`                  // Effective byte budget
+	MaxTokens       int      `json:"maxTokens"`                 // Byte budget expressed in estimated tokens
+	Stubbed         []string `json:"stubbed,omitempty"`         // IDs of references reduced to signatures
+	Omitted         []string `json:"omitted,omitempty"`         // IDs of references dropped entirely
+	TargetTruncated bool     `json:"targetTruncated,omitempty"` // Target code was cut short
 }
 
 // Result is the final output
 type Result struct {
-	Extract  Extract  // Structured extract
-	Rendered string   // Formatted output (markdown/HTML)
-	Metadata Metadata // Extraction metadata
+	Extract  Extract  `json:"extract"`            // Structured extract
+	Rendered string   `json:"rendered,omitempty"` // Formatted output (markdown/HTML)
+	Metadata Metadata `json:"metadata"`           // Extraction metadata
 }
 
 // Metadata contains extraction information
 type Metadata struct {
-	ExtractedAt  time.Time
-	GoVersion    string
-	Module       string
-	ModuleVersion string
-	TotalSymbols int
-	TotalLines   int
-	Options      Options
+	ExtractedAt   time.Time `json:"extractedAt"`
+	GoVersion     string    `json:"goVersion,omitempty"`     // Module's go directive (toolchain version if absent)
+	Toolchain     string    `json:"toolchain,omitempty"`     // Go version go-scope was built with
+	ToolVersion   string    `json:"toolVersion,omitempty"`   // go-scope version and revision
+	Module        string    `json:"module,omitempty"`        // Module path of the target package
+	ModuleVersion string    `json:"moduleVersion,omitempty"` // Module version, "(devel)" for the main module
+	VCSRevision   string    `json:"vcsRevision,omitempty"`   // Commit of the extracted source tree
+	VCSModified   bool      `json:"vcsModified,omitempty"`   // Source tree has uncommitted changes
+	TotalSymbols  int       `json:"totalSymbols"`
+	TotalLines    int       `json:"totalLines"` // Lines of code included in the extract
+	Timings       Timings   `json:"timings"`
+	Options       Options   `json:"options"`
+	Warnings      []string  `json:"warnings,omitempty"` // Problems that left the extract incomplete, e.g. an unreadable rules file
 }
 
+// Timings records how long each extraction phase took (JSON: nanoseconds)
+type Timings struct {
+	Load       time.Duration `json:"load"`
+	Locate     time.Duration `json:"locate"`
+	Collect    time.Duration `json:"collect"`
+	Interfaces time.Duration `json:"interfaces"`
+	DI         time.Duration `json:"di"`
+	Analysis   time.Duration `json:"analysis"`
+	Total      time.Duration `json:"total"`
+}
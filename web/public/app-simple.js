@@ -130,12 +130,14 @@ setTimeout(() => {
             link.addEventListener('click', (e) => {
                 e.preventDefault();
                 e.stopPropagation();
+                const nodeId = link.getAttribute('data-node-id');
                 const nodeName = link.getAttribute('data-node-name');
-                console.log('Clicked:', nodeName);
+                console.log('Clicked:', nodeId);
 
-                // Find the symbol first
+                // Find the symbol first, by ID so same-named symbols stay distinct
                 const allSymbols = this.symbols || this.nodes || [];
-                const targetSymbol = allSymbols.find(n => n.name === nodeName);
+                const targetSymbol = allSymbols.find(n => n.id === nodeId) ||
+                    allSymbols.find(n => n.name === nodeName);
 
                 if (targetSymbol && targetSymbol.file) {
                     // Find the file node that contains this symbol
@@ -137,7 +137,17 @@ class GoScopeVisualizer {
 
         try {
             const text = await file.text();
-            this.data = JSON.parse(text);
+            const data = JSON.parse(text);
+
+            // DI object graphs from `go-scope di graph -format=json`
+            if (Array.isArray(data) && data.every(graph => graph.objects && graph.edges)) {
+                this.data = null;
+                this.diGraphs = data;
+                this.renderDIGraphs();
+                return;
+            }
+            this.diGraphs = null;
+            this.data = data;
 
             // Validate required fields
             if (!this.data.target) {
@@ -253,6 +263,180 @@ class GoScopeVisualizer {
         }
     }
 
+    // DI object graphs: one top-down layout per root, side by side, with
+    // objects in rows by distance from the root
+    renderDIGraphs() {
+        if (this.simulation) this.simulation.stop();
+        this.svg.select('g').selectAll('*').remove();
+        const g = this.svg.select('g');
+
+        const width = 190, height = 46, colGap = 30, rowGap = 70, margin = 40;
+        this.nodes = [];
+        this.links = [];
+        let offsetX = margin;
+
+        this.diGraphs.forEach((graph, gi) => {
+            const rows = new Map();
+            graph.objects.forEach(object => {
+                if (!rows.has(object.depth)) rows.set(object.depth, []);
+                rows.get(object.depth).push(object);
+            });
+            const widest = Math.max(...Array.from(rows.values()).map(row => row.length));
+
+            const byId = new Map();
+            rows.forEach((objects, depth) => {
+                // Center each row under the widest one
+                const rowOffset = (widest - objects.length) * (width + colGap) / 2;
+                objects.forEach((object, i) => {
+                    const node = {
+                        id: `${gi}:${object.id}`,
+                        name: object.kind === 'missing' ? 'missing' : object.provider.name,
+                        kind: object.kind,
+                        object,
+                        graph,
+                        x: offsetX + rowOffset + i * (width + colGap),
+                        y: margin + depth * (height + rowGap),
+                    };
+                    byId.set(object.id, node);
+                    this.nodes.push(node);
+                });
+            });
+
+            graph.edges.forEach(edge => {
+                const source = byId.get(edge.from);
+                const target = byId.get(edge.to);
+                if (source && target) this.links.push({ source, target, edge });
+            });
+            offsetX += widest * (width + colGap) + margin * 2;
+        });
+
+        g.append('defs').append('marker')
+            .attr('id', 'di-arrow')
+            .attr('viewBox', '0 -5 10 10')
+            .attr('refX', 10)
+            .attr('markerWidth', 6)
+            .attr('markerHeight', 6)
+            .attr('orient', 'auto')
+            .append('path')
+            .attr('d', 'M0,-5L10,0L0,5')
+            .attr('fill', 'var(--edge)');
+
+        // Parameters run from the bottom of the object requiring a value to
+        // the top of the one providing it
+        const link = g.append('g')
+            .attr('class', 'links')
+            .selectAll('path')
+            .data(this.links)
+            .join('path')
+            .attr('class', 'link di-link')
+            .attr('marker-end', 'url(#di-arrow)')
+            .attr('d', d => {
+                const sx = d.source.x + width / 2, sy = d.source.y + height;
+                const tx = d.target.x + width / 2, ty = d.target.y;
+                const my = (sy + ty) / 2;
+                return `M${sx},${sy} C${sx},${my} ${tx},${my} ${tx},${ty}`;
+            });
+        link.append('title').text(d => this.diEdgeLabel(d.edge));
+
+        const node = g.append('g')
+            .attr('class', 'nodes')
+            .selectAll('g')
+            .data(this.nodes)
+            .join('g')
+            .attr('class', d => `node di-node di-${d.kind}`)
+            .attr('transform', d => `translate(${d.x},${d.y})`)
+            .on('click', (event, d) => this.showDIObjectDetails(d));
+
+        node.append('rect')
+            .attr('width', width)
+            .attr('height', height)
+            .attr('rx', 6);
+
+        node.append('text')
+            .attr('x', width / 2)
+            .attr('y', 19)
+            .style('font-weight', 'bold')
+            .text(d => d.name);
+
+        node.append('text')
+            .attr('x', width / 2)
+            .attr('y', 36)
+            .style('display', this.config.showLabels ? 'block' : 'none')
+            .text(d => {
+                const provided = d.object.provides && d.object.provides[0];
+                const type = provided ? this.shortType(provided.type) : '';
+                return d.object.scope && d.kind !== 'missing' ? `${type} · ${d.object.scope}` : type;
+            });
+
+        node.append('title')
+            .text(d => `${d.object.id}\n${d.kind}`);
+
+        document.getElementById('stat-nodes').textContent = this.nodes.length;
+        document.getElementById('stat-edges').textContent = this.links.length;
+        document.getElementById('stat-depth').textContent =
+            Math.max(0, ...this.nodes.map(n => n.object.depth));
+        document.getElementById('stat-external').textContent = 0;
+
+        if (this.nodes.length > 0) {
+            this.showDIObjectDetails(this.nodes[0]);
+        }
+    }
+
+    showDIObjectDetails(node) {
+        const object = node.object;
+        const row = (label, value) => `<div class="detail-row">
+            <span class="detail-label">${label}:</span>
+            <span class="detail-value">${value}</span>
+        </div>`;
+
+        let html = '<div class="node-details">';
+        html += row('Object', `<strong>${this.escapeHtml(object.id)}</strong>`);
+        html += row('Kind', object.kind);
+        html += row('Container', this.escapeHtml(node.graph.container || node.graph.framework));
+        if (object.scope) html += row('Scope', object.scope);
+        if (object.module) html += row('Module', this.escapeHtml(object.module));
+        if (object.provider && object.provider.file) {
+            html += row('Source', this.createFileLink(object.provider.file, object.provider.line));
+        }
+        (object.provides || []).forEach(value => {
+            html += row('Provides', this.escapeHtml(this.diValueLabel(value)));
+        });
+
+        const params = this.links.filter(l => l.source === node);
+        params.forEach(l => {
+            html += row('Needs', `${this.escapeHtml(this.diEdgeLabel(l.edge))} ← ${this.escapeHtml(l.target.name)}`);
+        });
+        const users = this.links.filter(l => l.target === node);
+        users.forEach(l => {
+            html += row('Used by', this.escapeHtml(l.source.name));
+        });
+        html += '</div>';
+
+        document.getElementById('code-title').textContent = node.name;
+        document.getElementById('code-content').innerHTML = html;
+    }
+
+    diValueLabel(value) {
+        let label = this.shortType(value.type);
+        if (value.name) label += ` name:"${value.name}"`;
+        if (value.group) label += ` group:"${value.group}"`;
+        if (value.optional) label += ' (optional)';
+        return label;
+    }
+
+    diEdgeLabel(edge) {
+        let label = this.diValueLabel(edge.value);
+        if (edge.implementation) {
+            label += ` = ${this.shortType(edge.implementation.package + '.' + edge.implementation.name)}`;
+        }
+        return label;
+    }
+
+    // Drops import paths from a type, keeping package names
+    shortType(type) {
+        return type.replace(/(?:[\w.-]+\/)+/g, '');
+    }
+
     prepareData() {
         // Store original symbol data for reference
         this.symbols = [this.data.target, ...this.data.nodes];
@@ -301,10 +485,15 @@ class GoScopeVisualizer {
         // Create file-to-file edges from symbol dependencies
         const fileEdges = new Map();
 
+        // Edges reference fully qualified node IDs; extracts written before
+        // that carried bare names, so fall back to a name lookup for those
+        const symbolsById = new Map(this.symbols.map(s => [s.id, s]));
+        const findSymbol = key => symbolsById.get(key) || this.symbols.find(s => s.name === key);
+
         this.data.edges.forEach(edge => {
             // Find source and target symbols
-            const sourceSymbol = this.symbols.find(s => s.name === edge.from);
-            const targetSymbol = this.symbols.find(s => s.name === edge.to);
+            const sourceSymbol = findSymbol(edge.from);
+            const targetSymbol = findSymbol(edge.to);
 
             if (!sourceSymbol || !targetSymbol) return;
             if (!sourceSymbol.file || !targetSymbol.file) return;
@@ -324,7 +513,7 @@ class GoScopeVisualizer {
 
             const fileEdge = fileEdges.get(edgeKey);
             fileEdge.count++;
-            fileEdge.symbols.push({ from: edge.from, to: edge.to });
+            fileEdge.symbols.push({ from: sourceSymbol.name, to: targetSymbol.name });
         });
 
         // Convert to array
@@ -441,6 +630,20 @@ class GoScopeVisualizer {
                 <span class="detail-value">${node.kind}</span>
             </div>`;
 
+            if (node.role) {
+                html += `<div class="detail-row">
+                    <span class="detail-label">Role:</span>
+                    <span class="detail-value">${node.role}</span>
+                </div>`;
+            }
+
+            if (node.effects && node.effects.length > 0) {
+                html += `<div class="detail-row">
+                    <span class="detail-label">Effects:</span>
+                    <span class="detail-value">${this.effectBadges(node.effects)}</span>
+                </div>`;
+            }
+
             if (node.package) {
                 html += `<div class="detail-row">
                     <span class="detail-label">Package:</span>
@@ -489,10 +692,14 @@ class GoScopeVisualizer {
                     html += `<div class="node-doc">${this.escapeHtml(symbol.doc)}</div>`;
                 }
 
-                if (symbol.code) {
-                    html += `<h4>${symbol.name} (${symbol.kind})</h4>`;
-                    const highlightedCode = this.highlightCode(symbol.code, symbol.name, symbol.kind);
+                // Signature-mode nodes carry only their contract; name-only nodes neither
+                const code = symbol.code || symbol.signature;
+                if (code) {
+                    html += `<h4>${symbol.name} (${symbol.kind})${this.effectBadges(symbol.effects)}</h4>`;
+                    const highlightedCode = this.highlightCode(code, symbol.name, symbol.kind);
                     html += `<div class="code-block"><pre class="language-go"><code class="language-go">${highlightedCode}</code></pre></div>`;
+                } else if (symbol.render === 'name') {
+                    html += `<h4>${symbol.name} (${symbol.kind})${this.effectBadges(symbol.effects)}</h4>`;
                 }
             });
         } else {
@@ -694,6 +901,11 @@ class GoScopeVisualizer {
         return `${fileLink} ${copyButton}`;
     }
 
+    effectBadges(effects) {
+        if (!effects || effects.length === 0) return '';
+        return effects.map(effect => ` <span class="effect-badge effect-${effect}">${effect}</span>`).join('');
+    }
+
     highlightCode(code, symbolName, kind) {
         // DISABLED: app-simple.js now handles linking AFTER Prism
         // let result = this.linkifyIdentifiers(code);